@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryWait  = 200 * time.Millisecond
+	maxRetryWait      = 10 * time.Second
+)
+
+// HttpRequestDoer выполняет HTTP-запросы; реализуется *http.Client
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RequestEditorFn изменяет запрос перед отправкой, например добавляет заголовки
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Client типизированный клиент API тендеров по swagger.yaml. Повторяет запросы,
+// получившие 429 или 5xx, а также сетевые ошибки: GET — всегда, остальные
+// методы — только с заголовком Idempotency-Key (см. WithIdempotencyKey).
+// Ответ 429 означает, что запрос не выполнялся, поэтому он повторяется для любого метода.
+type Client struct {
+	// Server базовый URL API, например http://localhost:8080/api/v1
+	Server string
+
+	// Client выполняет запросы; по умолчанию http.DefaultClient
+	Client HttpRequestDoer
+
+	// RequestEditors применяются к каждому запросу
+	RequestEditors []RequestEditorFn
+
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// ClientOption настраивает Client
+type ClientOption func(*Client) error
+
+// NewClient создает клиент для API по адресу server
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	client := Client{
+		Server:     strings.TrimSuffix(server, "/"),
+		maxRetries: defaultMaxRetries,
+		retryWait:  defaultRetryWait,
+	}
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	if client.Client == nil {
+		client.Client = http.DefaultClient
+	}
+	return &client, nil
+}
+
+// WithHTTPClient задает HTTP-клиент
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn добавляет функцию, изменяющую каждый запрос
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// WithToken передает token в заголовке Authorization: Bearer
+func WithToken(token string) ClientOption {
+	return WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// WithRetries задает число повторов и начальную паузу между ними; пауза
+// удваивается с каждым повтором, заголовок Retry-After имеет приоритет.
+// maxRetries = 0 отключает повторы.
+func WithRetries(maxRetries int, wait time.Duration) ClientOption {
+	return func(c *Client) error {
+		if maxRetries < 0 || wait < 0 {
+			return fmt.Errorf("invalid retry settings: %d retries, %s wait", maxRetries, wait)
+		}
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+		return nil
+	}
+}
+
+// WithIdempotencyKey добавляет к запросу заголовок Idempotency-Key; запрос
+// с ключом безопасно повторять, сервер вернет сохраненный ответ
+func WithIdempotencyKey(key string) RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Idempotency-Key", key)
+		return nil
+	}
+}
+
+// Error ответ API с кодом 4xx или 5xx
+type Error struct {
+	StatusCode int
+	model.ErrorResponse
+}
+
+func (e *Error) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("api: %d %s", e.StatusCode, e.Reason)
+}
+
+// rawBody тело запроса, которое отправляется как есть, а не кодируется в JSON
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// do выполняет запрос с повторами и декодирует JSON-ответ в out (если out не nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}, reqEditors []RequestEditorFn) error {
+	var payload []byte
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		payload, contentType = b.data, b.contentType
+	default:
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, method, path, query, payload, contentType, reqEditors)
+		if err != nil {
+			return err
+		}
+		retryable := method == http.MethodGet || req.Header.Get("Idempotency-Key") != ""
+
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			if !retryable || attempt >= c.maxRetries || ctx.Err() != nil {
+				return err
+			}
+			if err := sleep(ctx, backoff(wait)); err != nil {
+				return err
+			}
+			wait *= 2
+			continue
+		}
+
+		if resp.StatusCode < http.StatusBadRequest {
+			err := decodeResponse(resp, out)
+			resp.Body.Close()
+			return err
+		}
+
+		apiErr := readError(resp)
+		resp.Body.Close()
+		retry := resp.StatusCode == http.StatusTooManyRequests ||
+			(retryable && resp.StatusCode >= http.StatusInternalServerError)
+		if !retry || attempt >= c.maxRetries {
+			return apiErr
+		}
+		delay := backoff(wait)
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = d
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, payload []byte, contentType string, reqEditors []RequestEditorFn) (*http.Request, error) {
+	u := c.Server + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, fn := range append(c.RequestEditors, reqEditors...) {
+		if err := fn(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// readError читает тело ошибки. Сервер отвечает ErrorResponse ({"reason": ...})
+// или ошибкой Echo ({"message": ...}); оба варианта сводятся к Reason.
+func readError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var body struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		apiErr.Reason = body.Reason
+		if apiErr.Reason == "" {
+			apiErr.Reason = body.Message
+		}
+	} else {
+		apiErr.Reason = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+// backoff пауза со случайным разбросом ±50%, чтобы клиенты не повторяли синхронно
+func backoff(wait time.Duration) time.Duration {
+	if wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int64N(int64(wait)))
+}
+
+// retryAfter разбирает Retry-After в секундах или в формате HTTP-даты
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return min(time.Duration(s)*time.Second, maxRetryWait), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return min(max(time.Until(t), 0), maxRetryWait), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
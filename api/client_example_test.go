@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	"go-tenders/api"
+	"go-tenders/logging"
+	"go-tenders/model"
+	"go-tenders/server"
+	"go-tenders/storage"
+)
+
+// exampleStorage хранилище в памяти с методами, нужными для проверки токена и создания тендера
+type exampleStorage struct {
+	storage.Storage
+}
+
+func (exampleStorage) IsOrganizationResponsible(_ context.Context, username, organizationId string) (bool, error) {
+	return username == "alice" && organizationId == "org-1", nil
+}
+
+func (exampleStorage) APITokenUsername(_ context.Context, token string) (string, error) {
+	if token != "tnd_alice" {
+		return "", sql.ErrNoRows
+	}
+	return "alice", nil
+}
+
+func (exampleStorage) CreateTender(_ context.Context, body model.TendersNewBody) (model.Tender, error) {
+	return model.Tender{
+		Id:             "tender-1",
+		Name:           body.Name,
+		Description:    body.Description,
+		ServiceType:    body.ServiceType,
+		OrganizationId: body.OrganizationId,
+		Status:         model.Created,
+		Version:        1,
+		CreatedAt:      time.Now().Format(time.RFC3339),
+	}, nil
+}
+
+func Example() {
+	logger, _ := logging.New(io.Discard, "error", logging.FormatJSON)
+	srv := httptest.NewServer(server.NewServer(exampleStorage{}, logger, nil).Handler())
+	defer srv.Close()
+
+	client, err := api.NewClient(srv.URL+"/api/v1", api.WithToken("tnd_alice"), api.WithRetries(2, 10*time.Millisecond))
+	if err != nil {
+		panic(err)
+	}
+	ctx := context.Background()
+
+	tender, err := client.CreateTender(ctx, model.TendersNewBody{
+		Name:            "Доставка товаров Казань - Москва",
+		Description:     "Нужно доставить оборудование",
+		ServiceType:     model.Delivery,
+		OrganizationId:  "org-1",
+		CreatorUsername: "alice",
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(tender.Id, tender.Status, tender.Version)
+
+	_, err = client.CreateTender(ctx, model.TendersNewBody{
+		Name:            "Чужой тендер",
+		Description:     "Пользователь не отвечает за организацию",
+		ServiceType:     model.Delivery,
+		OrganizationId:  "org-2",
+		CreatorUsername: "alice",
+	})
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		fmt.Println(apiErr.StatusCode, apiErr.Reason)
+	}
+	// Output:
+	// tender-1 Created 1
+	// 403 User is not responsible for the organization
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go-tenders/model"
+)
+
+// CheckServer проверка доступности сервера
+// (GET /ping)
+func (c *Client) CheckServer(ctx context.Context, reqEditors ...RequestEditorFn) error {
+	return c.do(ctx, http.MethodGet, "/ping", nil, nil, nil, reqEditors)
+}
+
+// GetTenders получение списка тендеров
+// (GET /tenders)
+func (c *Client) GetTenders(ctx context.Context, params *model.GetTendersParams, reqEditors ...RequestEditorFn) ([]model.Tender, error) {
+	query := url.Values{}
+	if params != nil {
+		addPage(query, params.Limit, params.Offset)
+		if params.ServiceType != nil {
+			for _, st := range *params.ServiceType {
+				query.Add("service_type", string(st))
+			}
+		}
+	}
+	var tenders []model.Tender
+	return tenders, c.do(ctx, http.MethodGet, "/tenders", query, nil, &tenders, reqEditors)
+}
+
+// GetUserTenders получить тендеры пользователя
+// (GET /tenders/my)
+func (c *Client) GetUserTenders(ctx context.Context, params *model.GetUserTendersParams, reqEditors ...RequestEditorFn) ([]model.Tender, error) {
+	query := url.Values{}
+	if params != nil {
+		addPage(query, params.Limit, params.Offset)
+		addOptional(query, "username", params.Username)
+	}
+	var tenders []model.Tender
+	return tenders, c.do(ctx, http.MethodGet, "/tenders/my", query, nil, &tenders, reqEditors)
+}
+
+// CreateTender создание нового тендера
+// (POST /tenders/new)
+func (c *Client) CreateTender(ctx context.Context, body model.CreateTenderJSONRequestBody, reqEditors ...RequestEditorFn) (model.Tender, error) {
+	var tender model.Tender
+	return tender, c.do(ctx, http.MethodPost, "/tenders/new", nil, body, &tender, reqEditors)
+}
+
+// ImportTenders массовое создание тендеров из файла CSV или JSONL; contentType —
+// text/csv или application/x-ndjson, если формат не задан в params
+// (POST /tenders/import)
+func (c *Client) ImportTenders(ctx context.Context, params *model.ImportTendersParams, contentType string, data []byte, reqEditors ...RequestEditorFn) (model.TenderImportReport, error) {
+	query := url.Values{}
+	if params != nil {
+		addOptional(query, "format", params.Format)
+		if params.DryRun != nil {
+			query.Set("dryRun", strconv.FormatBool(*params.DryRun))
+		}
+		if params.BatchSize != nil {
+			query.Set("batchSize", strconv.Itoa(int(*params.BatchSize)))
+		}
+	}
+	var report model.TenderImportReport
+	return report, c.do(ctx, http.MethodPost, "/tenders/import", query, rawBody{contentType: contentType, data: data}, &report, reqEditors)
+}
+
+// EditTender редактирование тендера
+// (PATCH /tenders/{tenderId}/edit)
+func (c *Client) EditTender(ctx context.Context, tenderId model.TenderId, params model.EditTenderParams, body model.EditTenderJSONRequestBody, reqEditors ...RequestEditorFn) (model.Tender, error) {
+	query := url.Values{"username": {params.Username}}
+	addOptional(query, "description", params.Description)
+	var tender model.Tender
+	return tender, c.do(ctx, http.MethodPatch, "/tenders/"+url.PathEscape(tenderId)+"/edit", query, body, &tender, reqEditors)
+}
+
+// RollbackTender откат версии тендера
+// (PUT /tenders/{tenderId}/rollback/{version})
+func (c *Client) RollbackTender(ctx context.Context, tenderId model.TenderId, version int32, params model.RollbackTenderParams, reqEditors ...RequestEditorFn) (model.Tender, error) {
+	query := url.Values{"username": {params.Username}}
+	path := "/tenders/" + url.PathEscape(tenderId) + "/rollback/" + strconv.Itoa(int(version))
+	var tender model.Tender
+	return tender, c.do(ctx, http.MethodPut, path, query, nil, &tender, reqEditors)
+}
+
+// GetTenderStatus получение текущего статуса тендера
+// (GET /tenders/{tenderId}/status)
+func (c *Client) GetTenderStatus(ctx context.Context, tenderId model.TenderId, params model.GetTenderStatusParams, reqEditors ...RequestEditorFn) (model.TenderStatus, error) {
+	query := url.Values{}
+	addOptional(query, "username", params.Username)
+	if params.Status != "" {
+		query.Set("status", string(params.Status))
+	}
+	var status model.TenderStatus
+	return status, c.do(ctx, http.MethodGet, "/tenders/"+url.PathEscape(tenderId)+"/status", query, nil, &status, reqEditors)
+}
+
+// UpdateTenderStatus изменение статуса тендера
+// (PUT /tenders/{tenderId}/status)
+func (c *Client) UpdateTenderStatus(ctx context.Context, tenderId model.TenderId, params model.UpdateTenderStatusParams, reqEditors ...RequestEditorFn) (model.Tender, error) {
+	query := url.Values{"status": {string(params.Status)}, "username": {params.Username}}
+	var tender model.Tender
+	return tender, c.do(ctx, http.MethodPut, "/tenders/"+url.PathEscape(tenderId)+"/status", query, nil, &tender, reqEditors)
+}
+
+// GetUserBids получение списка ваших предложений
+// (GET /bids/my)
+func (c *Client) GetUserBids(ctx context.Context, params *model.GetUserBidsParams, reqEditors ...RequestEditorFn) ([]model.Bid, error) {
+	query := url.Values{}
+	if params != nil {
+		addPage(query, params.Limit, params.Offset)
+		addOptional(query, "username", params.Username)
+	}
+	var bids []model.Bid
+	return bids, c.do(ctx, http.MethodGet, "/bids/my", query, nil, &bids, reqEditors)
+}
+
+// CreateBid создание нового предложения
+// (POST /bids/new)
+func (c *Client) CreateBid(ctx context.Context, body model.CreateBidJSONRequestBody, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPost, "/bids/new", nil, body, &bid, reqEditors)
+}
+
+// EditBid редактирование параметров предложения
+// (PATCH /bids/{bidId}/edit)
+func (c *Client) EditBid(ctx context.Context, bidId model.BidId, params model.EditBidParams, body model.EditBidJSONRequestBody, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	query := url.Values{"username": {params.Username}}
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPatch, "/bids/"+url.PathEscape(bidId)+"/edit", query, body, &bid, reqEditors)
+}
+
+// SubmitBidFeedback отправка отзыва по предложению
+// (PUT /bids/{bidId}/feedback)
+func (c *Client) SubmitBidFeedback(ctx context.Context, bidId model.BidId, params model.SubmitBidFeedbackParams, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	query := url.Values{"bidFeedback": {params.BidFeedback}, "username": {params.Username}}
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPut, "/bids/"+url.PathEscape(bidId)+"/feedback", query, nil, &bid, reqEditors)
+}
+
+// RollbackBid откат версии предложения
+// (PUT /bids/{bidId}/rollback/{version})
+func (c *Client) RollbackBid(ctx context.Context, bidId model.BidId, version int32, params model.RollbackBidParams, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	query := url.Values{"username": {params.Username}}
+	path := "/bids/" + url.PathEscape(bidId) + "/rollback/" + strconv.Itoa(int(version))
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPut, path, query, nil, &bid, reqEditors)
+}
+
+// GetBidStatus получение текущего статуса предложения
+// (GET /bids/{bidId}/status)
+func (c *Client) GetBidStatus(ctx context.Context, bidId model.BidId, params model.GetBidStatusParams, reqEditors ...RequestEditorFn) (model.BidStatus, error) {
+	query := url.Values{"username": {params.Username}}
+	var status model.BidStatus
+	return status, c.do(ctx, http.MethodGet, "/bids/"+url.PathEscape(bidId)+"/status", query, nil, &status, reqEditors)
+}
+
+// UpdateBidStatus изменение статуса предложения
+// (PUT /bids/{bidId}/status)
+func (c *Client) UpdateBidStatus(ctx context.Context, bidId model.BidId, params model.UpdateBidStatusParams, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	query := url.Values{"status": {string(params.Status)}, "username": {params.Username}}
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPut, "/bids/"+url.PathEscape(bidId)+"/status", query, nil, &bid, reqEditors)
+}
+
+// SubmitBidDecision отправка решения по предложению
+// (PUT /bids/{bidId}/submit_decision)
+func (c *Client) SubmitBidDecision(ctx context.Context, bidId model.BidId, params model.SubmitBidDecisionParams, reqEditors ...RequestEditorFn) (model.Bid, error) {
+	query := url.Values{"decision": {string(params.Decision)}, "username": {params.Username}}
+	var bid model.Bid
+	return bid, c.do(ctx, http.MethodPut, "/bids/"+url.PathEscape(bidId)+"/submit_decision", query, nil, &bid, reqEditors)
+}
+
+// GetBidsForTender получение списка предложений для тендера
+// (GET /bids/{tenderId}/list)
+func (c *Client) GetBidsForTender(ctx context.Context, tenderId model.TenderId, params model.GetBidsForTenderParams, reqEditors ...RequestEditorFn) ([]model.Bid, error) {
+	query := url.Values{"username": {params.Username}}
+	addPage(query, params.Limit, params.Offset)
+	var bids []model.Bid
+	return bids, c.do(ctx, http.MethodGet, "/bids/"+url.PathEscape(tenderId)+"/list", query, nil, &bids, reqEditors)
+}
+
+// GetBidReviews просмотр отзывов на прошлые предложения
+// (GET /bids/{tenderId}/reviews)
+func (c *Client) GetBidReviews(ctx context.Context, tenderId model.TenderId, params model.GetBidReviewsParams, reqEditors ...RequestEditorFn) ([]model.BidReview, error) {
+	query := url.Values{"authorUsername": {params.AuthorUsername}, "requesterUsername": {params.RequesterUsername}}
+	addPage(query, params.Limit, params.Offset)
+	var reviews []model.BidReview
+	return reviews, c.do(ctx, http.MethodGet, "/bids/"+url.PathEscape(tenderId)+"/reviews", query, nil, &reviews, reqEditors)
+}
+
+func addPage(query url.Values, limit, offset *int32) {
+	if limit != nil {
+		query.Set("limit", strconv.Itoa(int(*limit)))
+	}
+	if offset != nil {
+		query.Set("offset", strconv.Itoa(int(*offset)))
+	}
+}
+
+func addOptional(query url.Values, name string, value *string) {
+	if value != nil {
+		query.Set(name, *value)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-tenders/config"
+	"go-tenders/model"
+	"go-tenders/storage"
+)
+
+// adminStorage операции хранилища, которые выполняют служебные команды
+type adminStorage interface {
+	Migrate(ctx context.Context) ([]storage.Migration, error)
+	MigrationVersion(ctx context.Context) (version int, tracked bool, err error)
+	Seed(ctx context.Context) (storage.SeedResult, error)
+	CreateAPIToken(ctx context.Context, username, name string, ttl time.Duration) (model.APIToken, error)
+	ReindexSearch(ctx context.Context) error
+	PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	PurgeOutbox(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// adminFunc выполняет служебную команду с уже открытым хранилищем
+type adminFunc func(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error
+
+// adminCommand служебная команда сервиса. prepare объявляет флаги команды и
+// возвращает ее выполнение, которое читает флаги и позиционные аргументы fs.
+type adminCommand struct {
+	usage   string
+	summary string
+	args    int
+	prepare func(fs *flag.FlagSet) adminFunc
+}
+
+var adminCommands = map[string]adminCommand{
+	"migrate": {
+		usage:   "migrate",
+		summary: "apply pending schema migrations",
+		prepare: func(fs *flag.FlagSet) adminFunc { return migrate },
+	},
+	"seed": {
+		usage:   "seed",
+		summary: "load demo employees, organizations and tenders",
+		prepare: func(fs *flag.FlagSet) adminFunc { return seed },
+	},
+	"create-token": {
+		usage:   "create-token [--name NAME] [--ttl DURATION] <username>",
+		summary: "issue an API token for an employee",
+		args:    1,
+		prepare: func(fs *flag.FlagSet) adminFunc {
+			name := fs.String("name", "", "token description")
+			ttl := fs.Duration("ttl", 0, "token lifetime; 0 means no expiry")
+			return func(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error {
+				return createToken(ctx, store, out, fs.Arg(0), *name, *ttl)
+			}
+		},
+	},
+	"reindex-search": {
+		usage:   "reindex-search",
+		summary: "recompute full-text search vectors and rebuild their indexes",
+		prepare: func(fs *flag.FlagSet) adminFunc { return reindexSearch },
+	},
+	"purge-expired": {
+		usage:   "purge-expired",
+		summary: "delete expired idempotency keys and outbox events older than OUTBOX_RETENTION",
+		prepare: func(fs *flag.FlagSet) adminFunc { return purgeExpired },
+	},
+}
+
+// parseCommand разбирает аргументы служебной команды name до подключения к базе
+func parseCommand(name string, args []string) (adminFunc, error) {
+	cmd, ok := adminCommands[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q\n\n%s", name, commandsUsage())
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s [config flags] %s\n", filepath.Base(os.Args[0]), cmd.usage)
+		fs.PrintDefaults()
+	}
+	run := cmd.prepare(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != cmd.args {
+		fs.Usage()
+		return nil, fmt.Errorf("%s: expected %d argument(s), got %d", name, cmd.args, fs.NArg())
+	}
+	return run, nil
+}
+
+// commandsUsage список команд для сообщения об ошибке
+func commandsUsage() string {
+	names := make([]string, 0, len(adminCommands))
+	for name := range adminCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "usage: %s [config flags] [command]\n\ncommands:\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(&b, "  %-16s %s\n", "serve", "run the HTTP server (default)")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-16s %s\n", name, adminCommands[name].summary)
+	}
+	return b.String()
+}
+
+func migrate(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error {
+	applied, err := store.Migrate(ctx)
+	for _, m := range applied {
+		fmt.Fprintf(out, "applied %s\n", m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	version, _, err := store.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "schema version %d\n", version)
+	return nil
+}
+
+func seed(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error {
+	result, err := store.Seed(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "added %d employees, %d organizations, %d tenders\n",
+		result.Employees, result.Organizations, result.Tenders)
+	return nil
+}
+
+func createToken(ctx context.Context, store adminStorage, out io.Writer, username, name string, ttl time.Duration) error {
+	if ttl < 0 {
+		return errors.New("--ttl must not be negative")
+	}
+	token, err := store.CreateAPIToken(ctx, username, name, ttl)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("employee %q not found", username)
+	}
+	if err != nil {
+		return err
+	}
+	expires := "never"
+	if token.ExpiresAt != nil {
+		expires = token.ExpiresAt.Format(time.RFC3339)
+	}
+	fmt.Fprintf(out, "token id %s for %s, expires %s\n", token.Id, token.Username, expires)
+	// Токен не хранится в базе — показываем его один раз отдельной строкой для копирования
+	fmt.Fprintln(out, token.Token)
+	return nil
+}
+
+func reindexSearch(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error {
+	if err := store.ReindexSearch(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "search index rebuilt")
+	return nil
+}
+
+func purgeExpired(ctx context.Context, store adminStorage, cfg *config.Config, out io.Writer) error {
+	keys, err := store.PurgeExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return err
+	}
+	events, err := store.PurgeOutbox(ctx, cfg.OutboxRetention)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "deleted %d idempotency keys, %d outbox events\n", keys, events)
+	return nil
+}
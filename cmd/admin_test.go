@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-tenders/config"
+	"go-tenders/model"
+)
+
+// fakeAdminStorage токены и очистка в памяти; остальные методы не вызываются
+type fakeAdminStorage struct {
+	adminStorage
+	tokens    []model.APIToken
+	retention time.Duration
+	purgeErr  error
+}
+
+func (f *fakeAdminStorage) CreateAPIToken(_ context.Context, username, name string, ttl time.Duration) (model.APIToken, error) {
+	if username != "alice" {
+		return model.APIToken{}, sql.ErrNoRows
+	}
+	token := model.APIToken{Id: "token-1", Username: username, Name: name, Token: "tnd_secret"}
+	if ttl > 0 {
+		expiresAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	f.tokens = append(f.tokens, token)
+	return token, nil
+}
+
+func (f *fakeAdminStorage) PurgeExpiredIdempotencyKeys(context.Context) (int64, error) {
+	return 3, f.purgeErr
+}
+
+func (f *fakeAdminStorage) PurgeOutbox(_ context.Context, olderThan time.Duration) (int64, error) {
+	f.retention = olderThan
+	return 7, nil
+}
+
+// runCommand разбирает и выполняет служебную команду над store
+func runCommand(t *testing.T, store adminStorage, cfg *config.Config, args ...string) (string, error) {
+	t.Helper()
+	run, err := parseCommand(args[0], args[1:])
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	err = run(context.Background(), store, cfg, &out)
+	return out.String(), err
+}
+
+// create-token печатает токен отдельной строкой и передает флаги хранилищу
+func TestCreateToken(t *testing.T) {
+	store := &fakeAdminStorage{}
+
+	out, err := runCommand(t, store, &config.Config{}, "create-token", "--name", "ci", "--ttl", "24h", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "token id token-1 for alice, expires 2024-05-02T00:00:00Z\ntnd_secret\n"
+	if out != want {
+		t.Fatalf("output = %q, want %q", out, want)
+	}
+	if len(store.tokens) != 1 || store.tokens[0].Name != "ci" {
+		t.Fatalf("tokens = %+v", store.tokens)
+	}
+
+	out, err = runCommand(t, store, &config.Config{}, "create-token", "alice")
+	if err != nil || !strings.Contains(out, "expires never\n") {
+		t.Fatalf("token without ttl: %q, %v", out, err)
+	}
+
+	if _, err := runCommand(t, store, &config.Config{}, "create-token", "mallory"); err == nil || err.Error() != `employee "mallory" not found` {
+		t.Fatalf("unknown employee: error = %v", err)
+	}
+	if _, err := runCommand(t, store, &config.Config{}, "create-token", "--ttl", "-1h", "alice"); err == nil || !strings.Contains(err.Error(), "--ttl must not be negative") {
+		t.Fatalf("negative ttl: error = %v", err)
+	}
+	if len(store.tokens) != 2 {
+		t.Fatalf("issued %d tokens, want 2", len(store.tokens))
+	}
+}
+
+// purge-expired удаляет ключи идемпотентности и события старше OUTBOX_RETENTION
+func TestPurgeExpired(t *testing.T) {
+	store := &fakeAdminStorage{}
+	out, err := runCommand(t, store, &config.Config{OutboxRetention: 72 * time.Hour}, "purge-expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "deleted 3 idempotency keys, 7 outbox events\n" {
+		t.Fatalf("output = %q", out)
+	}
+	if store.retention != 72*time.Hour {
+		t.Fatalf("outbox purged with retention %v, want OUTBOX_RETENTION", store.retention)
+	}
+
+	store = &fakeAdminStorage{purgeErr: errors.New("db down")}
+	if _, err := runCommand(t, store, &config.Config{OutboxRetention: time.Hour}, "purge-expired"); err == nil || err.Error() != "db down" {
+		t.Fatalf("error = %v, want db down", err)
+	}
+	if store.retention != 0 {
+		t.Fatal("outbox purged after idempotency keys failed")
+	}
+}
+
+// Аргументы проверяются до подключения к базе
+func TestParseCommandErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"drop-all"}, `unknown command "drop-all"`},
+		{[]string{"create-token"}, "create-token: expected 1 argument(s), got 0"},
+		{[]string{"purge-expired", "now"}, "purge-expired: expected 0 argument(s), got 1"},
+		{[]string{"create-token", "--ttl", "soon", "alice"}, `invalid value "soon" for flag -ttl`},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := parseCommand(tt.args[0], tt.args[1:])
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
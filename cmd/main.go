@@ -1,50 +1,265 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
-	"go-tenders/api"
 	"go-tenders/config"
+	"go-tenders/digest"
+	"go-tenders/logging"
+	"go-tenders/metrics"
+	"go-tenders/notify"
+	"go-tenders/outbox"
 	"go-tenders/server"
 	"go-tenders/storage"
+	"go-tenders/tracing"
+	"go-tenders/webhook"
 
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	_ "github.com/lib/pq"
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run запускает сервис или служебную команду (см. adminCommands) и возвращает
+// код завершения процесса: 0 — штатная остановка по сигналу или успешная
+// команда, 1 — ошибка запуска, команды или аварийная остановка
+func run() int {
+	// Флаги настроек идут до команды: go-tenders [флаги] [команда [аргументы]]
+	settings, command := config.SplitArgs(os.Args[1:])
+	var admin adminFunc
+	if len(command) > 0 && command[0] != "serve" {
+		var err error
+		admin, err = parseCommand(command[0], command[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+	} else if len(command) > 1 {
+		log.Printf("serve: unexpected argument %q", command[1])
+		return 1
+	}
+
 	// Загружаем конфигурацию
-	cfg := config.LoadConfig() // Имплементация LoadConfig должна возвращать структуру с Host и Port
+	cfg, err := config.LoadConfig(settings)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		log.Printf("Failed to load config: %v", err)
+		return 1
+	}
+
+	logger, err := logging.New(os.Stdout, cfg.LogLevel, logging.Format(cfg.LogFormat))
+	if err != nil {
+		log.Printf("Failed to initialize logger: %v", err)
+		return 1
+	}
+	logger.Slog().Info("effective config", "config", cfg.Redacted())
+
+	// SIGINT и SIGTERM запускают штатную остановку
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName:  cfg.ServiceName,
+		Exporter:     tracing.Exporter(cfg.TracingExporter),
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		OTLPInsecure: cfg.TracingOTLPInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing: ", err)
+		return 1
+	}
 
 	// Инициализируем хранилище (Postgres)
-	dbURL := os.Getenv("DATABASE_URL")
-	store, err := storage.NewPostgresStorage(dbURL)
+	db, err := storage.OpenInstrumented("postgres", cfg.PostgresConn, metrics.QueryHook{}, tracing.QueryHook{})
+	if err != nil {
+		logger.Error("Failed to initialize storage: ", err)
+		return 1
+	}
+	db.SetMaxOpenConns(cfg.PostgresMaxOpenConns)
+	db.SetMaxIdleConns(cfg.PostgresMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.PostgresConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.PostgresConnMaxIdleTime)
+
+	// Недоступная при запуске база — ошибка конфигурации, а не временный сбой
+	pingCtx, cancelPing := context.WithTimeout(ctx, cfg.PostgresConnectTimeout)
+	err = db.PingContext(pingCtx)
+	cancelPing()
 	if err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+		logger.Error("Failed to connect to database: ", err)
+		db.Close()
+		return 1
+	}
+	if err := metrics.RegisterDBStats(db.DB); err != nil {
+		logger.Error("Failed to register db metrics: ", err)
+	}
+	store := storage.NewPostgresStorage(db)
+
+	if admin != nil {
+		return runAdmin(ctx, admin, store, cfg, logger, func() error {
+			return errors.Join(db.Close(), shutdownTracing(context.Background()))
+		})
+	}
+
+	// Фоновые задачи работают до отмены jobsCtx
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	if err := startJobs(jobsCtx, &jobs, store, logger, cfg); err != nil {
+		logger.Error("Failed to start background jobs: ", err)
+		cancelJobs()
+		db.Close()
+		return 1
+	}
+
+	// Регистрируется один раз на процесс: newEcho вызывается и при перезагрузке настроек
+	if err := metrics.RegisterBusinessGauges(store); err != nil {
+		logger.Error("Failed to register business metrics: ", err)
+	}
+	apiServer := server.NewServer(store, logger, cfg)
+
+	// SIGHUP и изменение файла конфигурации применяют безопасные настройки без перезапуска
+	watcher := config.NewWatcher(cfg, settings, logger)
+	watcher.Subscribe(func(c *config.Config) {
+		if err := logger.SetLevel(c.LogLevel); err != nil {
+			logger.Error("Failed to apply log level: ", err)
+		}
+		apiServer.SetConfig(c)
+	})
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		watcher.Run(jobsCtx)
+	}()
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- apiServer.Start(cfg.ServerAddress)
+	}()
+
+	code := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received, draining requests")
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server stopped: ", err)
+			code = 1
+		}
 	}
+	// Повторный сигнал завершает процесс немедленно
+	stop()
 
-	// Создаем сервер api.ServerInterface
-	apiServer := server.NewServer(store, nil, cfg)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
-	// Создаем Echo
-	e := echo.New()
+	if err := apiServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to drain requests: ", err)
+		code = 1
+	}
 
-	// Встроенный логгер Echo для HTTP запросов
-	e.Use(middleware.Logger())
+	cancelJobs()
+	if err := waitGroup(shutdownCtx, &jobs); err != nil {
+		logger.Error("Background jobs did not stop in time: ", err)
+		code = 1
+	}
 
-	// Восстановление после паники и возврат 500
-	e.Use(middleware.Recover())
+	if err := db.Close(); err != nil {
+		logger.Error("Failed to close database: ", err)
+		code = 1
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces: ", err)
+		code = 1
+	}
 
-	// Регистрируем API маршруты
-	api.RegisterHandlers(e, apiServer)
+	logger.Info("Server stopped")
+	return code
+}
 
-	// Формируем адрес из конфигурации
-	addr := cfg.Host + ":" + cfg.Port
-	log.Printf("Starting server at %s", addr)
+// runAdmin выполняет служебную команду и освобождает ресурсы через closeAll
+func runAdmin(ctx context.Context, admin adminFunc, store *storage.PostgresStorage, cfg *config.Config, logger *logging.Logger, closeAll func() error) int {
+	code := 0
+	if err := admin(ctx, store, cfg, os.Stdout); err != nil {
+		logger.Error("Command failed: ", err)
+		code = 1
+	}
+	if err := closeAll(); err != nil {
+		logger.Error("Failed to release resources: ", err)
+		code = 1
+	}
+	return code
+}
+
+// startJobs запускает доставку outbox, вебхуков, писем и сводок
+func startJobs(ctx context.Context, wg *sync.WaitGroup, store *storage.PostgresStorage, logger *logging.Logger, cfg *config.Config) error {
+	sinks := []outbox.Sink{webhook.NewSink(store)}
+	workers := []func(context.Context) error{
+		webhook.NewWorker(store, webhook.NewSender(nil), logger, outbox.DefaultConfig).Run,
+	}
+
+	renderer, err := digest.NewRenderer()
+	if err != nil {
+		return err
+	}
+	channels := []digest.Channel{digest.NewWebhookChannel(nil)}
+
+	// Без SMTP письма и сводки по почте не отправляются
+	if cfg.SMTPAddr != "" {
+		templates, err := notify.LoadTemplates()
+		if err != nil {
+			return err
+		}
+		transport := notify.NewSMTPTransport(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		sinks = append(sinks, notify.NewSink(store, templates))
+		workers = append(workers, notify.NewWorker(store, transport, logger, outbox.DefaultConfig).Run)
+		channels = append(channels, digest.NewEmailChannel(transport))
+	}
+	sinks = append(sinks, outbox.NewLogSink(logger))
+
+	digestCfg := digest.DefaultConfig
+	digestCfg.Period = cfg.DigestPeriod
+	digestCfg.CheckInterval = cfg.DigestCheckInterval
+
+	workers = append(workers,
+		outbox.NewRelay(store, logger, outbox.DefaultConfig, sinks...).Run,
+		digest.NewJob(store, renderer, logger, digestCfg, channels...).Run,
+	)
+	for _, run := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("Background job stopped: ", err)
+			}
+		}()
+	}
+	return nil
+}
 
-	// Запускаем сервер
-	if err := e.Start(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+// waitGroup ждет wg, но не дольше, чем позволяет ctx
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
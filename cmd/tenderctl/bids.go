@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"go-tenders/api"
+	"go-tenders/model"
+
+	"github.com/google/uuid"
+)
+
+var bidCommands = map[string]command{
+	"list":     bidsList,
+	"new":      bidsNew,
+	"status":   bidsStatus,
+	"decision": bidsDecision,
+	"feedback": bidsFeedback,
+	"rollback": bidsRollback,
+}
+
+// bidsList tenderctl bids list (--my | --tender <tenderId>) [--limit N] [--offset N]
+func bidsList(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids list", flag.ContinueOnError)
+	my := fs.Bool("my", false, "only bids created by the current user")
+	tenderId := fs.String("tender", "", "bids for the tender")
+	var page pageFlags
+	page.register(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *my == (*tenderId != "") {
+		return errors.New("bids list: pass exactly one of --my or --tender")
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	limit, offset := page.values()
+
+	var bids []model.Bid
+	var err error
+	if *my {
+		bids, err = c.client.GetUserBids(c.ctx, &model.GetUserBidsParams{Limit: limit, Offset: offset, Username: &c.username})
+	} else {
+		bids, err = c.client.GetBidsForTender(c.ctx, *tenderId, model.GetBidsForTenderParams{Username: c.username, Limit: limit, Offset: offset})
+	}
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bids...)
+}
+
+// bidsNew tenderctl bids new --tender-id T --organization-id O --name N --description D
+func bidsNew(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids new", flag.ContinueOnError)
+	var body model.BidsNewBody
+	fs.StringVar(&body.TenderId, "tender-id", "", "tender id")
+	fs.StringVar(&body.OrganizationId, "organization-id", "", "organization submitting the bid")
+	fs.StringVar(&body.Name, "name", "", "bid name")
+	fs.StringVar(&body.Description, "description", "", "bid description")
+	key := fs.String("idempotency-key", "", "Idempotency-Key; generated when empty")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	body.CreatorUsername = c.username
+	body.Status = model.BidStatusCreated
+
+	if *key == "" {
+		*key = uuid.NewString()
+	}
+	bid, err := c.client.CreateBid(c.ctx, body, api.WithIdempotencyKey(*key))
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bid)
+}
+
+// bidsStatus tenderctl bids status <bidId> [--set Created|Published|Canceled]
+func bidsStatus(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids status", flag.ContinueOnError)
+	set := fs.String("set", "", "new status")
+	positional, err := parseArgs(fs, args, "bidId")
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+
+	if *set == "" {
+		status, err := c.client.GetBidStatus(c.ctx, positional[0], model.GetBidStatusParams{Username: c.username})
+		if err != nil {
+			return err
+		}
+		return c.out.status(string(status))
+	}
+	bid, err := c.client.UpdateBidStatus(c.ctx, positional[0], model.UpdateBidStatusParams{
+		Status:   model.BidStatus(*set),
+		Username: c.username,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bid)
+}
+
+// bidsDecision tenderctl bids decision <bidId> Approved|Rejected
+func bidsDecision(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids decision", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "bidId", "decision")
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	bid, err := c.client.SubmitBidDecision(c.ctx, positional[0], model.SubmitBidDecisionParams{
+		Decision: model.BidDecision(positional[1]),
+		Username: c.username,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bid)
+}
+
+// bidsFeedback tenderctl bids feedback <bidId> <text>
+func bidsFeedback(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids feedback", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "bidId", "text")
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	bid, err := c.client.SubmitBidFeedback(c.ctx, positional[0], model.SubmitBidFeedbackParams{
+		BidFeedback: positional[1],
+		Username:    c.username,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bid)
+}
+
+// bidsRollback tenderctl bids rollback <bidId> <version>
+func bidsRollback(c *cli, args []string) error {
+	fs := flag.NewFlagSet("bids rollback", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "bidId", "version")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersion(positional[1])
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	bid, err := c.client.RollbackBid(c.ctx, positional[0], version, model.RollbackBidParams{Username: c.username})
+	if err != nil {
+		return err
+	}
+	return c.out.bids(bid)
+}
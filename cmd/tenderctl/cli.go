@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-tenders/api"
+)
+
+// cli клиент API и параметры, общие для команд tenders и bids
+type cli struct {
+	ctx      context.Context
+	client   *api.Client
+	username string
+	out      *printer
+}
+
+func newCLI(opts globalOptions, p *printer) (*cli, error) {
+	creds, err := resolveCredentials(opts)
+	if err != nil {
+		return nil, err
+	}
+	var clientOpts []api.ClientOption
+	if creds.Token != "" {
+		clientOpts = append(clientOpts, api.WithToken(creds.Token))
+	}
+	client, err := api.NewClient(creds.Server, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &cli{ctx: context.Background(), client: client, username: creds.Username, out: p}, nil
+}
+
+// requireUsername имя пользователя нужно командам, действующим от его имени
+func (c *cli) requireUsername() error {
+	if c.username == "" {
+		return errors.New("username is not configured, run tenderctl login or pass --username")
+	}
+	return nil
+}
+
+// command подкоманда tenders или bids
+type command func(c *cli, args []string) error
+
+// parseArgs разбирает флаги и проверяет число позиционных аргументов.
+// Флаги разрешены и после аргументов: tenderctl tenders edit <id> --name ...
+func parseArgs(fs *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != len(names) {
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: unexpected arguments %s", fs.Name(), strings.Join(positional, " "))
+		}
+		return nil, fmt.Errorf("%s: expected arguments <%s>", fs.Name(), strings.Join(names, "> <"))
+	}
+	return positional, nil
+}
+
+func parseVersion(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid version %q", s)
+	}
+	return int32(v), nil
+}
+
+// optional возвращает указатель на значение флага, если он был задан
+func optional(fs *flag.FlagSet, name, value string) *string {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	if !set {
+		return nil
+	}
+	return &value
+}
+
+// pageFlags флаги пагинации --limit и --offset
+type pageFlags struct {
+	limit, offset int
+}
+
+func (p *pageFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&p.limit, "limit", 0, "maximum number of items")
+	fs.IntVar(&p.offset, "offset", 0, "number of items to skip")
+}
+
+func (p *pageFlags) values() (limit, offset *int32) {
+	if p.limit > 0 {
+		l := int32(p.limit)
+		limit = &l
+	}
+	if p.offset > 0 {
+		o := int32(p.offset)
+		offset = &o
+	}
+	return limit, offset
+}
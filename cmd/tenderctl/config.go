@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// credentials содержимое конфигурационного файла tenderctl
+type credentials struct {
+	Server   string `json:"server"`
+	Username string `json:"username"`
+	Token    string `json:"token,omitempty"`
+}
+
+// defaultConfigPath путь к файлу: TENDERCTL_CONFIG или <UserConfigDir>/tenderctl/config.json
+func defaultConfigPath() string {
+	if p := os.Getenv("TENDERCTL_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "tenderctl.json"
+	}
+	return filepath.Join(dir, "tenderctl", "config.json")
+}
+
+func loadCredentials(path string) (credentials, error) {
+	var c credentials
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// saveCredentials записывает файл с правами 0600: в нем хранится токен
+func saveCredentials(path string, c credentials) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// resolveCredentials объединяет файл и глобальные флаги; флаги важнее
+func resolveCredentials(opts globalOptions) (credentials, error) {
+	c, err := loadCredentials(opts.configPath)
+	if err != nil {
+		return c, err
+	}
+	if opts.server != "" {
+		c.Server = opts.server
+	}
+	if opts.username != "" {
+		c.Username = opts.username
+	}
+	if opts.token != "" {
+		c.Token = opts.token
+	}
+	if c.Server == "" {
+		return c, errors.New("server is not configured, run tenderctl login or pass --server")
+	}
+	return c, nil
+}
+
+// login сохраняет адрес сервера и учетные данные в конфигурационный файл
+func login(opts globalOptions, args []string, out io.Writer) error {
+	c, err := loadCredentials(opts.configPath)
+	if err != nil {
+		return err
+	}
+	fset := flag.NewFlagSet("login", flag.ContinueOnError)
+	fset.StringVar(&c.Server, "server", c.Server, "API base URL, e.g. http://localhost:8080/api/v1")
+	fset.StringVar(&c.Username, "username", c.Username, "username")
+	fset.StringVar(&c.Token, "token", c.Token, "API token")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if c.Server == "" || c.Username == "" {
+		return errors.New("login requires --server and --username")
+	}
+	if err := saveCredentials(opts.configPath, c); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Saved credentials to", opts.configPath)
+	return nil
+}
@@ -0,0 +1,111 @@
+// tenderctl — клиент командной строки для API тендеров.
+//
+//	tenderctl login --server http://localhost:8080/api/v1 --username alice --token ...
+//	tenderctl tenders list --service-type Delivery
+//	tenderctl tenders new --name ... --description ... --service-type Delivery --organization-id ...
+//	tenderctl -o json bids list --tender <tenderId>
+//
+// Адрес сервера, имя пользователя и токен хранятся в конфигурационном файле
+// (см. tenderctl login) и могут быть переопределены флагами.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const usage = `Usage: tenderctl [global flags] <command> [flags] [args]
+
+Commands:
+  login                               save server address and credentials
+  tenders list [--my]                 list tenders
+  tenders new                         create a tender
+  tenders edit <tenderId>             edit a tender
+  tenders status <tenderId> [--set S] show or change tender status
+  tenders rollback <tenderId> <ver>   roll a tender back to a version
+  tenders import <file>               bulk-create tenders from CSV or JSONL
+  bids list (--my | --tender <id>)    list bids
+  bids new                            create a bid
+  bids status <bidId> [--set S]       show or change bid status
+  bids decision <bidId> <decision>    submit Approved or Rejected
+  bids feedback <bidId> <text>        leave feedback on a bid
+  bids rollback <bidId> <ver>         roll a bid back to a version
+
+Global flags:
+`
+
+// globalOptions флаги, общие для всех команд
+type globalOptions struct {
+	configPath string
+	output     string
+	server     string
+	username   string
+	token      string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "tenderctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	var opts globalOptions
+	fs := flag.NewFlagSet("tenderctl", flag.ContinueOnError)
+	fs.StringVar(&opts.configPath, "config", defaultConfigPath(), "credentials file")
+	fs.StringVar(&opts.output, "o", string(outputTable), "output format: table or json")
+	fs.StringVar(&opts.server, "server", "", "API base URL, overrides the config file")
+	fs.StringVar(&opts.username, "username", "", "username, overrides the config file")
+	fs.StringVar(&opts.token, "token", "", "API token, overrides the config file")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format := outputFormat(opts.output)
+	if format != outputTable && format != outputJSON {
+		return fmt.Errorf("unknown output format %q", opts.output)
+	}
+	p := &printer{w: out, format: format}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	switch rest[0] {
+	case "login":
+		return login(opts, rest[1:], out)
+	case "tenders", "bids":
+		if len(rest) < 2 {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+		cli, err := newCLI(opts, p)
+		if err != nil {
+			return err
+		}
+		commands := tenderCommands
+		if rest[0] == "bids" {
+			commands = bidCommands
+		}
+		cmd, ok := commands[rest[1]]
+		if !ok {
+			return fmt.Errorf("unknown command %q", strings.Join(rest[:2], " "))
+		}
+		return cmd(cli, rest[2:])
+	default:
+		return fmt.Errorf("unknown command %q", rest[0])
+	}
+}
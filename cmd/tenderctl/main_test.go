@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// apiRequest запрос, полученный тестовым сервером API
+type apiRequest struct {
+	Method, Path  string
+	Query         url.Values
+	Authorization string
+	ContentType   string
+	Body          string
+}
+
+// testAPI сервер API с заготовленными ответами; запоминает запросы
+type testAPI struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []apiRequest
+}
+
+var (
+	testTenders = []model.Tender{
+		{Id: "t-1", Name: "Road", ServiceType: model.Construction, Status: model.Published, Version: 2, OrganizationId: "org-1", CreatedAt: "2024-05-01T00:00:00Z"},
+		{Id: "t-2", Name: "Trucks", ServiceType: model.Delivery, Status: model.Created, Version: 1, OrganizationId: "org-1", CreatedAt: "2024-05-02T00:00:00Z"},
+	}
+	testBid    = model.Bid{Id: "b-1", Name: "Offer", Status: model.BidStatusApproved, TenderId: "t-1", AuthorType: model.Organization, AuthorId: "org-2", Version: 1, CreatedAt: "2024-05-03T00:00:00Z"}
+	testReport = model.TenderImportReport{BatchSize: 100, Total: 2, Created: 1, Failed: 1, Rows: []model.TenderImportRow{
+		{Row: 1, Status: model.TenderImportRowCreated, TenderId: "t-3"},
+		{Row: 2, Status: model.TenderImportRowFailed, Error: "name is required"},
+	}}
+)
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	api := &testAPI{}
+	responses := map[string]interface{}{
+		"GET /api/v1/tenders":                  testTenders,
+		"GET /api/v1/tenders/my":               testTenders[:1],
+		"PUT /api/v1/bids/b-1/submit_decision": testBid,
+		"POST /api/v1/tenders/import":          testReport,
+	}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		api.mu.Lock()
+		api.requests = append(api.requests, apiRequest{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Query:         r.URL.Query(),
+			Authorization: r.Header.Get("Authorization"),
+			ContentType:   r.Header.Get("Content-Type"),
+			Body:          string(body),
+		})
+		api.mu.Unlock()
+
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(model.ErrorResponse{Reason: "not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// last последний полученный запрос
+func (a *testAPI) last(t *testing.T) apiRequest {
+	t.Helper()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.requests) == 0 {
+		t.Fatal("no requests to the API")
+	}
+	return a.requests[len(a.requests)-1]
+}
+
+// runCLI запускает tenderctl и возвращает его вывод
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out strings.Builder
+	err := run(args, &out)
+	return out.String(), err
+}
+
+// loggedIn сохраняет учетные данные alice для сервера api и возвращает путь к файлу
+func loggedIn(t *testing.T, api *testAPI) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tenderctl", "config.json")
+	if _, err := runCLI(t, "--config", path, "login", "--server", api.URL+"/api/v1", "--username", "alice", "--token", "tnd_alice"); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tableRows строки таблицы, разбитые на колонки
+func tableRows(out string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	return rows
+}
+
+// login сохраняет файл с правами 0600, команды берут из него сервер,
+// пользователя и токен, а глобальные флаги их переопределяют
+func TestCredentialsFile(t *testing.T) {
+	api := newTestAPI(t)
+	path := loggedIn(t, api)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("credentials file mode %v, want 0600", info.Mode().Perm())
+	}
+	saved, err := loadCredentials(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != (credentials{Server: api.URL + "/api/v1", Username: "alice", Token: "tnd_alice"}) {
+		t.Fatalf("saved credentials = %+v", saved)
+	}
+
+	if _, err := runCLI(t, "--config", path, "tenders", "list", "--my"); err != nil {
+		t.Fatal(err)
+	}
+	if req := api.last(t); req.Authorization != "Bearer tnd_alice" || req.Query.Get("username") != "alice" {
+		t.Fatalf("request from file: authorization %q, username %q", req.Authorization, req.Query.Get("username"))
+	}
+
+	if _, err := runCLI(t, "--config", path, "--username", "bob", "--token", "tnd_bob", "tenders", "list", "--my"); err != nil {
+		t.Fatal(err)
+	}
+	if req := api.last(t); req.Authorization != "Bearer tnd_bob" || req.Query.Get("username") != "bob" {
+		t.Fatalf("flags do not override the file: authorization %q, username %q", req.Authorization, req.Query.Get("username"))
+	}
+
+	// Повторный login меняет только переданные значения
+	if _, err := runCLI(t, "--config", path, "login", "--username", "carol"); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := loadCredentials(path); saved.Username != "carol" || saved.Token != "tnd_alice" || saved.Server != api.URL+"/api/v1" {
+		t.Fatalf("credentials after second login = %+v", saved)
+	}
+}
+
+// Ошибки флагов и аргументов не доходят до API
+func TestFlagErrors(t *testing.T) {
+	api := newTestAPI(t)
+	path := loggedIn(t, api)
+	empty := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown output format", []string{"--config", path, "-o", "yaml", "tenders", "list"}, `unknown output format "yaml"`},
+		{"unknown command", []string{"--config", path, "tenders", "delete"}, `unknown command "tenders delete"`},
+		{"unknown flag", []string{"--config", path, "tenders", "list", "--all"}, "flag provided but not defined: -all"},
+		{"missing argument", []string{"--config", path, "bids", "decision", "b-1"}, "bids decision: expected arguments <bidId> <decision>"},
+		{"extra argument", []string{"--config", path, "tenders", "list", "t-1"}, "tenders list: unexpected arguments t-1"},
+		{"invalid version", []string{"--config", path, "tenders", "rollback", "t-1", "0"}, `invalid version "0"`},
+		{"both bid filters", []string{"--config", path, "bids", "list", "--my", "--tender", "t-1"}, "pass exactly one of --my or --tender"},
+		{"no server", []string{"--config", empty, "tenders", "list"}, "server is not configured"},
+		{"no username", []string{"--config", empty, "--server", api.URL + "/api/v1", "bids", "decision", "b-1", "Approved"}, "username is not configured"},
+		{"login without username", []string{"--config", empty, "login", "--server", api.URL}, "login requires --server and --username"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCLI(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if len(api.requests) != 0 {
+		t.Fatalf("invalid commands sent %d requests", len(api.requests))
+	}
+
+	if _, err := runCLI(t, "--config", path); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("no command: error = %v, want flag.ErrHelp", err)
+	}
+}
+
+// tenders list передает фильтры и печатает таблицу или JSON
+func TestTendersList(t *testing.T) {
+	api := newTestAPI(t)
+	path := loggedIn(t, api)
+
+	out, err := runCLI(t, "--config", path, "tenders", "list", "--service-type", "Construction, Delivery", "--limit", "2", "--offset", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.Method != http.MethodGet || req.Path != "/api/v1/tenders" {
+		t.Fatalf("request %s %s", req.Method, req.Path)
+	}
+	if got := req.Query["service_type"]; strings.Join(got, ",") != "Construction,Delivery" || req.Query.Get("limit") != "2" || req.Query.Get("offset") != "1" {
+		t.Fatalf("query = %v", req.Query)
+	}
+	want := [][]string{
+		{"ID", "NAME", "SERVICE", "STATUS", "VERSION", "ORGANIZATION", "CREATED"},
+		{"t-1", "Road", "Construction", "Published", "2", "org-1", "2024-05-01T00:00:00Z"},
+		{"t-2", "Trucks", "Delivery", "Created", "1", "org-1", "2024-05-02T00:00:00Z"},
+	}
+	if got := tableRows(out); !equalRows(got, want) {
+		t.Fatalf("table output:\n%s", out)
+	}
+
+	out, err = runCLI(t, "--config", path, "-o", "json", "tenders", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tenders []model.Tender
+	if err := json.Unmarshal([]byte(out), &tenders); err != nil {
+		t.Fatalf("json output %q: %v", out, err)
+	}
+	if len(tenders) != 2 || tenders[0] != testTenders[0] || tenders[1] != testTenders[1] {
+		t.Fatalf("json output = %+v", tenders)
+	}
+}
+
+// bids decision отправляет решение от имени пользователя; одно предложение
+// печатается в JSON объектом, а не массивом
+func TestBidsDecision(t *testing.T) {
+	api := newTestAPI(t)
+	path := loggedIn(t, api)
+
+	out, err := runCLI(t, "--config", path, "bids", "decision", "b-1", "Approved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.Method != http.MethodPut || req.Path != "/api/v1/bids/b-1/submit_decision" ||
+		req.Query.Get("decision") != "Approved" || req.Query.Get("username") != "alice" {
+		t.Fatalf("request %s %s?%s", req.Method, req.Path, req.Query.Encode())
+	}
+	want := [][]string{
+		{"ID", "NAME", "STATUS", "TENDER", "AUTHOR", "VERSION", "CREATED"},
+		{"b-1", "Offer", "Approved", "t-1", "Organization:org-2", "1", "2024-05-03T00:00:00Z"},
+	}
+	if got := tableRows(out); !equalRows(got, want) {
+		t.Fatalf("table output:\n%s", out)
+	}
+
+	out, err = runCLI(t, "--config", path, "-o", "json", "bids", "decision", "b-1", "Approved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bid model.Bid
+	if err := json.Unmarshal([]byte(out), &bid); err != nil {
+		t.Fatalf("json output %q: %v", out, err)
+	}
+	if bid != testBid {
+		t.Fatalf("json output = %+v", bid)
+	}
+
+	if _, err := runCLI(t, "--config", path, "bids", "decision", "b-404", "Rejected"); err == nil || !strings.Contains(err.Error(), "api: 404 not found") {
+		t.Fatalf("unknown bid: error = %v", err)
+	}
+}
+
+// tenders import отправляет файл в формате по расширению, печатает отчет и
+// возвращает ошибку, если часть строк не импортирована
+func TestTendersImport(t *testing.T) {
+	api := newTestAPI(t)
+	path := loggedIn(t, api)
+	file := filepath.Join(t.TempDir(), "tenders.jsonl")
+	data := `{"name":"Road"}` + "\n" + `{}` + "\n"
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "--config", path, "tenders", "import", file, "--batch-size", "0", "--dry-run")
+	if err == nil || err.Error() != "1 of 2 rows failed" {
+		t.Fatalf("error = %v, want failed rows", err)
+	}
+	req := api.last(t)
+	if req.Method != http.MethodPost || req.Path != "/api/v1/tenders/import" || req.Body != data {
+		t.Fatalf("request %s %s with body %q", req.Method, req.Path, req.Body)
+	}
+	if req.ContentType != "application/x-ndjson" || req.Query.Get("format") != "jsonl" ||
+		req.Query.Get("batchSize") != "0" || req.Query.Get("dryRun") != "true" {
+		t.Fatalf("content type %q, query %v", req.ContentType, req.Query)
+	}
+	want := [][]string{
+		{"ROW", "STATUS", "TENDER", "ERROR"},
+		{"1", "Created", "t-3"},
+		{"2", "Failed", "name", "is", "required"},
+		{"2", "rows:", "1", "created,", "1", "failed"},
+	}
+	if got := tableRows(out); !equalRows(got, want) {
+		t.Fatalf("table output:\n%s", out)
+	}
+
+	out, err = runCLI(t, "--config", path, "-o", "json", "tenders", "import", "--format", "csv", file)
+	if err == nil {
+		t.Fatal("failed rows are not reported as an error")
+	}
+	if req := api.last(t); req.ContentType != "text/csv" || req.Query.Get("format") != "csv" || req.Query.Has("batchSize") {
+		t.Fatalf("content type %q, query %v", req.ContentType, req.Query)
+	}
+	var report model.TenderImportReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("json output %q: %v", out, err)
+	}
+	if report.Total != 2 || report.Failed != 1 || len(report.Rows) != 2 || report.Rows[1].Error != "name is required" {
+		t.Fatalf("json output = %+v", report)
+	}
+
+	if _, err := runCLI(t, "--config", path, "tenders", "import", filepath.Join(t.TempDir(), "tenders.xml")); err == nil ||
+		!strings.Contains(err.Error(), "cannot detect format") {
+		t.Fatalf("unknown extension: error = %v", err)
+	}
+}
+
+func equalRows(got, want [][]string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if strings.Join(got[i], "\x00") != strings.Join(want[i], "\x00") {
+			return false
+		}
+	}
+	return true
+}
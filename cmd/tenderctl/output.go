@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"go-tenders/model"
+)
+
+// Defines values for outputFormat.
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+// outputFormat формат вывода результатов
+type outputFormat string
+
+// printer печатает результаты команд таблицей или JSON
+type printer struct {
+	w      io.Writer
+	format outputFormat
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) tenders(tenders ...model.Tender) error {
+	if p.format == outputJSON {
+		if len(tenders) == 1 {
+			return p.json(tenders[0])
+		}
+		return p.json(tenders)
+	}
+	rows := make([][]string, 0, len(tenders))
+	for _, t := range tenders {
+		rows = append(rows, []string{t.Id, t.Name, string(t.ServiceType), string(t.Status), fmt.Sprint(t.Version), t.OrganizationId, t.CreatedAt})
+	}
+	return p.table([]string{"ID", "NAME", "SERVICE", "STATUS", "VERSION", "ORGANIZATION", "CREATED"}, rows)
+}
+
+func (p *printer) bids(bids ...model.Bid) error {
+	if p.format == outputJSON {
+		if len(bids) == 1 {
+			return p.json(bids[0])
+		}
+		return p.json(bids)
+	}
+	rows := make([][]string, 0, len(bids))
+	for _, b := range bids {
+		rows = append(rows, []string{b.Id, b.Name, string(b.Status), b.TenderId, string(b.AuthorType) + ":" + b.AuthorId, fmt.Sprint(b.Version), b.CreatedAt})
+	}
+	return p.table([]string{"ID", "NAME", "STATUS", "TENDER", "AUTHOR", "VERSION", "CREATED"}, rows)
+}
+
+func (p *printer) status(status string) error {
+	if p.format == outputJSON {
+		return p.json(map[string]string{"status": status})
+	}
+	_, err := fmt.Fprintln(p.w, status)
+	return err
+}
+
+func (p *printer) importReport(report model.TenderImportReport) error {
+	if p.format == outputJSON {
+		return p.json(report)
+	}
+	rows := make([][]string, 0, len(report.Rows))
+	for _, r := range report.Rows {
+		rows = append(rows, []string{fmt.Sprint(r.Row), string(r.Status), r.TenderId, r.Error})
+	}
+	if err := p.table([]string{"ROW", "STATUS", "TENDER", "ERROR"}, rows); err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("%d rows: %d created, %d failed", report.Total, report.Created, report.Failed)
+	if report.DryRun {
+		summary += " (dry run, nothing saved)"
+	}
+	_, err := fmt.Fprintln(p.w, summary)
+	return err
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-tenders/api"
+	"go-tenders/model"
+
+	"github.com/google/uuid"
+)
+
+var tenderCommands = map[string]command{
+	"list":     tendersList,
+	"new":      tendersNew,
+	"edit":     tendersEdit,
+	"status":   tendersStatus,
+	"rollback": tendersRollback,
+	"import":   tendersImport,
+}
+
+// tendersList tenderctl tenders list [--my] [--service-type A,B] [--limit N] [--offset N]
+func tendersList(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders list", flag.ContinueOnError)
+	my := fs.Bool("my", false, "only tenders created by the current user")
+	serviceTypes := fs.String("service-type", "", "comma-separated service types")
+	var page pageFlags
+	page.register(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	limit, offset := page.values()
+
+	if *my {
+		if err := c.requireUsername(); err != nil {
+			return err
+		}
+		tenders, err := c.client.GetUserTenders(c.ctx, &model.GetUserTendersParams{Limit: limit, Offset: offset, Username: &c.username})
+		if err != nil {
+			return err
+		}
+		return c.out.tenders(tenders...)
+	}
+
+	params := &model.GetTendersParams{Limit: limit, Offset: offset}
+	if *serviceTypes != "" {
+		var types []model.TenderServiceType
+		for _, st := range strings.Split(*serviceTypes, ",") {
+			types = append(types, model.TenderServiceType(strings.TrimSpace(st)))
+		}
+		params.ServiceType = &types
+	}
+	tenders, err := c.client.GetTenders(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.out.tenders(tenders...)
+}
+
+// tendersNew tenderctl tenders new --name N --description D --service-type T --organization-id O
+func tendersNew(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders new", flag.ContinueOnError)
+	var body model.TendersNewBody
+	fs.StringVar(&body.Name, "name", "", "tender name")
+	fs.StringVar(&body.Description, "description", "", "tender description")
+	serviceType := fs.String("service-type", "", "Construction, Delivery or Manufacture")
+	fs.StringVar(&body.OrganizationId, "organization-id", "", "organization id")
+	key := fs.String("idempotency-key", "", "Idempotency-Key; generated when empty")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	body.ServiceType = model.TenderServiceType(*serviceType)
+	body.CreatorUsername = c.username
+	body.Status = model.Created
+
+	// Ключ делает безопасными повторы клиента после таймаута
+	if *key == "" {
+		*key = uuid.NewString()
+	}
+	tender, err := c.client.CreateTender(c.ctx, body, api.WithIdempotencyKey(*key))
+	if err != nil {
+		return err
+	}
+	return c.out.tenders(tender)
+}
+
+// tendersEdit tenderctl tenders edit <tenderId> [--name N] [--description D] [--service-type T]
+func tendersEdit(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders edit", flag.ContinueOnError)
+	name := fs.String("name", "", "new name")
+	description := fs.String("description", "", "new description")
+	serviceType := fs.String("service-type", "", "new service type")
+	positional, err := parseArgs(fs, args, "tenderId")
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+
+	body := model.TenderIdEditBody{
+		Name:        optional(fs, "name", *name),
+		Description: optional(fs, "description", *description),
+	}
+	if st := optional(fs, "service-type", *serviceType); st != nil {
+		t := model.TenderServiceType(*st)
+		body.ServiceType = &t
+	}
+	tender, err := c.client.EditTender(c.ctx, positional[0], model.EditTenderParams{Username: c.username}, body)
+	if err != nil {
+		return err
+	}
+	return c.out.tenders(tender)
+}
+
+// tendersStatus tenderctl tenders status <tenderId> [--set Created|Published|Closed]
+func tendersStatus(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders status", flag.ContinueOnError)
+	set := fs.String("set", "", "new status: Created, Published or Closed")
+	positional, err := parseArgs(fs, args, "tenderId")
+	if err != nil {
+		return err
+	}
+
+	if *set == "" {
+		params := model.GetTenderStatusParams{}
+		if c.username != "" {
+			params.Username = &c.username
+		}
+		status, err := c.client.GetTenderStatus(c.ctx, positional[0], params)
+		if err != nil {
+			return err
+		}
+		return c.out.status(string(status))
+	}
+
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	tender, err := c.client.UpdateTenderStatus(c.ctx, positional[0], model.UpdateTenderStatusParams{
+		Status:   model.TenderStatus(*set),
+		Username: c.username,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.tenders(tender)
+}
+
+// tendersRollback tenderctl tenders rollback <tenderId> <version>
+func tendersRollback(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders rollback", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "tenderId", "version")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersion(positional[1])
+	if err != nil {
+		return err
+	}
+	if err := c.requireUsername(); err != nil {
+		return err
+	}
+	tender, err := c.client.RollbackTender(c.ctx, positional[0], version, model.RollbackTenderParams{Username: c.username})
+	if err != nil {
+		return err
+	}
+	return c.out.tenders(tender)
+}
+
+// tendersImport tenderctl tenders import <file|-> [--format csv|jsonl] [--dry-run] [--batch-size N]
+func tendersImport(c *cli, args []string) error {
+	fs := flag.NewFlagSet("tenders import", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or jsonl; detected from the file extension when empty")
+	dryRun := fs.Bool("dry-run", false, "validate the file without saving tenders")
+	batchSize := fs.Int("batch-size", -1, "save rows in batches of N, each in its own transaction; 0 saves up to 500 rows in one transaction; server default 100 when unset")
+	positional, err := parseArgs(fs, args, "file")
+	if err != nil {
+		return err
+	}
+
+	path := positional[0]
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = "csv"
+		case ".jsonl", ".ndjson":
+			*format = "jsonl"
+		default:
+			return fmt.Errorf("cannot detect format of %q, pass --format csv or --format jsonl", path)
+		}
+	}
+	var data []byte
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	contentType := "text/csv"
+	if *format == "jsonl" {
+		contentType = "application/x-ndjson"
+	}
+	params := &model.ImportTendersParams{Format: format, DryRun: dryRun}
+	if *batchSize >= 0 {
+		size := int32(*batchSize)
+		params.BatchSize = &size
+	}
+	report, err := c.client.ImportTenders(c.ctx, params, contentType, data)
+	if err != nil {
+		return err
+	}
+	if err := c.out.importReport(report); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+	}
+	return nil
+}
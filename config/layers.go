@@ -0,0 +1,332 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Источники настроек в порядке возрастания приоритета: значения по умолчанию,
+// файл конфигурации, переменные окружения (и .env), флаги командной строки.
+//
+// Ключи файла — имена переменных окружения в любом регистре; вложенные
+// секции склеиваются через «_», так что
+//
+//	postgres:
+//	  host: db
+//
+// равносильно POSTGRES_HOST=db. Флаг каждой настройки — имя переменной в нижнем
+// регистре через дефис: --log-level, --postgres-host, --config-file.
+
+// settingField поле Config, задаваемое настройкой
+type settingField struct {
+	key    string // имя переменной окружения
+	index  int
+	kind   reflect.Type
+	reload bool // можно менять без перезапуска
+}
+
+var settingFields = func() []settingField {
+	t := reflect.TypeOf(Config{})
+	fields := make([]settingField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("envconfig")
+		if key == "" {
+			continue
+		}
+		fields = append(fields, settingField{key: key, index: i, kind: f.Type, reload: f.Tag.Get("reload") == "true"})
+	}
+	return fields
+}()
+
+func findSettingField(key string) (settingField, bool) {
+	for _, f := range settingFields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return settingField{}, false
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setSetting записывает строковое значение настройки в поле cfg
+func setSetting(cfg *Config, f settingField, value string) error {
+	v := reflect.ValueOf(cfg).Elem().Field(f.index)
+	if err := parseSetting(v, value); err != nil {
+		return fmt.Errorf("%s: %w", f.key, err)
+	}
+	return nil
+}
+
+func parseSetting(v reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range splitList(value) {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := parseSetting(elem, item); err != nil {
+				return err
+			}
+			items = reflect.Append(items, elem)
+		}
+		v.Set(items)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(value) {
+			k, val, ok := strings.Cut(item, ":")
+			if !ok {
+				return fmt.Errorf("invalid map item %q, expected key:value", item)
+			}
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := parseSetting(key, k); err != nil {
+				return err
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := parseSetting(elem, val); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// readConfigFile читает YAML- или TOML-файл (по расширению) и возвращает
+// значения настроек по именам переменных окружения
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfig("", doc, values); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	for key := range values {
+		if _, ok := findSettingField(key); !ok {
+			return nil, fmt.Errorf("config file %s: unknown setting %q", path, key)
+		}
+	}
+	return values, nil
+}
+
+// flattenConfig раскладывает вложенные секции в плоские ключи. Секция,
+// совпадающая с настройкой-словарем (например FEATURE_FLAGS), сворачивается
+// в строку key:value,...
+func flattenConfig(prefix string, node interface{}, out map[string]string) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if f, ok := findSettingField(prefix); ok && f.kind.Kind() == reflect.Map {
+			keys := make([]string, 0, len(n))
+			for k := range n {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			items := make([]string, 0, len(n))
+			for _, k := range keys {
+				items = append(items, fmt.Sprintf("%s:%v", k, n[k]))
+			}
+			out[prefix] = strings.Join(items, ",")
+			return nil
+		}
+		for k, v := range n {
+			key := strings.ToUpper(k)
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			if err := flattenConfig(key, v, out); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items := make([]string, 0, len(n))
+		for _, v := range n {
+			items = append(items, fmt.Sprint(v))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+	default:
+		if prefix == "" {
+			return fmt.Errorf("expected a mapping at the top level")
+		}
+		out[prefix] = fmt.Sprint(n)
+	}
+	return nil
+}
+
+// settingFlag значение флага; запоминает, был ли флаг указан
+type settingFlag struct {
+	value   string
+	set     bool
+	boolean bool
+}
+
+func (f *settingFlag) String() string { return f.value }
+
+func (f *settingFlag) Set(v string) error {
+	f.value, f.set = v, true
+	return nil
+}
+
+func (f *settingFlag) IsBoolFlag() bool { return f.boolean }
+
+// newFlagSet создает набор флагов всех настроек
+func newFlagSet() (*flag.FlagSet, map[string]*settingFlag) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flags := make(map[string]*settingFlag, len(settingFields))
+	for _, f := range settingFields {
+		sf := &settingFlag{boolean: f.kind.Kind() == reflect.Bool}
+		flags[f.key] = sf
+		fs.Var(sf, flagName(f.key), "overrides "+f.key)
+	}
+	return fs, flags
+}
+
+// parseFlags разбирает флаги командной строки и возвращает указанные значения
+// по именам переменных окружения
+func parseFlags(args []string) (map[string]string, error) {
+	fs, flags := newFlagSet()
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	values := make(map[string]string)
+	for key, sf := range flags {
+		if sf.set {
+			values[key] = sf.value
+		}
+	}
+	return values, nil
+}
+
+// SplitArgs делит аргументы вида [флаги настроек] [команда [аргументы команды]]
+// на флаги для LoadConfig и команду с ее аргументами. Ошибочные флаги целиком
+// остаются в settings, чтобы о них сообщил LoadConfig.
+func SplitArgs(args []string) (settings, command []string) {
+	fs, _ := newFlagSet()
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return args, nil
+	}
+	command = fs.Args()
+	return args[:len(args)-len(command)], command
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// dotenvKeys переменные окружения, заданные из .env, а не окружением процесса
+var (
+	dotenvMu   sync.Mutex
+	dotenvKeys = map[string]bool{}
+)
+
+// loadDotEnv переносит переменные из файла .env в окружение. Переменные
+// окружения процесса имеют приоритет, а заданные ранее из .env перезаписываются
+// и удаляются вместе со строкой файла, чтобы перезагрузка видела правки .env
+// (godotenv.Load не меняет уже заданные переменные).
+func loadDotEnv(path string) error {
+	values, err := godotenv.Read(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, inEnv := os.LookupEnv(key); inEnv && !dotenvKeys[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+		dotenvKeys[key] = true
+	}
+	return err
+}
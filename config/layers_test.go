@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Приоритет: значения по умолчанию < файл < окружение < флаги
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+postgres:
+  host: file-db
+  database: tenders
+log:
+  level: warn
+  format: text
+server_port: 9000
+feature_flags:
+  search: true
+  export: false
+audit_users: [alice, bob]
+`)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("POSTGRES_DATABASE", "env-db")
+
+	cfg, err := LoadConfig([]string{"--config-file", path, "--log-level", "debug", "--server-port=9100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"LOG_LEVEL from flag over env and file", cfg.LogLevel, "debug"},
+		{"LOG_FORMAT from file", cfg.LogFormat, "text"},
+		{"POSTGRES_DATABASE from env over file", cfg.PostgresDatabase, "env-db"},
+		{"POSTGRES_HOST from nested file section", cfg.PostgresHost, "file-db"},
+		{"SERVER_PORT from flag", cfg.ServerPort, 9100},
+		{"SHUTDOWN_TIMEOUT default", cfg.ShutdownTimeout.String(), "15s"},
+		{"FEATURE_FLAGS map section", cfg.Feature("search") && !cfg.Feature("export"), true},
+		{"AUDIT_USERS list", strings.Join(cfg.AuditUsers, ","), "alice,bob"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadConfigTOML(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+rate_limit_enabled = false
+
+[postgres]
+host = "toml-db"
+`)
+	cfg, err := LoadConfig([]string{"--config-file=" + path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PostgresHost != "toml-db" || cfg.RateLimitEnabled {
+		t.Fatalf("got host %q, rate limit %v", cfg.PostgresHost, cfg.RateLimitEnabled)
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"unknown setting", "config.yaml", "postgres:\n  hots: db\n", `unknown setting "POSTGRES_HOTS"`},
+		{"unsupported format", "config.json", `{"postgres_host": "db"}`, "unsupported format"},
+		{"invalid value", "config.yaml", "postgres_host: db\nserver_port: eighty\n", "SERVER_PORT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+			_, err := LoadConfig([]string{"--config-file", path})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadConfig() = %v, want error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	settings, command := SplitArgs([]string{"--log-level", "debug", "--rate-limit-enabled", "migrate", "--dry-run"})
+	if strings.Join(settings, " ") != "--log-level debug --rate-limit-enabled" {
+		t.Errorf("settings = %v", settings)
+	}
+	if strings.Join(command, " ") != "migrate --dry-run" {
+		t.Errorf("command = %v", command)
+	}
+}
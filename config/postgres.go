@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPostgresPort = 5432
+	redactedSecret      = "xxxxx"
+)
+
+// postgresParts части строки подключения, собираемые из переменных окружения
+type postgresParts struct {
+	host     string
+	port     int
+	database string
+	username string
+	password string
+	params   url.Values
+}
+
+// buildPostgresConn собирает URL подключения из POSTGRES_HOST/PORT/DATABASE/
+// USERNAME/PASSWORD; незаданные части берутся из POSTGRES_JDBC_URL. Если ни
+// хост, ни база не известны, возвращает пустую строку.
+func (c *Config) buildPostgresConn() (string, error) {
+	parts := postgresParts{params: url.Values{}}
+	if c.PostgresJDBCURL != "" {
+		jdbc, err := parseJDBCURL(c.PostgresJDBCURL)
+		if err != nil {
+			return "", fmt.Errorf("POSTGRES_JDBC_URL: %w", err)
+		}
+		parts = jdbc
+	}
+
+	if c.PostgresHost != "" {
+		parts.host = c.PostgresHost
+	}
+	if c.PostgresPort != 0 {
+		parts.port = c.PostgresPort
+	}
+	if c.PostgresDatabase != "" {
+		parts.database = c.PostgresDatabase
+	}
+	if c.PostgresUsername != "" {
+		parts.username = c.PostgresUsername
+	}
+	if c.PostgresPassword != "" {
+		parts.password = c.PostgresPassword
+	}
+
+	if parts.host == "" && parts.database == "" {
+		return "", nil
+	}
+	if parts.host == "" {
+		parts.host = "localhost"
+	}
+	if parts.port == 0 {
+		parts.port = defaultPostgresPort
+	}
+	if c.PostgresConnectTimeout > 0 && parts.params.Get("connect_timeout") == "" {
+		parts.params.Set("connect_timeout", strconv.Itoa(int(c.PostgresConnectTimeout.Seconds())))
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		Host:     net.JoinHostPort(parts.host, strconv.Itoa(parts.port)),
+		Path:     "/" + parts.database,
+		RawQuery: parts.params.Encode(),
+	}
+	switch {
+	case parts.username != "" && parts.password != "":
+		u.User = url.UserPassword(parts.username, parts.password)
+	case parts.username != "":
+		u.User = url.User(parts.username)
+	}
+	return u.String(), nil
+}
+
+// parseJDBCURL разбирает jdbc:postgresql://host:port/db?user=..&password=..
+// Параметры user и password становятся учетными данными, остальные
+// передаются драйверу как есть.
+func parseJDBCURL(raw string) (postgresParts, error) {
+	rest, ok := strings.CutPrefix(raw, "jdbc:postgresql:")
+	if !ok || !strings.HasPrefix(rest, "//") {
+		return postgresParts{}, fmt.Errorf("expected jdbc:postgresql://host:port/database")
+	}
+	u, err := url.Parse("postgres:" + rest)
+	if err != nil {
+		return postgresParts{}, err
+	}
+
+	parts := postgresParts{
+		host:     u.Hostname(),
+		database: strings.TrimPrefix(u.Path, "/"),
+		params:   u.Query(),
+	}
+	if port := u.Port(); port != "" {
+		parts.port, err = strconv.Atoi(port)
+		if err != nil {
+			return postgresParts{}, fmt.Errorf("invalid port %q", port)
+		}
+	}
+	parts.username = parts.params.Get("user")
+	parts.password = parts.params.Get("password")
+	parts.params.Del("user")
+	parts.params.Del("password")
+	return parts, nil
+}
+
+// validatePostgresConn проверяет URL подключения; строки вида key=value
+// драйвер разбирает сам
+func validatePostgresConn(conn string) error {
+	if !strings.Contains(conn, "://") {
+		return nil
+	}
+	u, err := url.Parse(conn)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is empty")
+	}
+	return nil
+}
+
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// redactPostgresConn скрывает пароль в URL, JDBC- или key=value строке подключения
+func redactPostgresConn(conn string) string {
+	if conn == "" {
+		return ""
+	}
+	rest, jdbc := strings.CutPrefix(conn, "jdbc:")
+	if !strings.Contains(rest, "://") {
+		return dsnPassword.ReplaceAllString(conn, "${1}"+redactedSecret)
+	}
+	u, err := url.Parse(rest)
+	if err != nil {
+		return redactedSecret
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedSecret)
+	}
+	if q := u.Query(); q.Has("password") {
+		q.Set("password", redactedSecret)
+		u.RawQuery = q.Encode()
+	}
+	if jdbc {
+		return "jdbc:" + u.String()
+	}
+	return u.String()
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redactedSecret
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolvePostgresConn(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "POSTGRES_CONN wins",
+			cfg: Config{PostgresConn: "postgres://a:b@conn:5432/db", DatabaseURL: "postgres://legacy/db",
+				PostgresHost: "host"},
+			want: "postgres://a:b@conn:5432/db",
+		},
+		{
+			name: "DATABASE_URL before parts",
+			cfg:  Config{DatabaseURL: "postgres://legacy:5432/db", PostgresHost: "host"},
+			want: "postgres://legacy:5432/db",
+		},
+		{
+			name: "parts from README variables",
+			cfg: Config{PostgresHost: "db", PostgresPort: 6432, PostgresDatabase: "tenders",
+				PostgresUsername: "user", PostgresPassword: "p@ss", PostgresConnectTimeout: 5 * time.Second},
+			want: "postgres://user:p%40ss@db:6432/tenders?connect_timeout=5",
+		},
+		{
+			name: "JDBC URL fills missing parts",
+			cfg: Config{PostgresJDBCURL: "jdbc:postgresql://jdbc-host:5433/jdbcdb?user=ju&password=jp&sslmode=disable",
+				PostgresDatabase: "tenders"},
+			want: "postgres://ju:jp@jdbc-host:5433/tenders?sslmode=disable",
+		},
+		{
+			name: "explicit parts override JDBC URL",
+			cfg: Config{PostgresJDBCURL: "jdbc:postgresql://jdbc-host/jdbcdb?user=ju&password=jp",
+				PostgresHost: "db", PostgresUsername: "user", PostgresPassword: "pw"},
+			want: "postgres://user:pw@db:5432/jdbcdb",
+		},
+		{
+			name: "database only defaults host and port",
+			cfg:  Config{PostgresDatabase: "tenders"},
+			want: "postgres://localhost:5432/tenders",
+		},
+		{
+			name: "nothing configured",
+			cfg:  Config{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if err := cfg.resolve(); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.PostgresConn != tt.want {
+				t.Fatalf("PostgresConn = %q, want %q", cfg.PostgresConn, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveServerAddress(t *testing.T) {
+	cfg := Config{ServerHost: "0.0.0.0", ServerPort: 8080}
+	if err := cfg.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ServerAddress != "0.0.0.0:8080" {
+		t.Fatalf("ServerAddress = %q", cfg.ServerAddress)
+	}
+
+	cfg = Config{ServerAddress: "127.0.0.1:9000", ServerHost: "0.0.0.0", ServerPort: 8080}
+	if err := cfg.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ServerAddress != "127.0.0.1:9000" {
+		t.Fatalf("SERVER_ADDRESS was overridden: %q", cfg.ServerAddress)
+	}
+}
+
+func TestParseJDBCURLErrors(t *testing.T) {
+	for _, raw := range []string{"postgres://host/db", "jdbc:mysql://host/db", "jdbc:postgresql:host/db", "jdbc:postgresql://host:port/db"} {
+		if _, err := parseJDBCURL(raw); err == nil {
+			t.Errorf("parseJDBCURL(%q) succeeded", raw)
+		}
+	}
+}
+
+func TestValidatePostgresConn(t *testing.T) {
+	tests := []struct {
+		conn  string
+		valid bool
+	}{
+		{"postgres://user@host:5432/db", true},
+		{"postgresql://host/db", true},
+		{"host=db user=u password=secret dbname=tenders", true},
+		{"mysql://host/db", false},
+		{"postgres:///db", false},
+	}
+	for _, tt := range tests {
+		if err := validatePostgresConn(tt.conn); (err == nil) != tt.valid {
+			t.Errorf("validatePostgresConn(%q) = %v, want valid %v", tt.conn, err, tt.valid)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Config{
+		PostgresConn:     "postgres://user:secret@db:5432/tenders?password=secret2",
+		DatabaseURL:      "host=db user=u password='se cret' dbname=tenders",
+		PostgresJDBCURL:  "jdbc:postgresql://db/tenders?user=u&password=secret3",
+		PostgresPassword: "secret4",
+		SMTPPassword:     "secret5",
+		SMTPUsername:     "mailer",
+	}
+	redacted := cfg.Redacted()
+
+	for _, s := range []string{redacted.PostgresConn, redacted.DatabaseURL, redacted.PostgresJDBCURL,
+		redacted.PostgresPassword, redacted.SMTPPassword} {
+		if strings.Contains(s, "secret") || strings.Contains(s, "cret'") {
+			t.Errorf("secret leaked: %q", s)
+		}
+	}
+	if !strings.HasPrefix(redacted.PostgresConn, "postgres://user:"+redactedSecret+"@db:5432/tenders") {
+		t.Errorf("PostgresConn = %q, want user and host kept", redacted.PostgresConn)
+	}
+	if !strings.HasPrefix(redacted.PostgresJDBCURL, "jdbc:postgresql://db/tenders") {
+		t.Errorf("PostgresJDBCURL = %q, want jdbc prefix kept", redacted.PostgresJDBCURL)
+	}
+	if redacted.SMTPUsername != "mailer" {
+		t.Errorf("SMTPUsername = %q, want unchanged", redacted.SMTPUsername)
+	}
+	// Исходная конфигурация не меняется
+	if cfg.PostgresPassword != "secret4" {
+		t.Error("Redacted modified the original config")
+	}
+	if empty := (Config{}).Redacted(); empty.PostgresPassword != "" || empty.PostgresConn != "" {
+		t.Errorf("empty secrets became %+v", empty)
+	}
+}
+
+func TestLoadConfigFromREADMEVariables(t *testing.T) {
+	t.Setenv("SERVER_ADDRESS", "0.0.0.0:8080")
+	t.Setenv("POSTGRES_HOST", "db")
+	t.Setenv("POSTGRES_PORT", "5432")
+	t.Setenv("POSTGRES_DATABASE", "tenders")
+	t.Setenv("POSTGRES_USERNAME", "user")
+	t.Setenv("POSTGRES_PASSWORD", "secret")
+
+	cfg, err := LoadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "postgres://user:secret@db:5432/tenders?connect_timeout=5"; cfg.PostgresConn != want {
+		t.Errorf("PostgresConn = %q, want %q", cfg.PostgresConn, want)
+	}
+	if cfg.ServerAddress != "0.0.0.0:8080" {
+		t.Errorf("ServerAddress = %q", cfg.ServerAddress)
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "database connection is not configured"},
+		{map[string]string{"POSTGRES_CONN": "mysql://db/tenders"}, "POSTGRES_CONN"},
+		{map[string]string{"POSTGRES_JDBC_URL": "jdbc:mysql://db/tenders"}, "POSTGRES_JDBC_URL"},
+		{map[string]string{"POSTGRES_HOST": "db", "POSTGRES_MAX_IDLE_CONNS": "50"}, "POSTGRES_MAX_IDLE_CONNS"},
+		{map[string]string{"POSTGRES_HOST": "db", "LOG_LEVEL": "verbose"}, "LOG_LEVEL"},
+		{map[string]string{"POSTGRES_HOST": "db", "OUTBOX_RETENTION": "12h"}, "OUTBOX_RETENTION must be longer than DIGEST_PERIOD"},
+		{map[string]string{"POSTGRES_HOST": "db", "TRUSTED_PROXIES": "10.0.0.0/8,lb"}, "TRUSTED_PROXIES"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := LoadConfig(nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadConfig() = %v, want error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
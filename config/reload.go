@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(args ...interface{})
+	Error(args ...interface{})
+}
+
+// Watcher перечитывает конфигурацию по SIGHUP и при изменении файла
+// конфигурации. Применяются только поля с тегом reload:"true"; об изменении
+// остальных пишется в лог, они вступят в силу после перезапуска.
+type Watcher struct {
+	args    []string
+	logger  Logger
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(*Config)
+}
+
+// NewWatcher создает Watcher для конфигурации cfg, загруженной с флагами args
+func NewWatcher(cfg *Config, args []string, logger Logger) *Watcher {
+	w := &Watcher{args: args, logger: logger}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current действующая конфигурация; возвращаемое значение не изменяется
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe регистрирует fn, вызываемую с новой конфигурацией после каждой
+// перезагрузки, изменившей хотя бы одну настройку
+func (w *Watcher) Subscribe(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload перечитывает конфигурацию. При ошибке действующая конфигурация не меняется.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	loaded, err := LoadConfig(w.args)
+	if err != nil {
+		return err
+	}
+
+	current := w.current.Load()
+	next := *current
+	cur := reflect.ValueOf(current).Elem()
+	dst := reflect.ValueOf(&next).Elem()
+	src := reflect.ValueOf(loaded).Elem()
+	changed := false
+	for _, f := range settingFields {
+		if reflect.DeepEqual(cur.Field(f.index).Interface(), src.Field(f.index).Interface()) {
+			continue
+		}
+		if !f.reload {
+			w.logger.Info("config: ", f.key, " changed, restart required to apply")
+			continue
+		}
+		dst.Field(f.index).Set(src.Field(f.index))
+		changed = true
+		w.logger.Info("config: ", f.key, " reloaded")
+	}
+	if !changed {
+		return nil
+	}
+
+	w.current.Store(&next)
+	for _, fn := range w.subscribers {
+		fn(&next)
+	}
+	return nil
+}
+
+// Run перезагружает конфигурацию по SIGHUP и при изменении файла до отмены ctx
+func (w *Watcher) Run(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	cfg := w.Current()
+	var tick <-chan time.Time
+	if cfg.ConfigFile != "" && cfg.ConfigWatchInterval > 0 {
+		ticker := time.NewTicker(cfg.ConfigWatchInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	modTime := fileModTime(cfg.ConfigFile)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-hup:
+			w.logger.Info("config: SIGHUP received, reloading")
+		case <-tick:
+			t := fileModTime(cfg.ConfigFile)
+			if t.Equal(modTime) {
+				continue
+			}
+			modTime = t
+			w.logger.Info("config: ", cfg.ConfigFile, " changed, reloading")
+		}
+		if err := w.Reload(); err != nil {
+			w.logger.Error("config reload failed: ", err)
+		}
+	}
+}
+
+func fileModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
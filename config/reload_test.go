@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Info(args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *recordingLogger) Error(args ...interface{}) { l.Info(args...) }
+
+func (l *recordingLogger) contains(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.lines {
+		if strings.Contains(line, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestWatcher(t *testing.T, content string) (*Watcher, string, *recordingLogger) {
+	t.Helper()
+	path := writeConfigFile(t, "config.yaml", content)
+	args := []string{"--config-file", path}
+	cfg, err := LoadConfig(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := &recordingLogger{}
+	return NewWatcher(cfg, args, logger), path, logger
+}
+
+// Применяются только настройки с reload:"true"; остальные ждут перезапуска
+func TestWatcherReloadAppliesSafeSettings(t *testing.T) {
+	w, path, logger := newTestWatcher(t, "postgres_host: db\nlog_level: info\nserver_port: 8080\n")
+	var notified []*Config
+	w.Subscribe(func(cfg *Config) { notified = append(notified, cfg) })
+	before := w.Current()
+
+	err := os.WriteFile(path, []byte("postgres_host: db\nlog_level: debug\nserver_port: 9090\nrate_limit_user_read: 10/1s\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := w.Current()
+	if cfg.LogLevel != "debug" || cfg.RateLimitUserRead.String() != "10/1s" {
+		t.Errorf("reloadable settings not applied: level %q, limit %v", cfg.LogLevel, cfg.RateLimitUserRead)
+	}
+	if cfg.ServerPort != 8080 {
+		t.Errorf("SERVER_PORT = %d, want unchanged until restart", cfg.ServerPort)
+	}
+	if !logger.contains("SERVER_PORT changed, restart required") {
+		t.Errorf("restart warning not logged: %v", logger.lines)
+	}
+	if before.LogLevel != "info" {
+		t.Error("previous config was modified in place")
+	}
+	if len(notified) != 1 || notified[0] != cfg {
+		t.Fatalf("subscribers notified %d times, want once with the new config", len(notified))
+	}
+
+	// Повторная перезагрузка без изменений не уведомляет подписчиков
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) != 1 {
+		t.Fatalf("subscribers notified %d times after a no-op reload", len(notified))
+	}
+}
+
+// Ошибочный файл не меняет действующую конфигурацию
+func TestWatcherReloadKeepsConfigOnError(t *testing.T) {
+	w, path, _ := newTestWatcher(t, "postgres_host: db\nlog_level: info\n")
+	before := w.Current()
+
+	if err := os.WriteFile(path, []byte("postgres_host: db\nlog_level: loud\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Fatal("Reload accepted an invalid log level")
+	}
+	if w.Current() != before {
+		t.Fatal("config changed after a failed reload")
+	}
+}
+
+// Перезагрузка видит правки .env, но переменные окружения процесса важнее
+func TestWatcherReloadReadsDotEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Cleanup(func() {
+		for key := range dotenvKeys {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	})
+	t.Setenv("RATE_LIMIT_IP_READ", "5/1s")
+	writeDotEnv := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(".env", []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeDotEnv("POSTGRES_HOST=db\nLOG_LEVEL=info\nFEATURE_FLAGS=export:true\nRATE_LIMIT_IP_READ=50/1s\n")
+	cfg, err := LoadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(cfg, nil, &recordingLogger{})
+
+	writeDotEnv("POSTGRES_HOST=db\nLOG_LEVEL=debug\nRATE_LIMIT_IP_READ=50/1s\n")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	cfg = w.Current()
+	if cfg.LogLevel != "debug" {
+		t.Errorf("LOG_LEVEL = %q, want value edited in .env", cfg.LogLevel)
+	}
+	if !cfg.Feature(FeatureExport) || cfg.FeatureFlags != nil {
+		t.Errorf("FEATURE_FLAGS = %v, want removed from .env", cfg.FeatureFlags)
+	}
+	if cfg.RateLimitIPRead.String() != "5/1s" {
+		t.Errorf("RATE_LIMIT_IP_READ = %v, want process environment over .env", cfg.RateLimitIPRead)
+	}
+}
@@ -0,0 +1,88 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/notify"
+	"go-tenders/webhook"
+)
+
+// EventDigest значение заголовка X-Tenders-Event у сводки, отправленной вебхуком
+const EventDigest = "digest.daily"
+
+// Channel канал доставки сводки
+type Channel interface {
+	Name() model.DigestChannel
+	Send(ctx context.Context, sub model.DigestSubscription, digest Rendered) error
+}
+
+// EmailChannel отправляет сводку письмом на адрес подписки
+type EmailChannel struct {
+	transport notify.Transport
+}
+
+// NewEmailChannel конструктор EmailChannel
+func NewEmailChannel(transport notify.Transport) *EmailChannel {
+	return &EmailChannel{transport: transport}
+}
+
+func (c *EmailChannel) Name() model.DigestChannel {
+	return model.DigestChannelEmail
+}
+
+func (c *EmailChannel) Send(ctx context.Context, sub model.DigestSubscription, digest Rendered) error {
+	return c.transport.Send(ctx, sub.Target, digest.Subject, digest.Body)
+}
+
+// WebhookChannel отправляет сводку в JSON на URL подписки; запрос подписывается
+// секретом подписки так же, как доставки вебхуков организаций
+type WebhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel конструктор WebhookChannel; nil client заменяется клиентом
+// webhook.NewClient с таймаутом 10 секунд, который не ходит во внутреннюю сеть
+func NewWebhookChannel(client *http.Client) *WebhookChannel {
+	if client == nil {
+		client = webhook.NewClient(10 * time.Second)
+	}
+	return &WebhookChannel{client: client}
+}
+
+func (c *WebhookChannel) Name() model.DigestChannel {
+	return model.DigestChannelWebhook
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, sub model.DigestSubscription, digest Rendered) error {
+	payload, err := json.Marshal(digest.Digest)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-tenders-webhook/1.0")
+	req.Header.Set(webhook.HeaderEvent, EventDigest)
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(sub.Secret, time.Now(), payload))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/outbox"
+)
+
+// Store подписки и тендеры для сводки; реализуется storage.PostgresStorage
+type Store interface {
+	DueDigestSubscriptions(ctx context.Context, now, cutoff time.Time, limit int) ([]model.DigestSubscription, error)
+	PublishedTenders(ctx context.Context, from, to time.Time, serviceTypes []model.TenderServiceType, keywords []string) ([]model.Tender, error)
+	MarkDigestSent(ctx context.Context, subscriptionId string, sentUntil time.Time) error
+	RecordDigestFailure(ctx context.Context, subscriptionId string, retryAt time.Time) error
+	WithDigestLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Config параметры расписания сводки
+type Config struct {
+	// Period как часто подписчик получает сводку
+	Period time.Duration
+	// CheckInterval как часто задача ищет подписки, которым пора отправить сводку
+	CheckInterval time.Duration
+	// BatchSize сколько подписок обрабатывается за один проход
+	BatchSize int
+	// BaseBackoff, MaxBackoff задержка повтора неудачной сводки, удваивается с каждой попыткой
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultConfig ежедневная сводка с проверкой раз в 10 минут
+var DefaultConfig = Config{
+	Period:        24 * time.Hour,
+	CheckInterval: 10 * time.Minute,
+	BatchSize:     100,
+	BaseBackoff:   10 * time.Minute,
+	MaxBackoff:    6 * time.Hour,
+}
+
+// Job периодическая задача рассылки сводок. Для каждой подписки собирает тендеры,
+// опубликованные с предыдущей сводки, и отправляет их через канал подписки.
+// Если отправка не удалась, граница не сдвигается, а подписка откладывается
+// с экспоненциальной задержкой. Проход выполняется под advisory-блокировкой,
+// поэтому при нескольких экземплярах сервиса сводки рассылает один из них.
+type Job struct {
+	store    Store
+	renderer *Renderer
+	channels map[model.DigestChannel]Channel
+	logger   outbox.Logger
+	config   Config
+	now      func() time.Time
+}
+
+// NewJob конструктор Job
+func NewJob(store Store, renderer *Renderer, logger outbox.Logger, cfg Config, channels ...Channel) *Job {
+	j := &Job{
+		store:    store,
+		renderer: renderer,
+		channels: make(map[model.DigestChannel]Channel),
+		logger:   logger,
+		config:   cfg,
+		now:      time.Now,
+	}
+	for _, c := range channels {
+		j.channels[c.Name()] = c
+	}
+	return j
+}
+
+// Run выполняет проверки до отмены ctx
+func (j *Job) Run(ctx context.Context) error {
+	for {
+		// Блокировку держит другой экземпляр — проход пропускается
+		if _, err := j.store.WithDigestLock(ctx, j.runDue); err != nil {
+			j.logger.Error("digest job error: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(j.config.CheckInterval):
+		}
+	}
+}
+
+// runDue обрабатывает пачки, пока подписки, которым пора отправить сводку, не кончатся.
+// Неудачные подписки откладываются, поэтому повторно в выборку не попадают.
+func (j *Job) runDue(ctx context.Context) error {
+	for {
+		n, err := j.RunOnce(ctx)
+		if err != nil || n < j.config.BatchSize {
+			return err
+		}
+	}
+}
+
+// RunOnce обрабатывает одну пачку подписок, которым пора отправить сводку,
+// и возвращает их число
+func (j *Job) RunOnce(ctx context.Context) (int, error) {
+	now := j.now()
+	subs, err := j.store.DueDigestSubscriptions(ctx, now, now.Add(-j.config.Period), j.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sub := range subs {
+		if err := j.send(ctx, sub, now); err != nil {
+			j.logger.Error("digest ", sub.Id, " via ", sub.Channel, " failed: ", err)
+			retryAt := now.Add(outbox.Backoff(j.config.BaseBackoff, j.config.MaxBackoff, sub.Attempts+1))
+			if err := j.store.RecordDigestFailure(ctx, sub.Id, retryAt); err != nil {
+				j.logger.Error("digest record failure error: ", err)
+			}
+			continue
+		}
+		if err := j.store.MarkDigestSent(ctx, sub.Id, now); err != nil {
+			j.logger.Error("digest mark sent error: ", err)
+		}
+	}
+	return len(subs), nil
+}
+
+// send отправляет сводку подписки; пустая сводка не отправляется
+func (j *Job) send(ctx context.Context, sub model.DigestSubscription, now time.Time) error {
+	// Без канала граница не сдвигается: сводка уйдет, когда канал настроят
+	channel, ok := j.channels[sub.Channel]
+	if !ok {
+		return fmt.Errorf("digest channel %s is not configured", sub.Channel)
+	}
+	tenders, err := j.store.PublishedTenders(ctx, sub.LastSentAt, now, sub.ServiceTypes, sub.Keywords)
+	if err != nil {
+		return err
+	}
+	if len(tenders) == 0 {
+		return nil
+	}
+
+	rendered, err := j.renderer.Render(sub.Locale, model.Digest{
+		SubscriptionId: sub.Id,
+		From:           sub.LastSentAt,
+		To:             now,
+		Tenders:        tenders,
+	})
+	if err != nil {
+		return err
+	}
+	return channel.Send(ctx, sub, rendered)
+}
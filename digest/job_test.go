@@ -0,0 +1,139 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Error(args ...interface{}) {}
+
+// fakeStore отдает подписки, пока они не отправлены и не отложены
+type fakeStore struct {
+	subs     []model.DigestSubscription
+	tenders  []model.Tender
+	sent     map[string]time.Time
+	retryAt  map[string]time.Time
+	locked   bool
+	dueCalls int
+}
+
+func (f *fakeStore) DueDigestSubscriptions(ctx context.Context, now, cutoff time.Time, limit int) ([]model.DigestSubscription, error) {
+	f.dueCalls++
+	var due []model.DigestSubscription
+	for _, s := range f.subs {
+		if _, ok := f.sent[s.Id]; ok {
+			continue
+		}
+		if at, ok := f.retryAt[s.Id]; ok && at.After(now) {
+			continue
+		}
+		due = append(due, s)
+	}
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (f *fakeStore) PublishedTenders(ctx context.Context, from, to time.Time, serviceTypes []model.TenderServiceType, keywords []string) ([]model.Tender, error) {
+	return f.tenders, nil
+}
+
+func (f *fakeStore) MarkDigestSent(ctx context.Context, subscriptionId string, sentUntil time.Time) error {
+	f.sent[subscriptionId] = sentUntil
+	return nil
+}
+
+func (f *fakeStore) RecordDigestFailure(ctx context.Context, subscriptionId string, retryAt time.Time) error {
+	f.retryAt[subscriptionId] = retryAt
+	return nil
+}
+
+func (f *fakeStore) WithDigestLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if f.locked {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+type failingChannel struct{}
+
+func (failingChannel) Name() model.DigestChannel { return model.DigestChannelWebhook }
+
+func (failingChannel) Send(ctx context.Context, sub model.DigestSubscription, digest Rendered) error {
+	return errors.New("receiver is down")
+}
+
+func newTestJob(t *testing.T, store *fakeStore, cfg Config, channels ...Channel) *Job {
+	t.Helper()
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewJob(store, renderer, nopLogger{}, cfg, channels...)
+}
+
+// Неудачные подписки откладываются: полная пачка ошибок не зацикливает проход
+func TestJobBacksOffFailedSubscriptions(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		subs: []model.DigestSubscription{
+			{Id: "webhook", Channel: model.DigestChannelWebhook, Attempts: 2},
+			{Id: "email", Channel: model.DigestChannelEmail},
+		},
+		tenders: []model.Tender{{Id: "t1", Name: "Поставка"}},
+		sent:    map[string]time.Time{},
+		retryAt: map[string]time.Time{},
+	}
+	cfg := DefaultConfig
+	cfg.BatchSize = 2
+	job := newTestJob(t, store, cfg, failingChannel{})
+	job.now = func() time.Time { return now }
+
+	if err := job.runDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.dueCalls != 2 {
+		t.Fatalf("DueDigestSubscriptions called %d times, want 2", store.dueCalls)
+	}
+	if len(store.sent) != 0 {
+		t.Fatalf("sent = %v, want none", store.sent)
+	}
+	if want := now.Add(4 * cfg.BaseBackoff); !store.retryAt["webhook"].Equal(want) {
+		t.Errorf("webhook retry at %v, want %v", store.retryAt["webhook"], want)
+	}
+	// Канал email не настроен — это ошибка, а не успешная отправка
+	if want := now.Add(cfg.BaseBackoff); !store.retryAt["email"].Equal(want) {
+		t.Errorf("email retry at %v, want %v", store.retryAt["email"], want)
+	}
+}
+
+func TestJobSkipsPassWithoutLock(t *testing.T) {
+	store := &fakeStore{
+		subs:    []model.DigestSubscription{{Id: "s", Channel: model.DigestChannelWebhook}},
+		sent:    map[string]time.Time{},
+		retryAt: map[string]time.Time{},
+		locked:  true,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := DefaultConfig
+	cfg.CheckInterval = time.Millisecond
+	job := newTestJob(t, store, cfg, failingChannel{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := job.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v", err)
+	}
+	if store.dueCalls != 0 {
+		t.Fatalf("DueDigestSubscriptions called %d times without the lock", store.dueCalls)
+	}
+}
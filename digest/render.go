@@ -0,0 +1,64 @@
+package digest
+
+import (
+	"embed"
+	"strings"
+	"text/template"
+
+	"go-tenders/model"
+)
+
+// DefaultLocale язык сводки, если у подписки не задан или не поддерживается
+const DefaultLocale = "ru"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Rendered сводка, готовая к отправке: текст для людей и данные для машин
+type Rendered struct {
+	Subject string
+	Body    string
+	Digest  model.Digest
+}
+
+// Renderer шаблоны сводки по языкам
+type Renderer struct {
+	locales map[string]*template.Template
+}
+
+// NewRenderer загружает встроенные шаблоны ru и en
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{locales: make(map[string]*template.Template)}
+	for _, locale := range []string{"ru", "en"} {
+		tmpl, err := template.ParseFS(templateFS, "templates/"+locale+".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		r.locales[locale] = tmpl
+	}
+	return r, nil
+}
+
+// SupportsLocale сообщает, есть ли шаблоны для языка locale
+func (r *Renderer) SupportsLocale(locale string) bool {
+	_, ok := r.locales[locale]
+	return ok
+}
+
+// Render формирует сводку на языке locale
+func (r *Renderer) Render(locale string, d model.Digest) (Rendered, error) {
+	tmpl, ok := r.locales[locale]
+	if !ok {
+		tmpl = r.locales[DefaultLocale]
+	}
+	d.From, d.To = d.From.UTC(), d.To.UTC()
+
+	var subject, body strings.Builder
+	if err := tmpl.ExecuteTemplate(&subject, "subject", d); err != nil {
+		return Rendered{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", d); err != nil {
+		return Rendered{}, err
+	}
+	return Rendered{Subject: subject.String(), Body: body.String(), Digest: d}, nil
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// utf8BOM нужен Excel, чтобы открыть CSV с кириллицей в UTF-8
+const utf8BOM = "\ufeff"
+
+type csvWriter struct {
+	w       *csv.Writer
+	started bool
+	out     io.Writer
+	record  []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), out: w}
+}
+
+func (c *csvWriter) WriteRow(values ...interface{}) error {
+	if !c.started {
+		c.started = true
+		if _, err := io.WriteString(c.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+	c.record = c.record[:0]
+	for _, v := range values {
+		cell := fmt.Sprint(v)
+		if !isNumber(v) {
+			cell = escapeFormula(cell)
+		}
+		c.record = append(c.record, cell)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+// escapeFormula экранирует строки, которые табличный редактор принял бы за
+// формулу (CSV injection): такие значения приходят от пользователей API
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
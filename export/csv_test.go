@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+// Значения, похожие на формулу, экранируются; числа остаются числами
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Доставка", "Доставка"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+7 999", "'+7 999"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := escapeFormula(tt.in); got != tt.want {
+			t.Errorf("escapeFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// CSV начинается с BOM, строки экранируются, числа пишутся как есть
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(CSV, &buf, "ignored")
+	rows := [][]interface{}{
+		{"id", "name", "version"},
+		{"t-1", "=1+1", int32(-3)},
+		{"t-2", "Кавычки \"и\", запятые", 2.5},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, utf8BOM) {
+		t.Fatal("output does not start with BOM")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	want := [][]string{
+		{"id", "name", "version"},
+		{"t-1", "'=1+1", "-3"},
+		{"t-2", "Кавычки \"и\", запятые", "2.5"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %q, want %q", records, want)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+// Пустая выгрузка не содержит даже BOM
+func TestCSVWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(CSV, &buf, "").Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output = %q, want empty", buf.String())
+	}
+}
@@ -0,0 +1,62 @@
+// Package export построчно выгружает таблицы в CSV и XLSX, не накапливая их в памяти
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format формат выгрузки
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ParseFormat разбирает параметр format; пустое значение — CSV
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return CSV, nil
+	case CSV, XLSX:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q: use csv or xlsx", s)
+	}
+}
+
+// ContentType MIME-тип файла выгрузки
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer построчная запись таблицы. Значения int, int32, int64 и float64
+// записываются числами, остальные — строками через fmt.Sprint.
+type Writer interface {
+	WriteRow(values ...interface{}) error
+	// Flush отправляет записанные строки в нижележащий io.Writer
+	Flush() error
+	// Close завершает файл; без него XLSX не откроется
+	Close() error
+}
+
+// NewWriter создает Writer формата f; sheet — имя листа XLSX
+func NewWriter(f Format, w io.Writer, sheet string) Writer {
+	if f == XLSX {
+		return newXLSXWriter(w, sheet)
+	}
+	return newCSVWriter(w)
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int32, int64, float64:
+		return true
+	}
+	return false
+}
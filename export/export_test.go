@@ -0,0 +1,23 @@
+package export
+
+import "testing"
+
+// ParseFormat без учета регистра; пустое значение — CSV
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", CSV, false},
+		{"csv", CSV, false},
+		{"XLSX", XLSX, false},
+		{"json", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
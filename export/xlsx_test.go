@@ -0,0 +1,147 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// columnName нумерует колонки как Excel
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"}, {1, "B"}, {25, "Z"}, {26, "AA"}, {27, "AB"}, {51, "AZ"}, {52, "BA"},
+		{701, "ZZ"}, {702, "AAA"}, {16383, "XFD"},
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.i); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.i, got, tt.want)
+		}
+	}
+}
+
+// sheetName убирает запрещенные символы и обрезает имя до 31 символа
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "Sheet1"},
+		{"Тендеры", "Тендеры"},
+		{`a[b]c:d*e?f/g\h`, "a_b_c_d_e_f_g_h"},
+		{strings.Repeat("я", 40), strings.Repeat("я", 31)},
+	}
+	for _, tt := range tests {
+		if got := sheetName(tt.in); got != tt.want {
+			t.Errorf("sheetName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// xlsxCell ячейка листа в том виде, в каком ее пишет xlsxWriter
+type xlsxCell struct {
+	Ref   string `xml:"r,attr"`
+	Type  string `xml:"t,attr"`
+	Value string `xml:"v"`
+	Text  string `xml:"is>t"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   string     `xml:"r,attr"`
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readZipFile(t *testing.T, r *zip.Reader, name string) []byte {
+	t.Helper()
+	f, err := r.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return data
+}
+
+// Книга читается как ZIP с корректным XML; текст экранируется, числа пишутся значениями
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(XLSX, &buf, "Тендеры <&>")
+	if err := w.WriteRow("name", "version"); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.WriteRow(`<b>&"x"`+"\x00", int64(7)); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels"} {
+		readZipFile(t, r, name)
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(readZipFile(t, r, "xl/workbook.xml"), &workbook); err != nil {
+		t.Fatalf("workbook: %v", err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != "Тендеры <&>" {
+		t.Errorf("sheets = %+v", workbook.Sheets)
+	}
+
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(readZipFile(t, r, "xl/worksheets/sheet1.xml"), &sheet); err != nil {
+		t.Fatalf("sheet: %v", err)
+	}
+	if len(sheet.Rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(sheet.Rows))
+	}
+	row := sheet.Rows[1]
+	if row.Ref != "2" || len(row.Cells) != 2 {
+		t.Fatalf("row = %+v", row)
+	}
+	if c := row.Cells[0]; c.Ref != "A2" || c.Type != "inlineStr" || c.Text != `<b>&"x"`+"�" {
+		t.Errorf("text cell = %+v", c)
+	}
+	if c := row.Cells[1]; c.Ref != "B2" || c.Type != "" || c.Value != "7" {
+		t.Errorf("number cell = %+v", c)
+	}
+}
+
+// Пустая книга все равно открывается: лист создается при Close
+func TestXLSXWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(XLSX, &buf, "").Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(readZipFile(t, r, "xl/worksheets/sheet1.xml"), &sheet); err != nil {
+		t.Fatalf("sheet: %v", err)
+	}
+	if len(sheet.Rows) != 0 {
+		t.Errorf("rows = %d, want 0", len(sheet.Rows))
+	}
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Defines values for Format.
+const (
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+)
+
+// Format формат записей лога
+type Format string
+
+// Logger структурированный логгер на основе log/slog. Реализует server.Logger
+// и outbox.Logger: аргументы Info/Error склеиваются в сообщение, как в fmt.Sprint.
+// Уровень можно менять на лету через SetLevel.
+type Logger struct {
+	slog  *slog.Logger
+	level *slog.LevelVar
+}
+
+// New создает логгер, пишущий в w. level — debug, info, warn или error;
+// format — json или text.
+func New(w io.Writer, level string, format Format) (*Logger, error) {
+	lv := new(slog.LevelVar)
+	if err := setLevel(lv, level); err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lv}
+	var h slog.Handler
+	switch format {
+	case FormatJSON, "":
+		h = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return &Logger{slog: slog.New(contextHandler{h}), level: lv}, nil
+}
+
+func setLevel(lv *slog.LevelVar, level string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	lv.Set(l)
+	return nil
+}
+
+// SetLevel меняет уровень логирования без пересоздания логгера
+func (l *Logger) SetLevel(level string) error {
+	return setLevel(l.level, level)
+}
+
+// Slog возвращает нижележащий *slog.Logger
+func (l *Logger) Slog() *slog.Logger {
+	return l.slog
+}
+
+// With возвращает логгер с дополнительными полями
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{slog: l.slog.With(args...), level: l.level}
+}
+
+func (l *Logger) Debug(args ...interface{}) {
+	l.slog.Debug(fmt.Sprint(args...))
+}
+
+func (l *Logger) Info(args ...interface{}) {
+	l.slog.Info(fmt.Sprint(args...))
+}
+
+func (l *Logger) Warn(args ...interface{}) {
+	l.slog.Warn(fmt.Sprint(args...))
+}
+
+func (l *Logger) Error(args ...interface{}) {
+	l.slog.Error(fmt.Sprint(args...))
+}
+
+// InfoContext пишет сообщение с полями запроса из ctx
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.slog.InfoContext(ctx, msg, args...)
+}
+
+// ErrorContext пишет ошибку с полями запроса из ctx
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.slog.ErrorContext(ctx, msg, args...)
+}
+
+// RequestFields поля запроса, добавляемые к записям, сделанным с его контекстом
+type RequestFields struct {
+	RequestId string
+	User      string
+}
+
+type requestFieldsKey struct{}
+
+// WithRequestFields добавляет в контекст поля запроса для логирования
+func WithRequestFields(ctx context.Context, f RequestFields) context.Context {
+	return context.WithValue(ctx, requestFieldsKey{}, f)
+}
+
+// contextHandler дополняет записи полями request_id и user из контекста
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f, ok := ctx.Value(requestFieldsKey{}).(RequestFields); ok {
+		if f.RequestId != "" {
+			r.AddAttrs(slog.String("request_id", f.RequestId))
+		}
+		if f.User != "" {
+			r.AddAttrs(slog.String("user", f.User))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Default логгер в JSON на stdout с уровнем info
+func Default() *Logger {
+	l, _ := New(os.Stdout, "info", FormatJSON)
+	return l
+}
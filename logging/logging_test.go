@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Записи обработчика с контекстом запроса получают те же request_id и user,
+// что и строка журнала запросов
+func TestErrorContextCarriesRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(logger.AccessLog())
+	e.GET("/tenders", func(ctx echo.Context) error {
+		logger.ErrorContext(ctx.Request().Context(), "GetTenders error", "error", errors.New("db down"))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tenders")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenders?username=user1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-42")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want handler error and access log: %v", len(records), records)
+	}
+	for _, r := range records {
+		if r["request_id"] != "req-42" || r["user"] != "user1" {
+			t.Errorf("record %v lacks request fields", r)
+		}
+	}
+	if records[0]["msg"] != "GetTenders error" || records[0]["error"] != "db down" {
+		t.Errorf("handler record = %v", records[0])
+	}
+}
+
+// Без контекста запроса поля не добавляются
+func TestErrorWithoutRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Error("event log stopped: ", errors.New("closed"))
+
+	var r map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r["msg"] != "event log stopped: closed" {
+		t.Errorf("msg = %v", r["msg"])
+	}
+	if _, ok := r["request_id"]; ok {
+		t.Errorf("unexpected request_id in %v", r)
+	}
+}
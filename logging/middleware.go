@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AccessLog middleware журнала запросов. Должен стоять после middleware.RequestID:
+// идентификатор запроса и пользователь (параметр username) записываются в каждую
+// строку журнала и в контекст запроса, чтобы их получали и записи обработчиков.
+func (l *Logger) AccessLog() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			fields := RequestFields{
+				RequestId: ctx.Response().Header().Get(echo.HeaderXRequestID),
+				User:      ctx.QueryParam("username"),
+			}
+			reqCtx := WithRequestFields(req.Context(), fields)
+			ctx.SetRequest(req.WithContext(reqCtx))
+
+			start := time.Now()
+			err := next(ctx)
+			if err != nil {
+				// Передаем ошибку обработчику Echo, чтобы записать итоговый код ответа
+				ctx.Error(err)
+			}
+
+			status := ctx.Response().Status
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", ctx.Path()),
+				slog.Int("status", status),
+				slog.Int64("bytes_out", ctx.Response().Size),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("remote_ip", ctx.RealIP()),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			l.slog.LogAttrs(reqCtx, level, "request", attrs...)
+			return nil
+		}
+	}
+}
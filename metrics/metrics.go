@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tenders"
+
+// Registry реестр метрик сервиса; отдается эндпоинтом /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Число HTTP-запросов по операции API, методу и коду ответа.",
+	}, []string{"operation", "method", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Время обработки HTTP-запроса по операции API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "method"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Число обрабатываемых HTTP-запросов.",
+	})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Время обращения к базе по методу хранилища и виду обращения.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "kind"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Число обращений к базе, завершившихся ошибкой.",
+	}, []string{"operation", "kind"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, httpInFlight,
+		dbQueryDuration, dbQueryErrors,
+	)
+}
+
+// Handler обработчик эндпоинта /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDBStats публикует статистику пула соединений (открытые, занятые, ожидания)
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// QueryHook хук storage, измеряющий обращения к базе
+type QueryHook struct{}
+
+var _ storage.QueryHook = QueryHook{}
+
+func (QueryHook) BeforeQuery(ctx context.Context, q storage.QueryInfo) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		dbQueryDuration.WithLabelValues(q.Operation, string(q.Kind)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			dbQueryErrors.WithLabelValues(q.Operation, string(q.Kind)).Inc()
+		}
+	}
+}
+
+// BusinessSource источник бизнес-показателей; реализуется storage.PostgresStorage
+type BusinessSource interface {
+	CountTendersByStatus(ctx context.Context) (map[model.TenderStatus]int, error)
+	CountBidsByStatus(ctx context.Context) (map[model.BidStatus]int, error)
+}
+
+// businessCollector считает тендеры и предложения по статусам в момент сбора метрик
+type businessCollector struct {
+	source  BusinessSource
+	timeout time.Duration
+	tenders *prometheus.Desc
+	bids    *prometheus.Desc
+	errors  *prometheus.Desc
+}
+
+// RegisterBusinessGauges публикует число тендеров и предложений по статусам
+func RegisterBusinessGauges(source BusinessSource) error {
+	return Registry.Register(&businessCollector{
+		source:  source,
+		timeout: 5 * time.Second,
+		tenders: prometheus.NewDesc(namespace+"_tenders", "Число тендеров по статусу.", []string{"status"}, nil),
+		bids:    prometheus.NewDesc(namespace+"_bids", "Число предложений по статусу.", []string{"status"}, nil),
+		errors:  prometheus.NewDesc(namespace+"_business_scrape_error", "1, если бизнес-показатели не удалось получить.", nil, nil),
+	})
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tenders
+	ch <- c.bids
+	ch <- c.errors
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	failed := 0.0
+	if tenders, err := c.source.CountTendersByStatus(ctx); err != nil {
+		failed = 1
+	} else {
+		for _, status := range []model.TenderStatus{model.Created, model.Published, model.Closed} {
+			ch <- prometheus.MustNewConstMetric(c.tenders, prometheus.GaugeValue, float64(tenders[status]), string(status))
+		}
+	}
+
+	bidStatuses := []model.BidStatus{
+		model.BidStatusCreated, model.BidStatusPublished, model.BidStatusCanceled,
+		model.BidStatusApproved, model.BidStatusRejected,
+	}
+	if bids, err := c.source.CountBidsByStatus(ctx); err != nil {
+		failed = 1
+	} else {
+		for _, status := range bidStatuses {
+			ch <- prometheus.MustNewConstMetric(c.bids, prometheus.GaugeValue, float64(bids[status]), string(status))
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.GaugeValue, failed)
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware считает запросы и время их обработки по операции API;
+// operation возвращает имя операции для запроса
+func Middleware(operation func(ctx echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			op := operation(ctx)
+			method := ctx.Request().Method
+
+			// Dec в defer: паника в обработчике не оставляет запрос «в работе» навсегда
+			httpInFlight.Inc()
+			defer httpInFlight.Dec()
+			start := time.Now()
+			err := next(ctx)
+			if err != nil {
+				// Передаем ошибку обработчику Echo, чтобы записать итоговый код ответа
+				ctx.Error(err)
+			}
+
+			code := strconv.Itoa(ctx.Response().Status)
+			httpRequests.WithLabelValues(op, method, code).Inc()
+			httpDuration.WithLabelValues(op, method).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func inFlight(t *testing.T) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := httpInFlight.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+// Паника в обработчике не должна оставлять счетчик запросов в работе
+func TestMiddlewareInFlightAfterPanic(t *testing.T) {
+	e := echo.New()
+	handler := Middleware(func(echo.Context) string { return "test" })(func(echo.Context) error {
+		panic("boom")
+	})
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	before := inFlight(t)
+	func() {
+		defer func() { recover() }()
+		handler(ctx)
+	}()
+	if got := inFlight(t); got != before {
+		t.Fatalf("in flight = %v after panic, want %v", got, before)
+	}
+}
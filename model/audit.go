@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// Defines values for AuditAction.
+const (
+	AuditTenderCreate   AuditAction = "tender.create"
+	AuditTenderEdit     AuditAction = "tender.edit"
+	AuditTenderRollback AuditAction = "tender.rollback"
+	AuditTenderStatus   AuditAction = "tender.status"
+	AuditBidCreate      AuditAction = "bid.create"
+	AuditBidEdit        AuditAction = "bid.edit"
+	AuditBidRollback    AuditAction = "bid.rollback"
+	AuditBidStatus      AuditAction = "bid.status"
+	AuditBidDecision    AuditAction = "bid.decision"
+	AuditBidFeedback    AuditAction = "bid.feedback"
+)
+
+// AuditAction Изменяющая операция, записанная в журнал аудита
+type AuditAction string
+
+// Defines values for AuditEntityType.
+const (
+	AuditEntityTender AuditEntityType = "tender"
+	AuditEntityBid    AuditEntityType = "bid"
+)
+
+// AuditEntityType Тип измененной сущности
+type AuditEntityType string
+
+// AuditEntry Запись журнала аудита
+type AuditEntry struct {
+	Id     int64       `json:"id"`
+	Action AuditAction `json:"action"`
+
+	// Actor Пользователь, выполнивший операцию
+	Actor Username `json:"actor"`
+
+	// OrganizationId Организация тендера, к которому относится изменение
+	OrganizationId OrganizationId `json:"organizationId"`
+
+	EntityType AuditEntityType `json:"entityType"`
+	EntityId   string          `json:"entityId"`
+
+	// VersionBefore Версия сущности до изменения, отсутствует при создании
+	VersionBefore *int32 `json:"versionBefore,omitempty"`
+
+	// VersionAfter Версия сущности после изменения
+	VersionAfter *int32 `json:"versionAfter,omitempty"`
+
+	// RequestId Идентификатор HTTP-запроса (X-Request-Id)
+	RequestId string `json:"requestId"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	// PrevHash Хэш предыдущей записи цепочки
+	PrevHash string `json:"prevHash"`
+
+	// Hash SHA-256 от PrevHash и полей записи
+	Hash string `json:"hash"`
+}
+
+// AuditParams defines parameters for GetAuditLog.
+type AuditParams struct {
+	Actor          *Username        `query:"actor"`
+	OrganizationId *OrganizationId  `query:"organizationId"`
+	EntityType     *AuditEntityType `query:"entityType"`
+	EntityId       *string          `query:"entityId"`
+	Action         *AuditAction     `query:"action"`
+
+	// From Нижняя граница времени записи, RFC3339
+	From *time.Time `query:"from"`
+
+	// To Верхняя граница времени записи, RFC3339
+	To *time.Time `query:"to"`
+
+	// Limit Максимальное число возвращаемых объектов.
+	Limit *int32 `query:"limit"`
+
+	// Offset Какое количество объектов должно быть пропущено с начала.
+	Offset *int32 `query:"offset"`
+}
+
+// AuditVerification Результат проверки целостности цепочки журнала аудита
+type AuditVerification struct {
+	// Valid Цепочка не нарушена
+	Valid bool `json:"valid"`
+
+	// Checked Число проверенных записей
+	Checked int64 `json:"checked"`
+
+	// BrokenAt Id первой записи, хэш которой не совпал
+	BrokenAt *int64 `json:"brokenAt,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// BidRank Позиция предложения в рейтинге тендера
+type BidRank struct {
+	// Rank Место в рейтинге, начиная с 1
+	Rank int `json:"rank"`
+
+	BidId  BidId     `json:"bidId"`
+	Name   BidName   `json:"name"`
+	Status BidStatus `json:"status"`
+
+	// Approvals Число решений Approved
+	Approvals int `json:"approvals"`
+
+	// Rejections Число решений Rejected
+	Rejections int `json:"rejections"`
+}
+
+// BidBoardMessage Сообщение WebSocket-канала доски предложений тендера
+type BidBoardMessage struct {
+	// Type Тип события или "ranking" для снимка рейтинга
+	Type string `json:"type"`
+
+	// Event Доменное событие предложения
+	Event *Event `json:"event,omitempty"`
+
+	// Ranking Текущий рейтинг предложений тендера
+	Ranking []BidRank `json:"ranking,omitempty"`
+}
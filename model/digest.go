@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// Defines values for DigestChannel.
+const (
+	DigestChannelEmail   DigestChannel = "email"
+	DigestChannelWebhook DigestChannel = "webhook"
+)
+
+// DigestChannel Канал доставки сводки
+type DigestChannel string
+
+// DigestSubscriptionId Уникальный идентификатор подписки на сводку, присвоенный сервером.
+type DigestSubscriptionId = string
+
+// DigestSubscription Подписка пользователя на ежедневную сводку новых тендеров
+type DigestSubscription struct {
+	// Id Уникальный идентификатор подписки
+	Id DigestSubscriptionId `json:"id"`
+
+	// Username Владелец подписки
+	Username Username `json:"username"`
+
+	// ServiceTypes Виды услуг; пустой список — все виды
+	ServiceTypes []TenderServiceType `json:"serviceTypes"`
+
+	// Keywords Ключевые слова, хотя бы одно должно встречаться в названии или описании; пустой список — без фильтра
+	Keywords []string `json:"keywords"`
+
+	// Channel Канал доставки
+	Channel DigestChannel `json:"channel"`
+
+	// Target Адрес email или URL вебхука
+	Target string `json:"target"`
+
+	// Locale Язык сводки: ru или en
+	Locale string `json:"locale"`
+
+	// Secret Ключ подписи для канала webhook, возвращается только при создании
+	Secret string `json:"secret,omitempty"`
+
+	// CreatedAt Дата создания подписки в формате RFC3339
+	CreatedAt string `json:"createdAt"`
+
+	// LastSentAt Граница предыдущей сводки: в следующую попадут тендеры, опубликованные позже
+	LastSentAt time.Time `json:"lastSentAt"`
+
+	// Attempts Число неудачных попыток отправки подряд, служебное поле задачи сводок
+	Attempts int `json:"-"`
+}
+
+// DigestSubscriptionsNewBody defines model for digest_subscriptions_new_body.
+type DigestSubscriptionsNewBody struct {
+	ServiceTypes []TenderServiceType `json:"serviceTypes"`
+	Keywords     []string            `json:"keywords"`
+	Channel      DigestChannel       `json:"channel"`
+	Target       string              `json:"target"`
+	Locale       string              `json:"locale"`
+}
+
+// Digest Сводка тендеров, опубликованных в периоде (From, To]
+type Digest struct {
+	SubscriptionId DigestSubscriptionId `json:"subscriptionId"`
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	Tenders        []Tender             `json:"tenders"`
+}
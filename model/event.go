@@ -0,0 +1,110 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Defines values for EventType.
+const (
+	EventTenderPublished      EventType = "tender.published"
+	EventTenderClosed         EventType = "tender.closed"
+	EventTenderEdited         EventType = "tender.edited"
+	EventBidCreated           EventType = "bid.created"
+	EventBidEdited            EventType = "bid.edited"
+	EventBidStatusChanged     EventType = "bid.status_changed"
+	EventBidDecisionSubmitted EventType = "bid.decision_submitted"
+	EventBidFeedbackSubmitted EventType = "bid.feedback_submitted"
+)
+
+// EventType Тип доменного события
+type EventType string
+
+// EventTypes все известные типы доменных событий
+var EventTypes = []EventType{
+	EventTenderPublished,
+	EventTenderClosed,
+	EventTenderEdited,
+	EventBidCreated,
+	EventBidEdited,
+	EventBidStatusChanged,
+	EventBidDecisionSubmitted,
+	EventBidFeedbackSubmitted,
+}
+
+// Event Доменное событие, записанное в outbox вместе с изменением состояния
+type Event struct {
+	// Id Монотонно растущий идентификатор события
+	Id int64 `json:"id"`
+
+	// Type Тип события
+	Type EventType `json:"type"`
+
+	// AggregateId Идентификатор тендера или предложения, к которому относится событие
+	AggregateId string `json:"aggregateId"`
+
+	// Payload Снимок сущности или данные действия в JSON
+	Payload json.RawMessage `json:"payload"`
+
+	// CreatedAt Время фиксации события
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Attempts Число неудачных попыток доставки, служебное поле outbox
+	Attempts int `json:"-"`
+}
+
+// BidDecisionEvent Данные события bid.decision_submitted
+type BidDecisionEvent struct {
+	BidId    BidId       `json:"bidId"`
+	TenderId TenderId    `json:"tenderId"`
+	Decision BidDecision `json:"decision"`
+	Username Username    `json:"username"`
+
+	// BidStatus Статус предложения после голоса: Published, пока нет кворума
+	BidStatus BidStatus `json:"bidStatus"`
+}
+
+// BidFeedbackEvent Данные события bid.feedback_submitted
+type BidFeedbackEvent struct {
+	BidId       BidId       `json:"bidId"`
+	TenderId    TenderId    `json:"tenderId"`
+	BidFeedback BidFeedback `json:"bidFeedback"`
+	Username    Username    `json:"username"`
+}
+
+// TenderId возвращает тендер, к которому относится событие: для событий тендера
+// это сам агрегат, для событий предложения — поле tenderId в Payload
+func (e Event) TenderId() TenderId {
+	switch e.Type {
+	case EventTenderPublished, EventTenderClosed, EventTenderEdited:
+		return e.AggregateId
+	}
+	var ref struct {
+		TenderId TenderId `json:"tenderId"`
+	}
+	_ = json.Unmarshal(e.Payload, &ref)
+	return ref.TenderId
+}
+
+// StreamEvent Событие с данными, нужными для фильтрации подписок и проверки видимости
+type StreamEvent struct {
+	Event
+
+	// OrganizationId Организация тендера, к которому относится событие
+	OrganizationId OrganizationId
+
+	// ServiceType Вид услуги тендера
+	ServiceType TenderServiceType
+
+	// BidAuthor Пользователь, создавший предложение; пусто для событий тендера
+	BidAuthor Username
+
+	// BidAuthorType Тип автора предложения; пусто для событий тендера
+	BidAuthorType BidAuthorType
+
+	// BidAuthorId Организация или пользователь — автор предложения
+	BidAuthorId BidAuthorId
+
+	// BidStatus Текущий статус предложения
+	BidStatus BidStatus
+}
@@ -0,0 +1,30 @@
+package model
+
+// Defines values for HealthStatus.
+const (
+	HealthOk          HealthStatus = "ok"
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// HealthStatus Состояние сервиса или зависимости
+type HealthStatus string
+
+// HealthCheck Результат проверки одной зависимости
+type HealthCheck struct {
+	Status HealthStatus `json:"status"`
+
+	// LatencyMs Длительность проверки в миллисекундах
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Error Причина недоступности
+	Error string `json:"error,omitempty"`
+
+	// Details Дополнительные сведения, например версии миграций
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport Ответ эндпоинтов /health/live и /health/ready
+type HealthReport struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
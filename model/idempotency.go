@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IdempotencyRecord запрос с ключом идемпотентности и сохраненный ответ на него.
+// Пока запрос выполняется, StatusCode равен 0.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed сохранен ли ответ
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,55 @@
+package model
+
+// Defines values for TenderImportRowStatus.
+const (
+	TenderImportRowCreated TenderImportRowStatus = "Created"
+	TenderImportRowValid   TenderImportRowStatus = "Valid"
+	TenderImportRowFailed  TenderImportRowStatus = "Failed"
+	TenderImportRowSkipped TenderImportRowStatus = "Skipped"
+)
+
+// TenderImportRowStatus Результат импорта строки: Created — тендер создан, Valid — строка
+// прошла проверку в режиме dryRun, Failed — ошибка в строке, Skipped — строка верна,
+// но импорт в одной транзакции (batchSize=0) откачен из-за ошибки в другой строке
+type TenderImportRowStatus string
+
+// TenderImportRow Результат импорта одной строки файла
+type TenderImportRow struct {
+	// Row Номер записи с 1: в CSV — без строки заголовка, в JSONL — номер строки файла
+	Row int `json:"row"`
+
+	Status TenderImportRowStatus `json:"status"`
+
+	// TenderId Идентификатор созданного тендера
+	TenderId TenderId `json:"tenderId,omitempty"`
+
+	// Error Причина ошибки для статуса Failed
+	Error string `json:"error,omitempty"`
+}
+
+// TenderImportReport Отчет о массовом импорте тендеров (POST /tenders/import)
+type TenderImportReport struct {
+	DryRun bool `json:"dryRun"`
+
+	// BatchSize Размер пакета (по умолчанию 100); 0 — все строки в одной транзакции
+	BatchSize int `json:"batchSize"`
+
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+
+	Rows []TenderImportRow `json:"rows"`
+}
+
+// ImportTendersParams defines parameters for ImportTenders.
+type ImportTendersParams struct {
+	// Format Формат файла: csv или jsonl; по умолчанию определяется по Content-Type
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+
+	// DryRun Проверить файл без сохранения тендеров
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+
+	// BatchSize Сохранять строки пакетами такого размера (по умолчанию 100, не больше 500), каждый в своей транзакции;
+	// 0 — одна транзакция на весь файл, не больше 500 строк
+	BatchSize *int32 `form:"batchSize,omitempty" json:"batchSize,omitempty"`
+}
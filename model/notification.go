@@ -0,0 +1,45 @@
+package model
+
+// Defines values for NotificationKind.
+const (
+	NotificationBidReceived      NotificationKind = "bid_received"
+	NotificationDecisionRequired NotificationKind = "decision_required"
+	NotificationBidApproved      NotificationKind = "bid_approved"
+	NotificationBidRejected      NotificationKind = "bid_rejected"
+)
+
+// NotificationKind Вид email-уведомления
+type NotificationKind string
+
+// NotificationPreferences Настройки email-уведомлений пользователя
+type NotificationPreferences struct {
+	// Username Пользователь, которому принадлежат настройки
+	Username Username `json:"username"`
+
+	// Email Адрес для уведомлений
+	Email string `json:"email"`
+
+	// Locale Язык писем: ru или en
+	Locale string `json:"locale"`
+
+	// BidReceived Уведомлять ответственного о новом предложении на тендер
+	BidReceived bool `json:"bidReceived"`
+
+	// DecisionRequired Уведомлять ответственного, что по предложению нужно решение
+	DecisionRequired bool `json:"decisionRequired"`
+
+	// BidDecision Уведомлять автора о согласовании или отклонении предложения
+	BidDecision bool `json:"bidDecision"`
+}
+
+// Notification Письмо в очереди отправки
+type Notification struct {
+	Id       int64            `json:"id"`
+	EventId  int64            `json:"eventId"`
+	Username Username         `json:"username"`
+	Email    string           `json:"email"`
+	Kind     NotificationKind `json:"kind"`
+	Subject  string           `json:"subject"`
+	Body     string           `json:"body"`
+	Attempts int              `json:"attempts"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Defines values for SearchResultKind.
+const (
+	SearchResultKindBid    SearchResultKind = "bid"
+	SearchResultKindTender SearchResultKind = "tender"
+)
+
+// SearchResultKind Тип найденной сущности
+type SearchResultKind string
+
+// SearchParams defines parameters for Search.
+type SearchParams struct {
+	// Query Поисковый запрос в свободной форме по названию и описанию
+	Query string `query:"q"`
+
+	// Kind Ограничивает поиск только тендерами или только предложениями
+	Kind *SearchResultKind `query:"kind"`
+
+	// Status Статус тендера или предложения
+	Status *string `query:"status"`
+
+	// ServiceType Вид услуги тендера (для предложений берется из связанного тендера)
+	ServiceType *TenderServiceType `query:"service_type"`
+
+	// OrganizationId Организация тендера (для предложений берется из связанного тендера)
+	OrganizationId *OrganizationId `query:"organizationId"`
+
+	// CreatedFrom Нижняя граница даты создания, RFC3339
+	CreatedFrom *time.Time `query:"createdFrom"`
+
+	// CreatedTo Верхняя граница даты создания, RFC3339
+	CreatedTo *time.Time `query:"createdTo"`
+
+	// Limit Максимальное число возвращаемых объектов.
+	Limit *int32 `query:"limit"`
+
+	// Offset Какое количество объектов должно быть пропущено с начала.
+	Offset *int32 `query:"offset"`
+}
+
+// SearchResult Найденный тендер или предложение с оценкой релевантности
+type SearchResult struct {
+	// Kind Тип найденной сущности
+	Kind SearchResultKind `json:"kind"`
+
+	// Rank Релевантность результата, чем больше, тем выше в выдаче
+	Rank float32 `json:"rank"`
+
+	// Tender Найденный тендер, если Kind = tender
+	Tender *Tender `json:"tender,omitempty"`
+
+	// Bid Найденное предложение, если Kind = bid
+	Bid *Bid `json:"bid,omitempty"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// APIToken токен доступа сотрудника к API; сам токен показывается только при создании
+type APIToken struct {
+	Id        string
+	Username  string
+	Name      string
+	Token     string
+	CreatedAt time.Time
+	// ExpiresAt nil — бессрочный токен
+	ExpiresAt *time.Time
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "Pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "Delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "Failed"
+)
+
+// WebhookDeliveryStatus Статус доставки вебхука
+type WebhookDeliveryStatus string
+
+// WebhookId Уникальный идентификатор подписки на вебхук, присвоенный сервером.
+type WebhookId = string
+
+// Webhook Подписка организации на доменные события
+type Webhook struct {
+	// Id Уникальный идентификатор подписки
+	Id WebhookId `json:"id"`
+
+	// OrganizationId Организация, получающая уведомления
+	OrganizationId OrganizationId `json:"organizationId"`
+
+	// Url Адрес, на который отправляются события
+	Url string `json:"url"`
+
+	// Events Типы событий; пустой список — все события
+	Events []EventType `json:"events"`
+
+	// Secret Ключ подписи HMAC-SHA256, возвращается только при создании
+	Secret string `json:"secret,omitempty"`
+
+	// CreatedAt Дата создания подписки в формате RFC3339
+	CreatedAt string `json:"createdAt"`
+}
+
+// WebhooksNewBody defines model for webhooks_new_body.
+type WebhooksNewBody struct {
+	// OrganizationId Организация, получающая уведомления
+	OrganizationId OrganizationId `json:"organizationId"`
+
+	// Url Адрес, на который отправляются события
+	Url string `json:"url"`
+
+	// Events Типы событий; пустой список — все события
+	Events []EventType `json:"events"`
+}
+
+// WebhookDelivery Запись журнала доставки события в вебхук
+type WebhookDelivery struct {
+	Id        int64                 `json:"id"`
+	WebhookId WebhookId             `json:"webhookId"`
+	EventId   int64                 `json:"eventId"`
+	EventType EventType             `json:"eventType"`
+	Status    WebhookDeliveryStatus `json:"status"`
+
+	// Attempts Число выполненных попыток
+	Attempts int `json:"attempts"`
+
+	// ResponseCode HTTP-код последнего ответа получателя, 0 — ответа не было
+	ResponseCode int `json:"responseCode"`
+
+	// LastError Описание последней ошибки
+	LastError string `json:"lastError,omitempty"`
+
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+
+	// Payload тело запроса и секрет подписки, нужны только отправителю
+	Payload []byte `json:"-"`
+	Url     string `json:"-"`
+	Secret  string `json:"-"`
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/outbox"
+)
+
+// Store настройки получателей и очередь писем; реализуется storage.PostgresStorage
+type Store interface {
+	ResponsibleRecipients(ctx context.Context, tenderId string, kind model.NotificationKind) ([]model.NotificationPreferences, error)
+	BidAuthorRecipients(ctx context.Context, bidId string, kind model.NotificationKind) ([]model.NotificationPreferences, error)
+	EnqueueNotifications(ctx context.Context, notifications []model.Notification) error
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]model.Notification, error)
+	RecordNotificationAttempt(ctx context.Context, id int64, sendErr error, retryAt *time.Time) error
+}
+
+// Sink получатель outbox: превращает события предложений в письма и ставит их
+// в очередь. Письма рендерятся сразу, чтобы повторы отправляли тот же текст.
+type Sink struct {
+	store     Store
+	templates *Templates
+}
+
+// NewSink конструктор Sink
+func NewSink(store Store, templates *Templates) *Sink {
+	return &Sink{store: store, templates: templates}
+}
+
+func (s *Sink) Name() string {
+	return "email"
+}
+
+func (s *Sink) Deliver(ctx context.Context, event model.Event) error {
+	switch event.Type {
+	case model.EventBidCreated, model.EventBidStatusChanged:
+		var bid model.Bid
+		if err := json.Unmarshal(event.Payload, &bid); err != nil {
+			return err
+		}
+		// Черновик ответственные не видят: письма уходят при публикации
+		if bid.Status != model.BidStatusPublished {
+			return nil
+		}
+		data := TemplateData{TenderId: bid.TenderId, BidId: bid.Id, BidName: bid.Name}
+		required, err := s.store.ResponsibleRecipients(ctx, bid.TenderId, model.NotificationDecisionRequired)
+		if err != nil {
+			return err
+		}
+		if err := s.enqueue(ctx, event, model.NotificationDecisionRequired, required, data); err != nil {
+			return err
+		}
+		received, err := s.store.ResponsibleRecipients(ctx, bid.TenderId, model.NotificationBidReceived)
+		if err != nil {
+			return err
+		}
+		// Тем, кто получил запрос решения, второе письмо о том же предложении не нужно
+		notified := make(map[string]bool, len(required))
+		for _, r := range required {
+			notified[r.Username] = true
+		}
+		var rest []model.NotificationPreferences
+		for _, r := range received {
+			if !notified[r.Username] {
+				rest = append(rest, r)
+			}
+		}
+		return s.enqueue(ctx, event, model.NotificationBidReceived, rest, data)
+
+	case model.EventBidDecisionSubmitted:
+		var decision model.BidDecisionEvent
+		if err := json.Unmarshal(event.Payload, &decision); err != nil {
+			return err
+		}
+		// Отдельный голос не решает судьбу предложения: письмо автору уходит,
+		// когда голос привел к кворуму или отклонению
+		var kind model.NotificationKind
+		switch decision.BidStatus {
+		case model.BidStatusApproved:
+			kind = model.NotificationBidApproved
+		case model.BidStatusRejected:
+			kind = model.NotificationBidRejected
+		default:
+			return nil
+		}
+		recipients, err := s.store.BidAuthorRecipients(ctx, decision.BidId, kind)
+		if err != nil {
+			return err
+		}
+		data := TemplateData{TenderId: decision.TenderId, BidId: decision.BidId}
+		return s.enqueue(ctx, event, kind, recipients, data)
+	}
+	return nil
+}
+
+func (s *Sink) enqueue(ctx context.Context, event model.Event, kind model.NotificationKind, recipients []model.NotificationPreferences, data TemplateData) error {
+	notifications := make([]model.Notification, 0, len(recipients))
+	for _, r := range recipients {
+		data.Username = r.Username
+		subject, body, err := s.templates.Render(r.Locale, kind, data)
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, model.Notification{
+			EventId:  event.Id,
+			Username: r.Username,
+			Email:    r.Email,
+			Kind:     kind,
+			Subject:  subject,
+			Body:     body,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	return s.store.EnqueueNotifications(ctx, notifications)
+}
+
+// Worker отправляет письма из очереди с повторами и экспоненциальной задержкой
+type Worker struct {
+	store     Store
+	transport Transport
+	logger    outbox.Logger
+	config    outbox.Config
+}
+
+// NewWorker конструктор Worker; параметры опроса и повторов те же, что у outbox.Relay
+func NewWorker(store Store, transport Transport, logger outbox.Logger, cfg outbox.Config) *Worker {
+	return &Worker{
+		store:     store,
+		transport: transport,
+		logger:    logger,
+		config:    cfg,
+	}
+}
+
+// Run обрабатывает очередь до отмены ctx
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		n, err := w.ProcessBatch(ctx)
+		if err != nil {
+			w.logger.Error("notification worker error: ", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// ProcessBatch отправляет одну пачку писем, возвращает их число
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	notifications, err := w.store.ClaimNotifications(ctx, w.config.BatchSize, w.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range notifications {
+		sendErr := w.transport.Send(ctx, n.Email, n.Subject, n.Body)
+
+		var retryAt *time.Time
+		attempt := n.Attempts + 1
+		if sendErr != nil {
+			w.logger.Error("notification ", n.Id, " to ", n.Email, " failed: ", sendErr)
+			if attempt < w.config.MaxAttempts {
+				t := time.Now().Add(outbox.Backoff(w.config.BaseBackoff, w.config.MaxBackoff, attempt))
+				retryAt = &t
+			}
+		}
+		if err := w.store.RecordNotificationAttempt(ctx, n.Id, sendErr, retryAt); err != nil {
+			w.logger.Error("notification record attempt error: ", err)
+		}
+	}
+	return len(notifications), nil
+}
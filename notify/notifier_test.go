@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// fakeStore хранилище с фиксированными получателями; запоминает поставленные письма
+type fakeStore struct {
+	responsibles map[model.NotificationKind][]model.NotificationPreferences
+	authors      []model.NotificationPreferences
+	queued       []model.Notification
+}
+
+func (f *fakeStore) ResponsibleRecipients(ctx context.Context, tenderId string, kind model.NotificationKind) ([]model.NotificationPreferences, error) {
+	return f.responsibles[kind], nil
+}
+
+func (f *fakeStore) BidAuthorRecipients(ctx context.Context, bidId string, kind model.NotificationKind) ([]model.NotificationPreferences, error) {
+	return f.authors, nil
+}
+
+func (f *fakeStore) EnqueueNotifications(ctx context.Context, notifications []model.Notification) error {
+	f.queued = append(f.queued, notifications...)
+	return nil
+}
+
+func (f *fakeStore) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]model.Notification, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) RecordNotificationAttempt(ctx context.Context, id int64, sendErr error, retryAt *time.Time) error {
+	return nil
+}
+
+func newTestSink(t *testing.T, store *fakeStore) *Sink {
+	t.Helper()
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSink(store, templates)
+}
+
+func recipient(username string) model.NotificationPreferences {
+	return model.NotificationPreferences{Username: username, Email: username + "@example.com", Locale: DefaultLocale}
+}
+
+func event(t *testing.T, eventType model.EventType, payload interface{}) model.Event {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.Event{Id: 1, Type: eventType, Payload: raw}
+}
+
+func queuedKinds(queued []model.Notification) map[string]model.NotificationKind {
+	kinds := map[string]model.NotificationKind{}
+	for _, n := range queued {
+		kinds[n.Username] = n.Kind
+	}
+	return kinds
+}
+
+func TestSinkBidNotifications(t *testing.T) {
+	responsibles := map[model.NotificationKind][]model.NotificationPreferences{
+		model.NotificationDecisionRequired: {recipient("a"), recipient("b")},
+		model.NotificationBidReceived:      {recipient("b"), recipient("c")},
+	}
+
+	tests := []struct {
+		name  string
+		event model.Event
+		want  map[string]model.NotificationKind
+	}{
+		{
+			name:  "draft bid is invisible to responsibles",
+			event: event(t, model.EventBidCreated, model.Bid{Id: "bid", Status: model.BidStatusCreated}),
+			want:  map[string]model.NotificationKind{},
+		},
+		{
+			name:  "published bid",
+			event: event(t, model.EventBidStatusChanged, model.Bid{Id: "bid", Status: model.BidStatusPublished}),
+			want: map[string]model.NotificationKind{
+				"a": model.NotificationDecisionRequired,
+				"b": model.NotificationDecisionRequired,
+				"c": model.NotificationBidReceived,
+			},
+		},
+		{
+			name:  "canceled bid",
+			event: event(t, model.EventBidStatusChanged, model.Bid{Id: "bid", Status: model.BidStatusCanceled}),
+			want:  map[string]model.NotificationKind{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{responsibles: responsibles}
+			if err := newTestSink(t, store).Deliver(context.Background(), tt.event); err != nil {
+				t.Fatal(err)
+			}
+			if got := queuedKinds(store.queued); len(got) != len(tt.want) || len(store.queued) != len(tt.want) {
+				t.Fatalf("queued %v, want %v", got, tt.want)
+			}
+			for username, kind := range tt.want {
+				if got := queuedKinds(store.queued)[username]; got != kind {
+					t.Errorf("%s got %q, want %q", username, got, kind)
+				}
+			}
+		})
+	}
+}
+
+// Автору пишут об итоге, а не о каждом голосе
+func TestSinkDecisionFollowsBidStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision model.BidDecisionEvent
+		want     model.NotificationKind
+	}{
+		{"approval below quorum", model.BidDecisionEvent{Decision: model.BidDecisionApproved, BidStatus: model.BidStatusPublished}, ""},
+		{"approval reaching quorum", model.BidDecisionEvent{Decision: model.BidDecisionApproved, BidStatus: model.BidStatusApproved}, model.NotificationBidApproved},
+		{"rejection", model.BidDecisionEvent{Decision: model.BidDecisionRejected, BidStatus: model.BidStatusRejected}, model.NotificationBidRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{authors: []model.NotificationPreferences{recipient("author")}}
+			if err := newTestSink(t, store).Deliver(context.Background(), event(t, model.EventBidDecisionSubmitted, tt.decision)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if len(store.queued) != 0 {
+					t.Fatalf("queued %v, want nothing", store.queued)
+				}
+				return
+			}
+			if len(store.queued) != 1 || store.queued[0].Kind != tt.want {
+				t.Fatalf("queued %v, want one %q", store.queued, tt.want)
+			}
+		})
+	}
+}
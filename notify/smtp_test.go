@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// fakeSMTPServer минимальный SMTP-сервер, принимающий одно письмо и отдающий его в канал
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				messages <- data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func TestSMTPTransportSendsRenderedTemplate(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, body, err := templates.Render("ru", model.NotificationBidApproved, TemplateData{
+		Username: "user1",
+		TenderId: "tender-1",
+		BidId:    "bid-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, messages := fakeSMTPServer(t)
+	transport := NewSMTPTransport(addr, "", "", "tenders@example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := transport.Send(ctx, "user1@example.com", subject, body); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var raw string
+	select {
+	case raw = <-messages:
+	case <-ctx.Done():
+		t.Fatal("message was not received")
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.Header.Get("To"); got != "user1@example.com" {
+		t.Errorf("To = %q", got)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != "Предложение согласовано" {
+		t.Errorf("Subject = %q", decoded)
+	}
+}
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := templates.Render(DefaultLocale, model.NotificationBidRejected, TemplateData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := templates.Render("de", model.NotificationBidRejected, TemplateData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("subject = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"go-tenders/model"
+)
+
+// DefaultLocale язык писем, если у пользователя не задан или не поддерживается
+const DefaultLocale = "ru"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// TemplateData данные, доступные в шаблонах писем
+type TemplateData struct {
+	Username model.Username
+	TenderId model.TenderId
+	BidId    model.BidId
+	BidName  model.BidName
+}
+
+// Templates шаблоны писем по языкам
+type Templates struct {
+	locales map[string]*template.Template
+}
+
+// LoadTemplates загружает встроенные шаблоны ru и en
+func LoadTemplates() (*Templates, error) {
+	t := &Templates{locales: make(map[string]*template.Template)}
+	for _, locale := range []string{"ru", "en"} {
+		tmpl, err := template.ParseFS(templateFS, "templates/"+locale+".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		t.locales[locale] = tmpl
+	}
+	return t, nil
+}
+
+// SupportsLocale сообщает, есть ли шаблоны для языка locale
+func (t *Templates) SupportsLocale(locale string) bool {
+	_, ok := t.locales[locale]
+	return ok
+}
+
+// Render возвращает тему и текст письма вида kind на языке locale
+func (t *Templates) Render(locale string, kind model.NotificationKind, data TemplateData) (subject, body string, err error) {
+	tmpl, ok := t.locales[locale]
+	if !ok {
+		tmpl = t.locales[DefaultLocale]
+	}
+
+	var sb strings.Builder
+	if err := tmpl.ExecuteTemplate(&sb, string(kind)+".subject", data); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", kind, err)
+	}
+	subject = sb.String()
+
+	sb.Reset()
+	if err := tmpl.ExecuteTemplate(&sb, string(kind)+".body", data); err != nil {
+		return "", "", fmt.Errorf("render %s body: %w", kind, err)
+	}
+	return subject, sb.String(), nil
+}
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-tenders/model"
+)
+
+// Store источник событий outbox; реализуется storage.PostgresStorage
+type Store interface {
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error)
+	MarkEventDelivered(ctx context.Context, id int64) error
+	MarkEventFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error
+}
+
+// Sink получатель доменных событий. Доставка «как минимум один раз»:
+// получатель должен быть готов к повторам и отбрасывать дубликаты по Event.Id.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event model.Event) error
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(args ...interface{})
+	Error(args ...interface{})
+}
+
+// Config параметры relay
+type Config struct {
+	// PollInterval пауза между опросами, когда событий нет
+	PollInterval time.Duration
+	// BatchSize число событий, забираемых за один опрос
+	BatchSize int
+	// Lease время, на которое событие резервируется за relay
+	Lease time.Duration
+	// MaxAttempts после стольких неудач событие больше не доставляется
+	MaxAttempts int
+	// BaseBackoff задержка перед первой повторной попыткой, далее удваивается
+	BaseBackoff time.Duration
+	// MaxBackoff верхняя граница задержки
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig значения по умолчанию
+var DefaultConfig = Config{
+	PollInterval: time.Second,
+	BatchSize:    100,
+	Lease:        30 * time.Second,
+	MaxAttempts:  10,
+	BaseBackoff:  time.Second,
+	MaxBackoff:   10 * time.Minute,
+}
+
+// Relay читает события из outbox и доставляет их во все получатели
+type Relay struct {
+	store  Store
+	sinks  []Sink
+	logger Logger
+	config Config
+	now    func() time.Time
+}
+
+// NewRelay конструктор relay
+func NewRelay(store Store, logger Logger, cfg Config, sinks ...Sink) *Relay {
+	return &Relay{
+		store:  store,
+		sinks:  sinks,
+		logger: logger,
+		config: cfg,
+		now:    time.Now,
+	}
+}
+
+// Run обрабатывает outbox до отмены ctx
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.ProcessBatch(ctx)
+		if err != nil {
+			r.logger.Error("outbox relay error: ", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+// ProcessBatch забирает и доставляет одну пачку событий, возвращает их число
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := r.store.ClaimEvents(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := r.deliver(ctx, event); err != nil {
+			r.fail(ctx, event, err)
+			continue
+		}
+		if err := r.store.MarkEventDelivered(ctx, event.Id); err != nil {
+			// Событие будет выдано повторно после истечения lease
+			r.logger.Error("outbox mark delivered error: ", err)
+		}
+	}
+	return len(events), nil
+}
+
+// deliver отправляет событие во все получатели. При ошибке любого из них
+// событие повторяется целиком, поэтому остальные могут получить его дважды.
+func (r *Relay) deliver(ctx context.Context, event model.Event) error {
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Relay) fail(ctx context.Context, event model.Event, deliverErr error) {
+	attempt := event.Attempts + 1
+	var retryAt *time.Time
+	if attempt < r.config.MaxAttempts {
+		t := r.now().Add(Backoff(r.config.BaseBackoff, r.config.MaxBackoff, attempt))
+		retryAt = &t
+	}
+
+	r.logger.Error(fmt.Sprintf("outbox event %d (%s) attempt %d failed: ", event.Id, event.Type, attempt), deliverErr)
+	if err := r.store.MarkEventFailed(ctx, event.Id, deliverErr.Error(), retryAt); err != nil {
+		r.logger.Error("outbox mark failed error: ", err)
+	}
+}
+
+// Backoff экспоненциальная задержка перед попыткой attempt+1: base, 2*base, 4*base... не более maxDelay
+func Backoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxDelay)
+}
@@ -0,0 +1,273 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// memoryStore повторяет семантику outbox из storage: ClaimEvents откладывает
+// выдачу события на lease, MarkEventFailed переносит ее на retryAt или
+// отбрасывает событие, если retryAt равен nil
+type memoryStore struct {
+	mu     sync.Mutex
+	now    time.Time
+	events []*storedEvent
+	// markErr ошибка MarkEventDelivered, имитирует падение relay после доставки
+	markErr error
+}
+
+type storedEvent struct {
+	event       model.Event
+	nextAttempt time.Time
+	delivered   bool
+	dead        bool
+	lastError   string
+}
+
+func newMemoryStore(ids ...int64) *memoryStore {
+	s := &memoryStore{now: time.Now()}
+	for _, id := range ids {
+		s.events = append(s.events, &storedEvent{
+			event:       model.Event{Id: id, Type: model.EventBidCreated, AggregateId: "bid"},
+			nextAttempt: s.now,
+		})
+	}
+	return s
+}
+
+// advance сдвигает часы хранилища
+func (s *memoryStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+func (s *memoryStore) get(id int64) storedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.event.Id == id {
+			return *e
+		}
+	}
+	return storedEvent{}
+}
+
+func (s *memoryStore) ClaimEvents(_ context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []model.Event
+	for _, e := range s.events {
+		if len(claimed) == limit {
+			break
+		}
+		if e.delivered || e.dead || e.nextAttempt.After(s.now) {
+			continue
+		}
+		e.nextAttempt = s.now.Add(lease)
+		claimed = append(claimed, e.event)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkEventDelivered(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.markErr != nil {
+		return s.markErr
+	}
+	for _, e := range s.events {
+		if e.event.Id == id {
+			e.delivered = true
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkEventFailed(_ context.Context, id int64, reason string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.event.Id != id {
+			continue
+		}
+		e.event.Attempts++
+		e.lastError = reason
+		if retryAt == nil {
+			e.dead = true
+		} else {
+			e.nextAttempt = *retryAt
+		}
+	}
+	return nil
+}
+
+// failingSink отклоняет первые failures доставок и запоминает все попытки
+type failingSink struct {
+	failures int
+	attempts []int64
+}
+
+func (s *failingSink) Name() string { return "failing" }
+
+func (s *failingSink) Deliver(_ context.Context, event model.Event) error {
+	s.attempts = append(s.attempts, event.Id)
+	if len(s.attempts) <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Info(...interface{})  {}
+func (discardLogger) Error(...interface{}) {}
+
+func testConfig() Config {
+	return Config{BatchSize: 10, Lease: time.Minute, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute}
+}
+
+// newTestRelay создает relay, живущий по часам хранилища
+func newTestRelay(store *memoryStore, sinks ...Sink) *Relay {
+	r := NewRelay(store, discardLogger{}, testConfig(), sinks...)
+	r.now = func() time.Time {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.now
+	}
+	return r
+}
+
+func processBatch(t *testing.T, r *Relay) int {
+	t.Helper()
+	n, err := r.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessBatch: %v", err)
+	}
+	return n
+}
+
+// Неудачная доставка повторяется после задержки Backoff, а не раньше
+func TestRelayRetriesWithBackoff(t *testing.T) {
+	store := newMemoryStore(1)
+	sink := &failingSink{failures: 2}
+	relay := newTestRelay(store, sink)
+
+	processBatch(t, relay)
+	e := store.get(1)
+	if e.delivered || e.event.Attempts != 1 || e.lastError != "sink failing: sink unavailable" {
+		t.Fatalf("after first failure: %+v", e)
+	}
+	if delay := e.nextAttempt.Sub(store.now); delay != testConfig().BaseBackoff {
+		t.Fatalf("retry scheduled in %v, want %v", delay, testConfig().BaseBackoff)
+	}
+
+	if n := processBatch(t, relay); n != 0 {
+		t.Fatalf("event redelivered before backoff expired")
+	}
+	store.advance(testConfig().BaseBackoff)
+	processBatch(t, relay)
+	if e := store.get(1); e.event.Attempts != 2 || e.nextAttempt.Sub(store.now) != 2*time.Second {
+		t.Fatalf("second failure should double the delay: %+v", e)
+	}
+
+	store.advance(time.Second)
+	if n := processBatch(t, relay); n != 0 {
+		t.Fatalf("event redelivered before doubled backoff expired")
+	}
+	store.advance(time.Second)
+	processBatch(t, relay)
+	if e := store.get(1); !e.delivered || e.event.Attempts != 2 {
+		t.Fatalf("event not delivered on third attempt: %+v", e)
+	}
+	if len(sink.attempts) != 3 {
+		t.Fatalf("sink got %d attempts, want 3", len(sink.attempts))
+	}
+}
+
+// После MaxAttempts неудач событие отбрасывается и больше не выдается
+func TestRelayDropsEventAfterMaxAttempts(t *testing.T) {
+	store := newMemoryStore(1, 2)
+	sink := &failingSink{failures: 100}
+	relay := newTestRelay(store, sink)
+
+	for range testConfig().MaxAttempts {
+		processBatch(t, relay)
+		store.advance(time.Hour)
+	}
+	for _, id := range []int64{1, 2} {
+		if e := store.get(id); !e.dead || e.event.Attempts != testConfig().MaxAttempts {
+			t.Fatalf("event %d: %+v", id, e)
+		}
+	}
+	if n := processBatch(t, relay); n != 0 {
+		t.Fatalf("dead events claimed again: %d", n)
+	}
+}
+
+// Если relay не отметил доставку (упал или потерял базу), событие выдается
+// повторно только после истечения lease — доставка «как минимум один раз»
+func TestRelayRedeliversAfterLeaseExpiry(t *testing.T) {
+	store := newMemoryStore(1)
+	store.markErr = errors.New("connection lost")
+	sink := &failingSink{}
+	relay := newTestRelay(store, sink)
+
+	processBatch(t, relay)
+	store.advance(testConfig().Lease - time.Second)
+	if n := processBatch(t, relay); n != 0 {
+		t.Fatalf("event claimed again while leased")
+	}
+
+	store.markErr = nil
+	store.advance(2 * time.Second)
+	processBatch(t, relay)
+	if e := store.get(1); !e.delivered || e.event.Attempts != 0 {
+		t.Fatalf("event not redelivered after lease: %+v", e)
+	}
+	if len(sink.attempts) != 2 {
+		t.Fatalf("sink got %d deliveries, want 2", len(sink.attempts))
+	}
+}
+
+// Ошибка одного получателя повторяет событие для всех
+func TestRelayRetriesWholeEventOnSinkError(t *testing.T) {
+	store := newMemoryStore(1)
+	var first []int64
+	ok := SinkFunc{SinkName: "ok", Fn: func(_ context.Context, e model.Event) error {
+		first = append(first, e.Id)
+		return nil
+	}}
+	relay := newTestRelay(store, ok, &failingSink{failures: 1})
+
+	processBatch(t, relay)
+	store.advance(time.Minute)
+	processBatch(t, relay)
+	if len(first) != 2 || !store.get(1).delivered {
+		t.Fatalf("first sink deliveries = %v, delivered = %v", first, store.get(1).delivered)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{100, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(time.Second, time.Minute, tt.attempt); got != tt.want {
+			t.Errorf("Backoff(attempt %d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
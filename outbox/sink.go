@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"context"
+
+	"go-tenders/model"
+)
+
+// LogSink записывает события в лог; полезен для отладки и как получатель по умолчанию
+type LogSink struct {
+	logger Logger
+}
+
+// NewLogSink конструктор LogSink
+func NewLogSink(logger Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event model.Event) error {
+	s.logger.Info("event ", event.Id, " ", event.Type, " ", event.AggregateId, " ", string(event.Payload))
+	return nil
+}
+
+// SinkFunc адаптер функции к интерфейсу Sink
+type SinkFunc struct {
+	SinkName string
+	Fn       func(ctx context.Context, event model.Event) error
+}
+
+func (f SinkFunc) Name() string {
+	return f.SinkName
+}
+
+func (f SinkFunc) Deliver(ctx context.Context, event model.Event) error {
+	return f.Fn(ctx, event)
+}
@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Defines values for Scope.
+const (
+	ScopeUser         Scope = "user"
+	ScopeOrganization Scope = "organization"
+	ScopeIP           Scope = "ip"
+)
+
+// Scope по чему считается квота
+type Scope string
+
+// ClassLimits квоты на чтение (GET, HEAD, OPTIONS) и на запись (остальные методы)
+type ClassLimits struct {
+	Read  Limit
+	Write Limit
+}
+
+// Limits действующие квоты по каждому ключу
+type Limits struct {
+	Enabled      bool
+	User         ClassLimits
+	Organization ClassLimits
+	IP           ClassLimits
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Error(args ...interface{})
+}
+
+// Config параметры middleware
+type Config struct {
+	Store Store
+	// Limits возвращает действующие квоты; вызывается на каждый запрос, чтобы
+	// перезагруженная конфигурация применялась сразу
+	Limits func() Limits
+	// User определяет пользователя запроса; пустая строка — квота пользователя
+	// не применяется. Вызывается только после проверки квоты IP.
+	User func(ctx echo.Context) string
+	// Organizations определяет организации пользователя. Вызывается только
+	// после проверки квот IP и пользователя, поэтому может обращаться к базе.
+	Organizations func(ctx echo.Context, user string) ([]string, error)
+	// Skipper запросы, которые не ограничиваются
+	Skipper func(ctx echo.Context) bool
+	Logger  Logger
+}
+
+// Middleware ограничивает частоту запросов. Квоты проверяются по очереди:
+// IP клиента, пользователь, его организации; при превышении любой из них
+// следующие не проверяются и возвращается 429 с телом ErrorResponse.
+// Запрос списывается со всех проверенных квот; в заголовки RateLimit-*
+// попадает самая строгая из них. Ошибки хранилища квот не блокируют запросы.
+func Middleware(cfg Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			limits := cfg.Limits()
+			if !limits.Enabled || (cfg.Skipper != nil && cfg.Skipper(ctx)) {
+				return next(ctx)
+			}
+
+			l := &requestLimiter{cfg: cfg, ctx: ctx, write: isWrite(ctx.Request().Method)}
+			if l.take(ScopeIP, ctx.RealIP(), limits.IP) {
+				return l.respond(next)
+			}
+
+			var user string
+			if cfg.User != nil {
+				user = cfg.User(ctx)
+			}
+			if user == "" {
+				return l.respond(next)
+			}
+			if l.take(ScopeUser, user, limits.User) {
+				return l.respond(next)
+			}
+
+			if cfg.Organizations == nil {
+				return l.respond(next)
+			}
+			organizations, err := cfg.Organizations(ctx, user)
+			if err != nil {
+				cfg.Logger.Error("rate limit organizations error: ", err)
+				return l.respond(next)
+			}
+			for _, org := range organizations {
+				if l.take(ScopeOrganization, org, limits.Organization) {
+					break
+				}
+			}
+			return l.respond(next)
+		}
+	}
+}
+
+// requestLimiter списывает запрос с квот и запоминает самый строгий результат
+type requestLimiter struct {
+	cfg       Config
+	ctx       echo.Context
+	write     bool
+	strictest *Result
+	denied    Scope
+}
+
+// take списывает запрос с квоты; true — квота превышена
+func (l *requestLimiter) take(scope Scope, id string, limits ClassLimits) bool {
+	limit, class := limits.Read, "read"
+	if l.write {
+		limit, class = limits.Write, "write"
+	}
+	if id == "" || limit.Unlimited() {
+		return false
+	}
+	key := strings.Join([]string{string(scope), class, id}, ":")
+	res, err := l.cfg.Store.Take(l.ctx.Request().Context(), key, limit)
+	if err != nil {
+		l.cfg.Logger.Error("rate limit store error: ", err)
+		return false
+	}
+	if l.strictest == nil || stricter(res, *l.strictest) {
+		l.strictest = &res
+	}
+	if !res.Allowed {
+		l.denied = scope
+	}
+	return !res.Allowed
+}
+
+// respond выставляет заголовки RateLimit-* и пропускает или отклоняет запрос
+func (l *requestLimiter) respond(next echo.HandlerFunc) error {
+	if l.strictest == nil {
+		return next(l.ctx)
+	}
+	h := l.ctx.Response().Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(l.strictest.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(l.strictest.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(l.strictest.Reset)))
+	if l.denied != "" {
+		h.Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(l.strictest.RetryAfter)))
+		return l.ctx.JSON(http.StatusTooManyRequests, model.ErrorResponse{
+			Reason: "Rate limit exceeded for " + string(l.denied),
+		})
+	}
+	return next(l.ctx)
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// stricter отклоненный запрос строже пропущенного; иначе строже меньший остаток
+func stricter(a, b Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type testLogger struct{ errors int }
+
+func (l *testLogger) Error(args ...interface{}) { l.errors++ }
+
+// limiterTest middleware с квотами limits; считает обращения к User и Organizations
+type limiterTest struct {
+	handler       echo.HandlerFunc
+	userCalls     int
+	orgCalls      int
+	handlerCalls  int
+	user          string
+	organizations []string
+}
+
+func newLimiterTest(limits Limits) *limiterTest {
+	lt := &limiterTest{user: "alice", organizations: []string{"org-1"}}
+	store := NewMemoryStore()
+	store.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	mw := Middleware(Config{
+		Store:  store,
+		Limits: func() Limits { return limits },
+		User: func(echo.Context) string {
+			lt.userCalls++
+			return lt.user
+		},
+		Organizations: func(echo.Context, string) ([]string, error) {
+			lt.orgCalls++
+			return lt.organizations, nil
+		},
+		Logger: &testLogger{},
+	})
+	lt.handler = mw(func(ctx echo.Context) error {
+		lt.handlerCalls++
+		return ctx.NoContent(http.StatusOK)
+	})
+	return lt
+}
+
+func (lt *limiterTest) do(t *testing.T, method, ip string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/tenders", nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	if err := lt.handler(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	return rec
+}
+
+func perMinute(n int) ClassLimits {
+	l := Limit{Requests: n, Period: time.Minute}
+	return ClassLimits{Read: l, Write: l}
+}
+
+// Превышение квоты IP отклоняет запрос до определения пользователя и организаций
+func TestMiddlewareChecksIPFirst(t *testing.T) {
+	lt := newLimiterTest(Limits{Enabled: true, IP: perMinute(1), User: perMinute(10), Organization: perMinute(10)})
+
+	if rec := lt.do(t, http.MethodGet, "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", rec.Code)
+	}
+	rec := lt.do(t, http.MethodGet, "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got == "" {
+		t.Error("Retry-After is not set")
+	}
+	if lt.userCalls != 1 || lt.orgCalls != 1 {
+		t.Errorf("user calls %d, organization calls %d, want 1 and 1", lt.userCalls, lt.orgCalls)
+	}
+	if lt.handlerCalls != 1 {
+		t.Errorf("handler calls %d, want 1", lt.handlerCalls)
+	}
+
+	// Другой IP не затронут
+	if rec := lt.do(t, http.MethodGet, "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("other IP: status %d", rec.Code)
+	}
+}
+
+// Превышение квоты пользователя отклоняет запрос без поиска организаций
+func TestMiddlewareUserLimitBeforeOrganizations(t *testing.T) {
+	lt := newLimiterTest(Limits{Enabled: true, User: perMinute(1), Organization: perMinute(10)})
+
+	lt.do(t, http.MethodPost, "10.0.0.1")
+	rec := lt.do(t, http.MethodPost, "10.0.0.2")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "Rate limit exceeded for user") {
+		t.Errorf("body %q", body)
+	}
+	if lt.orgCalls != 1 {
+		t.Errorf("organization calls %d, want 1", lt.orgCalls)
+	}
+
+	// Квота чтения считается отдельно от записи
+	if rec := lt.do(t, http.MethodGet, "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("read after write limit: status %d", rec.Code)
+	}
+}
+
+// Квота организации общая для всех ее пользователей
+func TestMiddlewareOrganizationLimit(t *testing.T) {
+	lt := newLimiterTest(Limits{Enabled: true, Organization: perMinute(1)})
+
+	lt.do(t, http.MethodGet, "10.0.0.1")
+	lt.user = "bob"
+	rec := lt.do(t, http.MethodGet, "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Rate limit exceeded for organization") {
+		t.Errorf("body %q", rec.Body.String())
+	}
+}
+
+// Без пользователя применяется только квота IP
+func TestMiddlewareAnonymous(t *testing.T) {
+	lt := newLimiterTest(Limits{Enabled: true, IP: perMinute(5), User: perMinute(1), Organization: perMinute(1)})
+	lt.user = ""
+
+	for i := 0; i < 3; i++ {
+		if rec := lt.do(t, http.MethodGet, "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, rec.Code)
+		}
+	}
+	if lt.orgCalls != 0 {
+		t.Errorf("organization calls %d, want 0", lt.orgCalls)
+	}
+}
+
+// В заголовки попадает самая строгая из проверенных квот
+func TestMiddlewareHeaders(t *testing.T) {
+	lt := newLimiterTest(Limits{Enabled: true, IP: perMinute(10), User: perMinute(3)})
+
+	rec := lt.do(t, http.MethodGet, "10.0.0.1")
+	if got := rec.Header().Get("RateLimit-Limit"); got != "3" {
+		t.Errorf("RateLimit-Limit = %q, want 3", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "2" {
+		t.Errorf("RateLimit-Remaining = %q, want 2", got)
+	}
+	if got := rec.Header().Get("RateLimit-Reset"); got != "20" {
+		t.Errorf("RateLimit-Reset = %q, want 20", got)
+	}
+}
+
+// Выключенные квоты не проверяются
+func TestMiddlewareDisabled(t *testing.T) {
+	lt := newLimiterTest(Limits{IP: perMinute(1)})
+
+	for i := 0; i < 3; i++ {
+		if rec := lt.do(t, http.MethodGet, "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, rec.Code)
+		}
+	}
+	if lt.userCalls != 0 {
+		t.Errorf("user calls %d, want 0", lt.userCalls)
+	}
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Result итог списания запроса с квоты
+type Result struct {
+	Allowed bool
+	// Limit размер квоты
+	Limit int
+	// Remaining сколько запросов еще можно сделать сразу
+	Remaining int
+	// Reset через сколько квота восстановится полностью
+	Reset time.Duration
+	// RetryAfter через сколько можно повторить отклоненный запрос
+	RetryAfter time.Duration
+}
+
+// Store хранилище квот. MemoryStore считает запросы в одном процессе; для
+// нескольких экземпляров сервиса нужна реализация поверх общего хранилища
+// (например Redis) с атомарным списанием.
+type Store interface {
+	// Take списывает один запрос с квоты limit для key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// sweepInterval как часто MemoryStore удаляет заполнившиеся корзины
+const sweepInterval = time.Minute
+
+// MemoryStore квоты в памяти процесса по алгоритму token bucket
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore конструктор MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now, limit: limit}
+		m.buckets[key] = b
+	}
+	b.refill(now, limit)
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / limit.rate())
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = seconds((float64(limit.Requests) - b.tokens) / limit.rate())
+	return res, nil
+}
+
+// refill пополняет корзину за время с прошлого обращения. Квота могла
+// измениться при перезагрузке конфигурации: лишние токены отбрасываются.
+func (b *bucket) refill(now time.Time, limit Limit) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * limit.rate()
+	}
+	b.tokens = math.Min(b.tokens, float64(limit.Requests))
+	b.updated = now
+	b.limit = limit
+}
+
+// sweep удаляет корзины, которые успели заполниться: они неотличимы от новых
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.Sub(b.updated) >= b.limit.Period {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Корзина пополняется со скоростью квоты и не переполняется
+func TestMemoryStoreRefill(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: time.Minute}
+	take := func() Result {
+		res, err := store.Take(context.Background(), "k", limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return res
+	}
+
+	take()
+	take()
+	res := take()
+	if res.Allowed {
+		t.Fatal("third request allowed")
+	}
+	if res.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %v, want 30s", res.RetryAfter)
+	}
+
+	now = now.Add(30 * time.Second)
+	if !take().Allowed {
+		t.Error("request after refill denied")
+	}
+
+	now = now.Add(time.Hour)
+	res = take()
+	if !res.Allowed || res.Remaining != 1 {
+		t.Errorf("after idle: allowed %v remaining %d, want true and 1", res.Allowed, res.Remaining)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// auditMiddleware передает в контекст запроса данные для журнала аудита:
+// идентификатор запроса (выставляется middleware.RequestID) и пользователя
+func auditMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		meta := storage.AuditMeta{
+			RequestId: ctx.Response().Header().Get(echo.HeaderXRequestID),
+			Actor:     ctx.QueryParam("username"),
+		}
+		req := ctx.Request()
+		ctx.SetRequest(req.WithContext(storage.WithAuditMeta(req.Context(), meta)))
+		return next(ctx)
+	}
+}
+
+// authorizeAuditor проверяет, что пользователь из параметра username входит в AUDIT_USERS
+func (s *Server) authorizeAuditor(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+	cfg := s.config.Load()
+	if cfg == nil || !slices.Contains(cfg.AuditUsers, username) {
+		return echo.NewHTTPError(http.StatusForbidden, "User is not an auditor")
+	}
+	return nil
+}
+
+// GetAuditLog записи журнала аудита с фильтрами (GET /audit)
+func (s *Server) GetAuditLog(ctx echo.Context) error {
+	if err := s.authorizeAuditor(ctx); err != nil {
+		return err
+	}
+
+	var params model.AuditParams
+	if err := ctx.Bind(&params); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetAuditLog bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid audit parameters")
+	}
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return echo.NewHTTPError(http.StatusBadRequest, "from must not be after to")
+	}
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.storage.GetAuditLog(ctx.Request().Context(), params, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetAuditLog error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get audit log")
+	}
+	if entries == nil {
+		entries = []model.AuditEntry{}
+	}
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// VerifyAuditLog проверяет целостность цепочки хэшей журнала аудита (GET /audit/verify)
+func (s *Server) VerifyAuditLog(ctx echo.Context) error {
+	if err := s.authorizeAuditor(ctx); err != nil {
+		return err
+	}
+
+	result, err := s.storage.VerifyAuditChain(ctx.Request().Context())
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "VerifyAuditChain error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify audit log")
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
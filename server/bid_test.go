@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bidStorage тендеры и ответственные для проверок CreateBid
+type bidStorage struct {
+	lifecycleStorage
+	tenders      map[string]model.Tender
+	responsibles map[string][]string
+	created      []model.BidsNewBody
+}
+
+func (m *bidStorage) TenderOrganizationStatus(_ context.Context, tenderId string) (string, model.TenderStatus, error) {
+	t, ok := m.tenders[tenderId]
+	if !ok {
+		return "", "", sql.ErrNoRows
+	}
+	return t.OrganizationId, t.Status, nil
+}
+
+func (m *bidStorage) IsOrganizationResponsible(_ context.Context, username, organizationId string) (bool, error) {
+	for _, org := range m.responsibles[username] {
+		if org == organizationId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *bidStorage) CreateBid(_ context.Context, body model.BidsNewBody) (model.Bid, error) {
+	m.created = append(m.created, body)
+	return model.Bid{Name: body.Name, TenderId: body.TenderId}, nil
+}
+
+// CreateBid принимает предложения только на опубликованные тендеры чужих организаций
+func TestCreateBid(t *testing.T) {
+	tests := []struct {
+		name         string
+		organization string
+		creator      string
+		tender       string
+		want         int
+	}{
+		{"organization bid", "bidder-org", "bidder", "published", http.StatusOK},
+		{"user bid", "", "freelancer", "published", http.StatusOK},
+		{"unknown tender", "bidder-org", "bidder", "missing", http.StatusNotFound},
+		{"tender not published", "bidder-org", "bidder", "created", http.StatusNotFound},
+		{"not responsible for organization", "bidder-org", "freelancer", "published", http.StatusForbidden},
+		{"own organization tender", "tender-org", "owner", "published", http.StatusForbidden},
+		{"user responsible for tender organization", "", "owner", "published", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &bidStorage{
+				tenders: map[string]model.Tender{
+					"published": {OrganizationId: "tender-org", Status: model.Published},
+					"created":   {OrganizationId: "tender-org", Status: model.Created},
+				},
+				responsibles: map[string][]string{
+					"bidder": {"bidder-org"},
+					"owner":  {"tender-org"},
+				},
+			}
+			s := newLifecycleServer(t)
+			s.storage = store
+
+			body := `{"name":"Bid","description":"Bid","tenderId":"` + tt.tender +
+				`","organizationId":"` + tt.organization + `","creatorUsername":"` + tt.creator + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/bids/new", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e := echo.New()
+			ctx := e.NewContext(req, rec)
+			if err := s.CreateBid(ctx, model.Bid{}); err != nil {
+				e.HTTPErrorHandler(err, ctx)
+			}
+
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if wantCreated := tt.want == http.StatusOK; wantCreated != (len(store.created) == 1) {
+				t.Errorf("created %d bids", len(store.created))
+			}
+		})
+	}
+}
+
+// bidListStorage запоминает пользователя, для которого запрошен список предложений
+type bidListStorage struct {
+	lifecycleStorage
+	users []string
+}
+
+func (m *bidListStorage) QueryBidsForTender(_ context.Context, _ string, _ storage.ListQuery, username string, _, _ int) ([]model.Bid, error) {
+	m.users = append(m.users, username)
+	return nil, nil
+}
+
+func (m *bidListStorage) ListBidsForTender(_ context.Context, _ string, page storage.Page) ([]model.Bid, storage.PageInfo, error) {
+	m.users = append(m.users, page.Username)
+	return nil, storage.PageInfo{}, nil
+}
+
+// Список предложений по тендеру строится для пользователя из запроса
+// и при пагинации по смещению, и по курсору
+func TestGetBidsForTenderPassesUsername(t *testing.T) {
+	for _, query := range []string{"username=user1", "username=user1&cursor="} {
+		t.Run(query, func(t *testing.T) {
+			store := &bidListStorage{}
+			s := newLifecycleServer(t)
+			s.storage = store
+
+			req := httptest.NewRequest(http.MethodGet, "/api/bids/tender-1/list?"+query, nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+			if err := s.GetBidsForTender(ctx, "tender-1", model.GetBidsForTenderParams{Username: "user1"}); err != nil {
+				t.Fatal(err)
+			}
+			if len(store.users) != 1 || store.users[0] != "user1" {
+				t.Fatalf("storage called for %v, want [user1]", store.users)
+			}
+		})
+	}
+}
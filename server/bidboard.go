@@ -0,0 +1,86 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+const bidBoardWriteTimeout = 10 * time.Second
+
+// BidBoard WebSocket-канал доски предложений тендера (GET /tenders/{tenderId}/bids/ws).
+// Доступен ответственным за организацию тендера. Сразу после подключения
+// присылает текущий рейтинг, далее — события предложений и обновленный рейтинг.
+func (s *Server) BidBoard(ctx echo.Context) error {
+	tenderId := ctx.Param("tenderId")
+
+	organizationId, err := s.storage.TenderOrganization(ctx.Request().Context(), tenderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "BidBoard error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tender")
+	}
+	if err := s.authorizeOrganization(ctx, organizationId); err != nil {
+		return err
+	}
+
+	ranking, err := s.storage.BidRanking(ctx.Request().Context(), tenderId)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "BidRanking error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bid ranking")
+	}
+	snapshot, err := json.Marshal(model.BidBoardMessage{Type: "ranking", Ranking: ranking})
+	if err != nil {
+		return err
+	}
+
+	// Права проверены до рукопожатия, поэтому проверка Origin не нужна
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		client := s.hub.Join(tenderId)
+		defer s.hub.Leave(tenderId, client)
+
+		// Входящие сообщения не ожидаются; чтение нужно, чтобы заметить закрытие соединения
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard string
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+
+		send := func(msg []byte) bool {
+			ws.SetWriteDeadline(time.Now().Add(bidBoardWriteTimeout))
+			return websocket.Message.Send(ws, string(msg)) == nil
+		}
+		if !send(snapshot) {
+			return
+		}
+		for {
+			select {
+			case msg := <-client.send:
+				if !send(msg) {
+					return
+				}
+			case <-client.dropped:
+				s.logger.InfoContext(ws.Request().Context(), "BidBoard: dropping slow client", "tender_id", tenderId)
+				return
+			case <-closed:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}}.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
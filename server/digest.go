@@ -0,0 +1,116 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"go-tenders/digest"
+	"go-tenders/model"
+	"go-tenders/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateDigestSubscription подписывает пользователя на ежедневную сводку (POST /digests/subscriptions).
+// Для канала webhook секрет подписи возвращается только в ответе на этот запрос.
+func (s *Server) CreateDigestSubscription(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+
+	var body model.DigestSubscriptionsNewBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateDigestSubscription bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	for _, st := range body.ServiceTypes {
+		if st != model.Construction && st != model.Delivery && st != model.Manufacture {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid service type "+string(st))
+		}
+	}
+	keywords := make([]string, 0, len(body.Keywords))
+	for _, kw := range body.Keywords {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			keywords = append(keywords, kw)
+		}
+	}
+	if body.Locale == "" {
+		body.Locale = digest.DefaultLocale
+	}
+	if body.Locale != "ru" && body.Locale != "en" {
+		return echo.NewHTTPError(http.StatusBadRequest, "locale must be ru or en")
+	}
+
+	sub := model.DigestSubscription{
+		Username:     username,
+		ServiceTypes: body.ServiceTypes,
+		Keywords:     keywords,
+		Channel:      body.Channel,
+		Target:       body.Target,
+		Locale:       body.Locale,
+	}
+	switch body.Channel {
+	case model.DigestChannelEmail:
+		if _, err := mail.ParseAddress(body.Target); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "target must be an email address")
+		}
+	case model.DigestChannelWebhook:
+		if err := webhook.ValidateURL(ctx.Request().Context(), body.Target); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "target: "+err.Error())
+		}
+		var err error
+		if sub.Secret, err = webhook.NewSecret(); err != nil {
+			s.logger.ErrorContext(ctx.Request().Context(), "CreateDigestSubscription secret error", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create subscription")
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "channel must be email or webhook")
+	}
+
+	created, err := s.storage.CreateDigestSubscription(ctx.Request().Context(), sub)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateDigestSubscription error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create subscription")
+	}
+	return ctx.JSON(http.StatusCreated, created)
+}
+
+// ListDigestSubscriptions подписки пользователя на сводку (GET /digests/subscriptions)
+func (s *Server) ListDigestSubscriptions(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+
+	subs, err := s.storage.ListDigestSubscriptions(ctx.Request().Context(), username)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "ListDigestSubscriptions error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list subscriptions")
+	}
+	if subs == nil {
+		subs = []model.DigestSubscription{}
+	}
+	return ctx.JSON(http.StatusOK, subs)
+}
+
+// DeleteDigestSubscription отписывает пользователя от сводки (DELETE /digests/subscriptions/{subscriptionId})
+func (s *Server) DeleteDigestSubscription(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+
+	err := s.storage.DeleteDigestSubscription(ctx.Request().Context(), ctx.Param("subscriptionId"), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Subscription not found")
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "DeleteDigestSubscription error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete subscription")
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
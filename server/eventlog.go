@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-tenders/model"
+)
+
+// EventSource источник событий для EventLog; реализуется storage.PostgresStorage
+type EventSource interface {
+	EventsAfter(ctx context.Context, afterId int64, limit int) ([]model.StreamEvent, error)
+	LatestEventId(ctx context.Context) (int64, error)
+}
+
+// defaultEventGapTimeout сколько журнал ждет событие с пропущенным id: id выдаются
+// при вставке, а видны после фиксации, поэтому транзакция с меньшим id может
+// зафиксироваться позже соседней. Пропуск дольше этого срока считается откатом.
+const defaultEventGapTimeout = 5 * time.Second
+
+// EventLog ограниченный журнал последних доменных событий в памяти.
+// Заполняется чтением outbox, поэтому каждый экземпляр сервиса видит все события.
+// Используется потоковыми эндпоинтами для рассылки и возобновления по Last-Event-ID.
+type EventLog struct {
+	mu       sync.RWMutex
+	events   []model.StreamEvent
+	capacity int
+	lastId   int64
+	subs     map[chan struct{}]struct{}
+	// gapTimeout ожидание пропущенных id при чтении outbox
+	gapTimeout time.Duration
+}
+
+// NewEventLog конструктор журнала на capacity последних событий
+func NewEventLog(capacity int) *EventLog {
+	return &EventLog{
+		capacity:   capacity,
+		subs:       make(map[chan struct{}]struct{}),
+		gapTimeout: defaultEventGapTimeout,
+	}
+}
+
+// Append добавляет события в журнал и будит подписчиков
+func (l *EventLog) Append(events ...model.StreamEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	for _, e := range events {
+		if e.Id <= l.lastId {
+			continue
+		}
+		l.events = append(l.events, e)
+		l.lastId = e.Id
+	}
+	if over := len(l.events) - l.capacity; over > 0 {
+		l.events = append(l.events[:0:0], l.events[over:]...)
+	}
+	for ch := range l.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	l.mu.Unlock()
+}
+
+// Since возвращает события с id больше afterId. complete = false, если часть
+// событий после afterId уже вытеснена из журнала и клиенту нужна полная синхронизация.
+func (l *EventLog) Since(afterId int64) (events []model.StreamEvent, complete bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.events) == 0 {
+		return nil, afterId >= l.lastId
+	}
+	complete = afterId >= l.events[0].Id-1
+	for i, e := range l.events {
+		if e.Id > afterId {
+			return append([]model.StreamEvent(nil), l.events[i:]...), complete
+		}
+	}
+	return nil, complete
+}
+
+// LastId id последнего события в журнале
+func (l *EventLog) LastId() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.lastId
+}
+
+// Subscribe возвращает канал, получающий сигнал при появлении новых событий,
+// и функцию отписки. Сигналы не накапливаются: после пробуждения читайте Since.
+func (l *EventLog) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	l.mu.Lock()
+	l.subs[ch] = struct{}{}
+	l.mu.Unlock()
+
+	return ch, func() {
+		l.mu.Lock()
+		delete(l.subs, ch)
+		l.mu.Unlock()
+	}
+}
+
+// Tail читает новые события из source до отмены ctx. При старте журнал
+// заполняется последними capacity событиями, чтобы работало возобновление.
+// События после пропуска в id не добавляются, пока пропуск не заполнится
+// или не истечет gapTimeout: иначе позже зафиксированное событие с меньшим id
+// оказалось бы позади позиции чтения и потерялось.
+func (l *EventLog) Tail(ctx context.Context, source EventSource, interval time.Duration, logger Logger) error {
+	latest, err := source.LatestEventId(ctx)
+	if err != nil {
+		return err
+	}
+	after := max(latest-int64(l.capacity), 0)
+	// settled id, до которого пропуски больше не ждем; события до старта уже зафиксированы
+	settled := latest
+	var gapSince time.Time
+
+	for {
+		events, err := source.EventsAfter(ctx, after, l.capacity)
+		if err != nil {
+			logger.Error("event log tail error: ", err)
+		}
+		n := settledPrefix(events, after, settled)
+		if n < len(events) {
+			if gapSince.IsZero() {
+				gapSince = time.Now()
+			} else if time.Since(gapSince) >= l.gapTimeout {
+				settled = events[n].Id - 1
+				gapSince = time.Time{}
+				n = settledPrefix(events, after, settled)
+			}
+		} else {
+			gapSince = time.Time{}
+		}
+		if n > 0 {
+			l.Append(events[:n]...)
+			after = events[n-1].Id
+			if n == l.capacity {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// settledPrefix возвращает число событий в начале events, перед которыми нет
+// незакрытых пропусков id: пропуск закрыт, если все недостающие id не больше settled
+func settledPrefix(events []model.StreamEvent, afterId, settled int64) int {
+	next := afterId + 1
+	for i, e := range events {
+		if e.Id != next && e.Id-1 > settled {
+			return i
+		}
+		next = e.Id + 1
+	}
+	return len(events)
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// fakeEventSource outbox в памяти; видны только зафиксированные события
+type fakeEventSource struct {
+	mu        sync.Mutex
+	committed []int64
+}
+
+func (f *fakeEventSource) commit(ids ...int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.committed = append(f.committed, ids...)
+	slices.Sort(f.committed)
+}
+
+func (f *fakeEventSource) EventsAfter(_ context.Context, afterId int64, limit int) ([]model.StreamEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var events []model.StreamEvent
+	for _, id := range f.committed {
+		if id > afterId && len(events) < limit {
+			events = append(events, streamEvent(id))
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeEventSource) LatestEventId(context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.committed) == 0 {
+		return 0, nil
+	}
+	return f.committed[len(f.committed)-1], nil
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Info(...interface{})                          {}
+func (discardLogger) Error(...interface{})                         {}
+func (discardLogger) InfoContext(context.Context, string, ...any)  {}
+func (discardLogger) ErrorContext(context.Context, string, ...any) {}
+
+func streamEvent(id int64) model.StreamEvent {
+	return model.StreamEvent{Event: model.Event{Id: id, Type: model.EventTenderPublished}}
+}
+
+func eventIds(events []model.StreamEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.Id
+	}
+	return ids
+}
+
+func TestSettledPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int64
+		after   int64
+		settled int64
+		want    int
+	}{
+		{"empty", nil, 0, 0, 0},
+		{"contiguous", []int64{4, 5, 6}, 3, 3, 3},
+		{"gap at start", []int64{5, 6}, 3, 3, 0},
+		{"gap in the middle", []int64{4, 6, 7}, 3, 3, 1},
+		{"gap before settled", []int64{6, 7}, 3, 5, 2},
+		{"gap partly settled", []int64{7}, 3, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []model.StreamEvent
+			for _, id := range tt.ids {
+				events = append(events, streamEvent(id))
+			}
+			if got := settledPrefix(events, tt.after, tt.settled); got != tt.want {
+				t.Fatalf("settledPrefix = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Событие, зафиксированное позже соседнего с большим id, не теряется
+func TestEventLogTailWaitsForLateCommit(t *testing.T) {
+	source := &fakeEventSource{}
+	source.commit(1, 2)
+	log := NewEventLog(10)
+	log.gapTimeout = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go log.Tail(ctx, source, time.Millisecond, discardLogger{})
+
+	waitForEvents(t, log, []int64{1, 2})
+	source.commit(4)
+	time.Sleep(20 * time.Millisecond)
+	if got := eventIds(mustSince(log, 2)); len(got) != 0 {
+		t.Fatalf("event after gap was appended before the gap closed: %v", got)
+	}
+	source.commit(3)
+	waitForEvents(t, log, []int64{1, 2, 3, 4})
+}
+
+// Пропуск, который так и не заполнился (откат), перестает задерживать журнал
+func TestEventLogTailSkipsRolledBackId(t *testing.T) {
+	source := &fakeEventSource{}
+	log := NewEventLog(10)
+	log.gapTimeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go log.Tail(ctx, source, time.Millisecond, discardLogger{})
+
+	source.commit(1, 3)
+	waitForEvents(t, log, []int64{1, 3})
+}
+
+func mustSince(log *EventLog, after int64) []model.StreamEvent {
+	events, _ := log.Since(after)
+	return events
+}
+
+func waitForEvents(t *testing.T, log *EventLog, want []int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := eventIds(mustSince(log, 0))
+		if slices.Equal(got, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
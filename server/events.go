@@ -0,0 +1,172 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultEventLogCapacity = 1000
+	sseHeartbeatInterval    = 15 * time.Second
+)
+
+// eventFilter фильтры подписчика и данные для проверки видимости событий
+type eventFilter struct {
+	serviceTypes   []model.TenderServiceType
+	organizationId string
+	tenderId       string
+	username       string
+	responsibleFor []string
+}
+
+// visible повторяет правила видимости списков: опубликованные тендеры видны всем,
+// закрытие тендера — ответственным за его организацию, предложения — как в
+// storage.bidVisibility: автору и ответственным за организацию-автора, а
+// ответственным за организацию тендера — после выхода из статуса Created
+func (f eventFilter) visible(e model.StreamEvent) bool {
+	switch {
+	case e.Type == model.EventTenderPublished:
+		return true
+	case e.BidAuthor == "":
+		return slices.Contains(f.responsibleFor, e.OrganizationId)
+	case e.BidAuthor == f.username:
+		return true
+	case e.BidAuthorType == model.Organization && slices.Contains(f.responsibleFor, e.BidAuthorId):
+		return true
+	}
+	return e.BidStatus != model.BidStatusCreated && slices.Contains(f.responsibleFor, e.OrganizationId)
+}
+
+func (f eventFilter) match(e model.StreamEvent) bool {
+	if !f.visible(e) {
+		return false
+	}
+	if len(f.serviceTypes) > 0 && !slices.Contains(f.serviceTypes, e.ServiceType) {
+		return false
+	}
+	if f.organizationId != "" && e.OrganizationId != f.organizationId {
+		return false
+	}
+	return f.tenderId == "" || e.TenderId() == f.tenderId
+}
+
+// parseEventFilter разбирает фильтры из строки запроса и загружает организации пользователя
+func (s *Server) parseEventFilter(ctx echo.Context) (eventFilter, error) {
+	f := eventFilter{
+		organizationId: ctx.QueryParam("organizationId"),
+		tenderId:       ctx.QueryParam("tenderId"),
+		username:       ctx.QueryParam("username"),
+	}
+	for _, raw := range ctx.QueryParams()["service_type"] {
+		for _, st := range strings.Split(raw, ",") {
+			st := model.TenderServiceType(strings.TrimSpace(st))
+			if st != model.Construction && st != model.Delivery && st != model.Manufacture {
+				return f, echo.NewHTTPError(http.StatusBadRequest, "Invalid service_type "+string(st))
+			}
+			f.serviceTypes = append(f.serviceTypes, st)
+		}
+	}
+
+	if f.username != "" {
+		orgs, err := s.storage.ResponsibleOrganizations(ctx.Request().Context(), f.username)
+		if err != nil {
+			s.logger.ErrorContext(ctx.Request().Context(), "ResponsibleOrganizations error", "error", err)
+			return f, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check permissions")
+		}
+		f.responsibleFor = orgs
+	}
+	return f, nil
+}
+
+// lastEventId возвращает позицию возобновления из заголовка Last-Event-ID
+// или параметра lastEventId (для клиентов, которые не могут задать заголовок)
+func lastEventId(ctx echo.Context) (int64, bool, error) {
+	raw := ctx.Request().Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = ctx.QueryParam("lastEventId")
+	}
+	if raw == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, echo.NewHTTPError(http.StatusBadRequest, "Invalid Last-Event-ID")
+	}
+	return id, true, nil
+}
+
+// StreamEvents поток событий тендеров и предложений в формате Server-Sent Events (GET /events/stream)
+func (s *Server) StreamEvents(ctx echo.Context) error {
+	filter, err := s.parseEventFilter(ctx)
+	if err != nil {
+		return err
+	}
+	after, resume, err := lastEventId(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Подписываемся до чтения журнала, чтобы не пропустить события между ними
+	notify, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	w := ctx.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !resume {
+		after = s.events.LastId()
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		events, complete := s.events.Since(after)
+		if !complete {
+			// Часть событий вытеснена из журнала: клиент должен перечитать списки
+			if _, err := fmt.Fprintf(w, "event: resync\ndata: {}\n\n"); err != nil {
+				return nil
+			}
+		}
+		for _, e := range events {
+			after = e.Id
+			if !filter.match(e) {
+				continue
+			}
+			data, err := json.Marshal(e.Event)
+			if err != nil {
+				s.logger.ErrorContext(ctx.Request().Context(), "StreamEvents marshal error", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Id, e.Type, data); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-s.ctx.Done():
+			return nil
+		case <-notify:
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+		}
+	}
+}
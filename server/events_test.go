@@ -0,0 +1,51 @@
+package server
+
+import (
+	"testing"
+
+	"go-tenders/model"
+)
+
+func TestEventFilterVisible(t *testing.T) {
+	bidEvent := func(status model.BidStatus, authorType model.BidAuthorType, authorId string) model.StreamEvent {
+		return model.StreamEvent{
+			Event:          model.Event{Type: model.EventBidStatusChanged},
+			OrganizationId: "tender-org",
+			BidAuthor:      "author",
+			BidAuthorType:  authorType,
+			BidAuthorId:    authorId,
+			BidStatus:      status,
+		}
+	}
+	tenderClosed := model.StreamEvent{Event: model.Event{Type: model.EventTenderClosed}, OrganizationId: "tender-org"}
+	tenderPublished := model.StreamEvent{Event: model.Event{Type: model.EventTenderPublished}, OrganizationId: "tender-org"}
+
+	anonymous := eventFilter{}
+	author := eventFilter{username: "author"}
+	colleague := eventFilter{username: "colleague", responsibleFor: []string{"bidder-org"}}
+	tenderOwner := eventFilter{username: "owner", responsibleFor: []string{"tender-org"}}
+
+	tests := []struct {
+		name   string
+		filter eventFilter
+		event  model.StreamEvent
+		want   bool
+	}{
+		{"published tender to anyone", anonymous, tenderPublished, true},
+		{"closed tender hidden from others", author, tenderClosed, false},
+		{"closed tender to owner", tenderOwner, tenderClosed, true},
+		{"bid to its author", author, bidEvent(model.BidStatusCreated, model.Organization, "bidder-org"), true},
+		{"bid to author organization", colleague, bidEvent(model.BidStatusCreated, model.Organization, "bidder-org"), true},
+		{"user bid hidden from organization", colleague, bidEvent(model.BidStatusPublished, model.User, "bidder-org"), false},
+		{"created bid hidden from tender owner", tenderOwner, bidEvent(model.BidStatusCreated, model.Organization, "bidder-org"), false},
+		{"published bid to tender owner", tenderOwner, bidEvent(model.BidStatusPublished, model.Organization, "bidder-org"), true},
+		{"bid hidden from anonymous", anonymous, bidEvent(model.BidStatusPublished, model.Organization, "bidder-org"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.visible(tt.event); got != tt.want {
+				t.Fatalf("visible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"go-tenders/export"
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushRows через сколько строк выгрузка отправляется клиенту
+const exportFlushRows = 500
+
+var (
+	tenderExportHeader = []interface{}{"id", "name", "description", "serviceType", "status", "organizationId", "version", "createdAt"}
+	bidExportHeader    = []interface{}{"id", "name", "description", "status", "tenderId", "authorType", "authorId", "version", "createdAt"}
+)
+
+func tenderExportRow(t model.Tender) []interface{} {
+	return []interface{}{t.Id, t.Name, t.Description, string(t.ServiceType), string(t.Status), t.OrganizationId, t.Version, t.CreatedAt}
+}
+
+func bidExportRow(b model.Bid) []interface{} {
+	return []interface{}{b.Id, b.Name, b.Description, string(b.Status), b.TenderId, string(b.AuthorType), b.AuthorId, b.Version, b.CreatedAt}
+}
+
+// streamExport отдает таблицу в формате из параметра format (csv по умолчанию)
+// по мере чтения строк из базы. produce вызывает write для каждой строки.
+// Пока ответ не отправлен, ошибка produce возвращается клиенту как 500; после
+// этого соединение обрывается, чтобы клиент не принял неполный файл за целый.
+func (s *Server) streamExport(ctx echo.Context, operation, filename string, header []interface{},
+	produce func(write func(...interface{}) error) error) error {
+	format, err := export.ParseFormat(ctx.QueryParam("format"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	w := export.NewWriter(format, res, filename)
+	rows := 0
+	write := func(values ...interface{}) error {
+		if err := w.WriteRow(values...); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	}
+	if err = write(header...); err == nil {
+		err = produce(write)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		return nil
+	}
+
+	s.logger.ErrorContext(ctx.Request().Context(), operation+" export error", "error", err)
+	if !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export data")
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// ExportTenders выгрузка списка тендеров (GET /tenders/export) с фильтрами и
+// сортировкой GET /tenders. Неопубликованные тендеры видны только
+// ответственным за организацию пользователям из параметра username.
+func (s *Server) ExportTenders(ctx echo.Context) error {
+	q, err := listQuery(ctx, storage.TenderListSchema)
+	if err != nil {
+		return err
+	}
+	username := ctx.QueryParam("username")
+	return s.streamExport(ctx, "GetTenders", "tenders", tenderExportHeader, func(write func(...interface{}) error) error {
+		return s.storage.ExportTenders(ctx.Request().Context(), q, username, func(t model.Tender) error {
+			return write(tenderExportRow(t)...)
+		})
+	})
+}
+
+// ExportUserTenders выгрузка тендеров пользователя (GET /tenders/my/export)
+func (s *Server) ExportUserTenders(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+	return s.streamExport(ctx, "GetUserTenders", "my-tenders", tenderExportHeader, func(write func(...interface{}) error) error {
+		return s.storage.ExportUserTenders(ctx.Request().Context(), username, func(t model.Tender) error {
+			return write(tenderExportRow(t)...)
+		})
+	})
+}
+
+// ExportBidsForTender выгрузка предложений по тендеру (GET /bids/{tenderId}/list/export)
+// с теми же строками, что GET /bids/{tenderId}/list без пагинации: пользователь
+// видит свои предложения и предложения своей организации, ответственный за
+// организацию тендера — все опубликованные. По неизвестному тендеру, как и
+// список, отдает пустую таблицу.
+func (s *Server) ExportBidsForTender(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+	q, err := listQuery(ctx, storage.BidListSchema)
+	if err != nil {
+		return err
+	}
+	tenderId := ctx.Param("tenderId")
+	return s.streamExport(ctx, "GetBidsForTender", "bids-"+tenderId, bidExportHeader, func(write func(...interface{}) error) error {
+		return s.storage.ExportBidsForTender(ctx.Request().Context(), tenderId, q, username, func(b model.Bid) error {
+			return write(bidExportRow(b)...)
+		})
+	})
+}
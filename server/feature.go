@@ -0,0 +1,18 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// feature скрывает маршрут, если флаг функциональности name выключен; флаг
+// читается из действующей конфигурации, поэтому применяется без перезапуска
+func (s *Server) feature(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if cfg := s.config.Load(); cfg != nil && !cfg.Feature(name) {
+				return echo.ErrNotFound
+			}
+			return next(ctx)
+		}
+	}
+}
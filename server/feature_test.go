@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-tenders/config"
+)
+
+// Маршруты выключенного флага отвечают 404; флаг применяется после SetConfig
+func TestFeatureFlagHidesRoutes(t *testing.T) {
+	s := newLifecycleServer(t)
+	h := s.Handler()
+	status := func() int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tenders/my/export", nil))
+		return rec.Code
+	}
+
+	s.SetConfig(&config.Config{})
+	if code := status(); code != http.StatusUnauthorized {
+		t.Fatalf("enabled by default: status %d, want %d from the handler", code, http.StatusUnauthorized)
+	}
+	s.SetConfig(&config.Config{FeatureFlags: map[string]bool{config.FeatureExport: false}})
+	if code := status(); code != http.StatusNotFound {
+		t.Fatalf("disabled: status %d, want %d", code, http.StatusNotFound)
+	}
+	s.SetConfig(&config.Config{FeatureFlags: map[string]bool{config.FeatureExport: true, config.FeatureSearch: false}})
+	if code := status(); code != http.StatusUnauthorized {
+		t.Fatalf("re-enabled: status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// healthCheckFunc проверка зависимости; details попадают в ответ и при ошибке
+type healthCheckFunc func(ctx context.Context) (details map[string]interface{}, err error)
+
+// readinessChecks зависимости, без которых экземпляр не должен принимать трафик
+func (s *Server) readinessChecks() map[string]healthCheckFunc {
+	return map[string]healthCheckFunc{
+		"postgres": func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, s.storage.Ping(ctx)
+		},
+		"migrations": func(ctx context.Context) (map[string]interface{}, error) {
+			expected, err := storage.LatestMigrationVersion()
+			if err != nil {
+				return nil, err
+			}
+			current, tracked, err := s.storage.MigrationVersion(ctx)
+			if err != nil {
+				return nil, err
+			}
+			details := map[string]interface{}{"version": current, "expected": expected, "tracked": tracked}
+			// Без schema_migrations схема создана вне сервиса (migrate не запускался)
+			if !tracked {
+				return details, nil
+			}
+			// Схема новее кода допустима во время выкладки; старее — нет
+			if current < expected {
+				return details, fmt.Errorf("schema version %d is behind expected %d", current, expected)
+			}
+			return details, nil
+		},
+	}
+}
+
+// Live проверка живости процесса (GET /health/live); зависимости не проверяет,
+// чтобы перезапуск пода не вызывался недоступностью базы
+func (s *Server) Live(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, model.HealthReport{Status: model.HealthOk})
+}
+
+// Ready проверка готовности принимать трафик (GET /health/ready). Зависимости
+// проверяются параллельно; при недоступности любой из них, а также во время
+// остановки сервера возвращается 503.
+func (s *Server) Ready(ctx echo.Context) error {
+	if s.draining.Load() {
+		return ctx.JSON(http.StatusServiceUnavailable, model.HealthReport{Status: model.HealthUnavailable})
+	}
+
+	checks := s.readinessChecks()
+	report := model.HealthReport{Status: model.HealthOk, Checks: make(map[string]model.HealthCheck, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx.Request().Context(), healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			details, err := check(checkCtx)
+			result := model.HealthCheck{
+				Status:    model.HealthOk,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Details:   details,
+			}
+			if err != nil {
+				result.Status = model.HealthUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = model.HealthUnavailable
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if report.Status != model.HealthOk {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"go-tenders/model"
+)
+
+const defaultHubClientBuffer = 64
+
+// hubClient подписчик комнаты хаба
+type hubClient struct {
+	send chan []byte
+	// dropped закрывается, когда хаб отключает отстающего клиента
+	dropped chan struct{}
+	once    sync.Once
+}
+
+func (c *hubClient) drop() {
+	c.once.Do(func() { close(c.dropped) })
+}
+
+// Hub рассылает сообщения подписчикам комнат (комната — тендер).
+// Рассылка не блокируется: у каждого клиента ограниченный буфер, и клиент,
+// не успевающий его разбирать, отключается. После переподключения он получает
+// свежий снимок рейтинга, поэтому потерянные сообщения не приводят к расхождению.
+type Hub struct {
+	mu         sync.RWMutex
+	rooms      map[string]map[*hubClient]struct{}
+	bufferSize int
+}
+
+// NewHub конструктор хаба с буфером bufferSize сообщений на клиента
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		rooms:      make(map[string]map[*hubClient]struct{}),
+		bufferSize: bufferSize,
+	}
+}
+
+// Join добавляет клиента в комнату
+func (h *Hub) Join(room string) *hubClient {
+	c := &hubClient{
+		send:    make(chan []byte, h.bufferSize),
+		dropped: make(chan struct{}),
+	}
+	h.mu.Lock()
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*hubClient]struct{})
+	}
+	h.rooms[room][c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+// Leave удаляет клиента из комнаты
+func (h *Hub) Leave(room string, c *hubClient) {
+	h.mu.Lock()
+	delete(h.rooms[room], c)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+	h.mu.Unlock()
+	c.drop()
+}
+
+// HasClients сообщает, есть ли в комнате подписчики
+func (h *Hub) HasClients(room string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room]) > 0
+}
+
+// Broadcast отправляет сообщение всем клиентам комнаты, отключая тех, чей буфер заполнен
+func (h *Hub) Broadcast(room string, msg []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.rooms[room] {
+		select {
+		case c.send <- msg:
+		default:
+			c.drop()
+		}
+	}
+}
+
+// BroadcastJSON кодирует v в JSON и рассылает комнате
+func (h *Hub) BroadcastJSON(room string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.Broadcast(room, data)
+	return nil
+}
+
+// RankingFunc возвращает текущий рейтинг предложений тендера
+type RankingFunc func(ctx context.Context, tenderId string) ([]model.BidRank, error)
+
+// Run пересылает события предложений из журнала в комнаты их тендеров и после
+// каждой пачки событий рассылает обновленный рейтинг. Рейтинг считается один раз
+// на тендер за пачку, а не на каждого клиента. В комнате только ответственные за
+// организацию тендера (BidBoard), поэтому события проверяются правилом
+// eventFilter.visible для этой организации: черновики предложений не рассылаются.
+func (h *Hub) Run(ctx context.Context, log *EventLog, ranking RankingFunc, logger Logger) {
+	notify, unsubscribe := log.Subscribe()
+	defer unsubscribe()
+	after := log.LastId()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-notify:
+		}
+
+		events, _ := log.Since(after)
+		changed := make(map[string]struct{})
+		for _, e := range events {
+			after = e.Id
+			if !strings.HasPrefix(string(e.Type), "bid.") {
+				continue
+			}
+			tenderId := e.TenderId()
+			if !h.HasClients(tenderId) {
+				continue
+			}
+			if !(eventFilter{responsibleFor: []string{e.OrganizationId}}).visible(e) {
+				continue
+			}
+			event := e.Event
+			if err := h.BroadcastJSON(tenderId, model.BidBoardMessage{Type: string(e.Type), Event: &event}); err != nil {
+				logger.Error("hub broadcast error: ", err)
+			}
+			changed[tenderId] = struct{}{}
+		}
+
+		for tenderId := range changed {
+			r, err := ranking(ctx, tenderId)
+			if err != nil {
+				logger.Error("hub ranking error: ", err)
+				continue
+			}
+			if err := h.BroadcastJSON(tenderId, model.BidBoardMessage{Type: "ranking", Ranking: r}); err != nil {
+				logger.Error("hub broadcast error: ", err)
+			}
+		}
+	}
+}
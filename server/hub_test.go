@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+
+	"golang.org/x/net/websocket"
+)
+
+// bidBoardEvent событие предложения на тендер tender-1 организации tender-org
+func bidBoardEvent(id int64, status model.BidStatus) model.StreamEvent {
+	return model.StreamEvent{
+		Event: model.Event{
+			Id:          id,
+			Type:        model.EventBidStatusChanged,
+			AggregateId: "bid",
+			Payload:     json.RawMessage(`{"tenderId":"tender-1"}`),
+		},
+		OrganizationId: "tender-org",
+		BidAuthor:      "bidder",
+		BidAuthorType:  model.Organization,
+		BidAuthorId:    "bidder-org",
+		BidStatus:      status,
+	}
+}
+
+// waitSubscribed ждет, пока журнал получит n подписчиков
+func waitSubscribed(t *testing.T, log *EventLog, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		log.mu.RLock()
+		subs := len(log.subs)
+		log.mu.RUnlock()
+		if subs >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("event log has %d subscribers, want %d", subs, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch <-chan []byte) model.BidBoardMessage {
+	t.Helper()
+	select {
+	case data := <-ch:
+		var msg model.BidBoardMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message")
+	}
+	return model.BidBoardMessage{}
+}
+
+// Клиент с заполненным буфером отключается, остальные продолжают получать сообщения
+func TestHubDropsSlowClient(t *testing.T) {
+	h := NewHub(2)
+	slow := h.Join("tender-1")
+	fast := h.Join("tender-1")
+
+	for i := range 3 {
+		h.Broadcast("tender-1", []byte{byte(i)})
+		<-fast.send
+	}
+
+	select {
+	case <-slow.dropped:
+	default:
+		t.Fatal("slow client is not dropped")
+	}
+	select {
+	case <-fast.dropped:
+		t.Fatal("fast client is dropped")
+	default:
+	}
+	if len(slow.send) != 2 {
+		t.Fatalf("slow client buffer has %d messages, want 2", len(slow.send))
+	}
+
+	h.Leave("tender-1", slow)
+	h.Leave("tender-1", fast)
+	if h.HasClients("tender-1") {
+		t.Fatal("room is not removed after the last client left")
+	}
+}
+
+// В комнату доски попадают только события, видимые ответственным за организацию
+// тендера: черновик чужой организации не рассылается и не пересчитывает рейтинг
+func TestHubRunSkipsDraftBids(t *testing.T) {
+	log := NewEventLog(10)
+	h := NewHub(10)
+	client := h.Join("tender-1")
+
+	var rankings atomic.Int32
+	ranking := func(_ context.Context, tenderId string) ([]model.BidRank, error) {
+		rankings.Add(1)
+		return []model.BidRank{{Rank: 1, BidId: "bid"}}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx, log, ranking, discardLogger{})
+	waitSubscribed(t, log, 1)
+
+	log.Append(bidBoardEvent(1, model.BidStatusCreated))
+	log.Append(bidBoardEvent(2, model.BidStatusPublished))
+
+	if msg := receive(t, client.send); msg.Type != string(model.EventBidStatusChanged) || msg.Event.Id != 2 {
+		t.Fatalf("first message = %+v, want published bid event 2", msg)
+	}
+	if msg := receive(t, client.send); msg.Type != "ranking" || len(msg.Ranking) != 1 {
+		t.Fatalf("second message = %+v, want ranking", msg)
+	}
+	select {
+	case data := <-client.send:
+		t.Fatalf("unexpected message %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := rankings.Load(); n != 1 {
+		t.Fatalf("ranking computed %d times, want 1", n)
+	}
+}
+
+// bidBoardStorage тендер tender-1 организации tender-org с ответственным owner
+type bidBoardStorage struct {
+	lifecycleStorage
+}
+
+func (bidBoardStorage) TenderOrganization(_ context.Context, tenderId string) (string, error) {
+	return "tender-org", nil
+}
+
+func (bidBoardStorage) IsOrganizationResponsible(_ context.Context, username, organizationId string) (bool, error) {
+	return username == "owner" && organizationId == "tender-org", nil
+}
+
+func (bidBoardStorage) BidRanking(context.Context, string) ([]model.BidRank, error) {
+	return []model.BidRank{{Rank: 1, BidId: "bid", Status: model.BidStatusPublished}}, nil
+}
+
+// Доска присылает снимок рейтинга, затем видимые события и новый рейтинг;
+// подключиться может только ответственный за организацию тендера
+func TestBidBoard(t *testing.T) {
+	s := newLifecycleServer(t)
+	s.storage = bidBoardStorage{}
+	srv := httptest.NewServer(s.Handler())
+	s.startBackground()
+	t.Cleanup(func() {
+		srv.Close()
+		s.Shutdown(context.Background())
+	})
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/tenders/tender-1/bids/ws?username="
+
+	if _, err := websocket.Dial(wsURL+"stranger", "", srv.URL); err == nil {
+		t.Fatal("stranger connected to the bid board")
+	} else if !strings.Contains(err.Error(), "bad status") {
+		t.Fatalf("Dial error = %v, want bad status", err)
+	}
+
+	ws, err := websocket.Dial(wsURL+"owner", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	messages := make(chan []byte, 10)
+	go func() {
+		var data []byte
+		for websocket.Message.Receive(ws, &data) == nil {
+			messages <- data
+		}
+		close(messages)
+	}()
+
+	if msg := receive(t, messages); msg.Type != "ranking" || len(msg.Ranking) != 1 {
+		t.Fatalf("snapshot = %+v", msg)
+	}
+	waitSubscribed(t, s.events, 1)
+	s.events.Append(bidBoardEvent(1, model.BidStatusCreated), bidBoardEvent(2, model.BidStatusPublished))
+
+	if msg := receive(t, messages); msg.Event == nil || msg.Event.Id != 2 {
+		t.Fatalf("message = %+v, want published bid event 2", msg)
+	}
+	if msg := receive(t, messages); msg.Type != "ranking" {
+		t.Fatalf("message = %+v, want ranking", msg)
+	}
+}
+
+// Отстающий клиент доски отключается, а не тормозит рассылку остальным
+func TestBidBoardClosesDroppedClient(t *testing.T) {
+	s := newLifecycleServer(t)
+	s.storage = bidBoardStorage{}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		srv.Close()
+		s.Shutdown(context.Background())
+	})
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/tenders/tender-1/bids/ws?username=owner", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	var snapshot []byte
+	if err := websocket.Message.Receive(ws, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	s.hub.mu.RLock()
+	for c := range s.hub.rooms["tender-1"] {
+		c.drop()
+	}
+	s.hub.mu.RUnlock()
+
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var data []byte
+	if err := websocket.Message.Receive(ws, &data); err == nil {
+		t.Fatalf("dropped client received %s", data)
+	} else if ne, ok := err.(interface{ Timeout() bool }); ok && ne.Timeout() {
+		t.Fatal("connection of dropped client is not closed")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for s.hub.HasClients("tender-1") {
+		if time.Now().After(deadline) {
+			t.Fatal("dropped client is still in the room")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// idempotencyLock сколько ключ считается занятым выполняющимся запросом;
+	// после этого ключ брошенного запроса (например, при падении экземпляра) можно занять снова
+	idempotencyLock          = time.Minute
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+)
+
+// idempotentOperations операции, поддерживающие заголовок Idempotency-Key
+var idempotentOperations = map[string]bool{
+	"CreateTender": true,
+	"CreateBid":    true,
+}
+
+// idempotencyMiddleware повторяет сохраненный ответ на запрос с тем же
+// Idempotency-Key и тем же телом. Ключ действует в пределах операции, автора
+// и организации из тела запроса; тот же ключ с другим телом отклоняется с 422.
+func (s *Server) idempotencyMiddleware(operation func(echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			key := ctx.Request().Header.Get(headerIdempotencyKey)
+			op := operation(ctx)
+			if key == "" || !idempotentOperations[op] {
+				return next(ctx)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key is too long")
+			}
+
+			body, err := requestBody(ctx)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Request body is too large")
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+			}
+
+			scope := idempotencyScope(ctx, op)
+			reqCtx := ctx.Request().Context()
+			rec, acquired, err := s.storage.BeginIdempotentRequest(reqCtx, scope, key, requestHash(body), idempotencyLock, s.idempotencyKeyTTL())
+			if err != nil {
+				s.logger.ErrorContext(reqCtx, "BeginIdempotentRequest error", "error", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process Idempotency-Key")
+			}
+
+			if !acquired {
+				switch {
+				case rec.RequestHash != requestHash(body):
+					return ctx.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{
+						Reason: "Idempotency-Key was already used with a different request",
+					})
+				case !rec.Completed():
+					return ctx.JSON(http.StatusConflict, model.ErrorResponse{
+						Reason: "Request with this Idempotency-Key is still in progress",
+					})
+				}
+				ctx.Response().Header().Set(headerIdempotentReplayed, "true")
+				return ctx.Blob(rec.StatusCode, rec.ContentType, rec.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+			ctx.Response().Writer = recorder
+			err = next(ctx)
+			ctx.Response().Writer = recorder.ResponseWriter
+
+			// Ошибку и ответ 5xx не сохраняем: клиент может повторить запрос с тем же ключом
+			status := ctx.Response().Status
+			if err != nil || !ctx.Response().Committed || status >= http.StatusInternalServerError {
+				if releaseErr := s.storage.ReleaseIdempotentRequest(reqCtx, scope, key); releaseErr != nil {
+					s.logger.ErrorContext(reqCtx, "ReleaseIdempotentRequest error", "error", releaseErr)
+				}
+				return err
+			}
+
+			contentType := ctx.Response().Header().Get(echo.HeaderContentType)
+			if err := s.storage.CompleteIdempotentRequest(reqCtx, scope, key, status, contentType, recorder.body.Bytes()); err != nil {
+				s.logger.ErrorContext(reqCtx, "CompleteIdempotentRequest error", "error", err)
+			}
+			return nil
+		}
+	}
+}
+
+// idempotencyScope область действия ключа: операция, пользователь (владелец
+// токена или автор из тела запроса) и организация из тела запроса
+func idempotencyScope(ctx echo.Context, op string) string {
+	return op + ":" + requestUser(ctx, op) + ":" + requestCreator(ctx, op).OrganizationId
+}
+
+func (s *Server) idempotencyKeyTTL() time.Duration {
+	if cfg := s.config.Load(); cfg != nil && cfg.IdempotencyKeyTTL > 0 {
+		return cfg.IdempotencyKeyTTL
+	}
+	return defaultIdempotencyKeyTTL
+}
+
+// requestHash хэш тела запроса. JSON приводится к каноническому виду, чтобы
+// повтор с другим порядком полей или пробелами считался тем же запросом.
+func requestHash(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if canonical, err := json.Marshal(v); err == nil {
+			body = canonical
+		}
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder копирует тело ответа, чтобы сохранить его для повторов
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
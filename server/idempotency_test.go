@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// idempotencyStorage ключи идемпотентности в памяти
+type idempotencyStorage struct {
+	lifecycleStorage
+	mu      sync.Mutex
+	records map[string]model.IdempotencyRecord
+}
+
+func newIdempotencyStorage() *idempotencyStorage {
+	return &idempotencyStorage{records: make(map[string]model.IdempotencyRecord)}
+}
+
+func (m *idempotencyStorage) BeginIdempotentRequest(_ context.Context, scope, key, requestHash string, _, _ time.Duration) (model.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rec, ok := m.records[scope+"\n"+key]; ok {
+		return rec, false, nil
+	}
+	rec := model.IdempotencyRecord{Scope: scope, Key: key, RequestHash: requestHash}
+	m.records[scope+"\n"+key] = rec
+	return rec, true, nil
+}
+
+func (m *idempotencyStorage) CompleteIdempotentRequest(_ context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec := m.records[scope+"\n"+key]
+	rec.StatusCode, rec.ContentType, rec.Body = statusCode, contentType, body
+	m.records[scope+"\n"+key] = rec
+	return nil
+}
+
+func (m *idempotencyStorage) ReleaseIdempotentRequest(_ context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, scope+"\n"+key)
+	return nil
+}
+
+// idempotencyTest middleware над обработчиком, который считает вызовы
+type idempotencyTest struct {
+	storage *idempotencyStorage
+	handler echo.HandlerFunc
+	calls   int
+	status  int
+}
+
+func newIdempotencyTest(t *testing.T) *idempotencyTest {
+	t.Helper()
+	s := newLifecycleServer(t)
+	it := &idempotencyTest{storage: newIdempotencyStorage(), status: http.StatusOK}
+	s.storage = it.storage
+	mw := s.idempotencyMiddleware(func(echo.Context) string { return "CreateTender" })
+	it.handler = mw(func(ctx echo.Context) error {
+		it.calls++
+		var body model.TendersNewBody
+		if err := ctx.Bind(&body); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		}
+		return ctx.JSON(it.status, model.Tender{Name: body.Name, Version: int32(it.calls)})
+	})
+	return it
+}
+
+func (it *idempotencyTest) do(t *testing.T, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/new", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(headerIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e := echo.New()
+	if err := it.handler(e.NewContext(req, rec)); err != nil {
+		e.HTTPErrorHandler(err, e.NewContext(req, rec))
+	}
+	return rec
+}
+
+const tenderBody = `{"name":"Tender","creatorUsername":"alice","organizationId":"1"}`
+
+// Повтор с тем же ключом и телом получает сохраненный ответ без вызова обработчика
+func TestIdempotencyReplay(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	first := it.do(t, "key-1", tenderBody)
+	// Тот же JSON с другим порядком полей и пробелами — тот же запрос
+	second := it.do(t, "key-1", `{"organizationId": "1", "creatorUsername": "alice", "name": "Tender"}`)
+	if it.calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", it.calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %q, want %d %q", second.Code, second.Body, first.Code, first.Body)
+	}
+	if got := second.Header().Get(headerIdempotentReplayed); got != "true" {
+		t.Errorf("%s = %q, want true", headerIdempotentReplayed, got)
+	}
+	if got := first.Header().Get(headerIdempotentReplayed); got != "" {
+		t.Errorf("first response %s = %q", headerIdempotentReplayed, got)
+	}
+}
+
+// Тот же ключ с другим телом отклоняется
+func TestIdempotencyConflict(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.do(t, "key-1", tenderBody)
+
+	rec := it.do(t, "key-1", `{"name":"Other","creatorUsername":"alice","organizationId":"1"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status %d, want 422", rec.Code)
+	}
+	if it.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", it.calls)
+	}
+}
+
+// Повтор, пока первый запрос выполняется, получает 409
+func TestIdempotencyInFlight(t *testing.T) {
+	it := newIdempotencyTest(t)
+	scope := "CreateTender:alice:1"
+	it.storage.records[scope+"\nkey-1"] = model.IdempotencyRecord{
+		Scope: scope, Key: "key-1", RequestHash: requestHash([]byte(tenderBody)),
+	}
+
+	if rec := it.do(t, "key-1", tenderBody); rec.Code != http.StatusConflict {
+		t.Errorf("status %d, want 409", rec.Code)
+	}
+	if it.calls != 0 {
+		t.Errorf("handler calls = %d, want 0", it.calls)
+	}
+}
+
+// Ключ действует в пределах автора и организации из тела, а не параметра username
+func TestIdempotencyScope(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.do(t, "key-1", tenderBody)
+	it.do(t, "key-1", `{"name":"Tender","creatorUsername":"bob","organizationId":"1"}`)
+	it.do(t, "key-1", `{"name":"Tender","creatorUsername":"alice","organizationId":"2"}`)
+	if it.calls != 3 {
+		t.Errorf("handler calls = %d, want 3", it.calls)
+	}
+}
+
+// Ответ 5xx не сохраняется: повтор с тем же ключом выполняется заново
+func TestIdempotencyServerErrorNotStored(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.status = http.StatusInternalServerError
+	it.do(t, "key-1", tenderBody)
+	it.status = http.StatusOK
+	if rec := it.do(t, "key-1", tenderBody); rec.Code != http.StatusOK {
+		t.Errorf("retry: status %d, want 200", rec.Code)
+	}
+	if it.calls != 2 {
+		t.Errorf("handler calls = %d, want 2", it.calls)
+	}
+}
+
+// Тело больше предела отклоняется до обращения к хранилищу
+func TestIdempotencyBodyTooLarge(t *testing.T) {
+	it := newIdempotencyTest(t)
+	rec := it.do(t, "key-1", `{"name":"`+strings.Repeat("x", maxRequestBodySize)+`"}`)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %d, want 413", rec.Code)
+	}
+	if it.calls != 0 {
+		t.Errorf("handler calls = %d, want 0", it.calls)
+	}
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// maxImportBytes ограничение размера файла импорта
+	maxImportBytes = 16 << 20
+	// maxImportRows ограничение числа строк в одном импорте
+	maxImportRows = 10000
+	// defaultImportBatchSize размер пакета, если batchSize не задан
+	defaultImportBatchSize = 100
+	// maxImportBatchSize наибольший пакет, в том числе импорт в одной транзакции
+	// (batchSize=0): длинная транзакция держит блокировку цепочки аудита
+	maxImportBatchSize = 500
+)
+
+// errImportStatus статус импортируемых тендеров назначает сервер
+var errImportStatus = errors.New("status is not allowed: imported tenders are created with status Created")
+
+// importRecord строка файла импорта; err — ошибка разбора строки
+type importRecord struct {
+	row  int
+	body model.TendersNewBody
+	err  error
+}
+
+// importFormat определяет формат файла по параметру format или Content-Type
+func importFormat(ctx echo.Context, format *string) (string, error) {
+	if format != nil {
+		switch f := strings.ToLower(*format); f {
+		case "csv", "jsonl":
+			return f, nil
+		}
+		return "", fmt.Errorf("unsupported import format %q: use csv or jsonl", *format)
+	}
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case "text/csv":
+		return "csv", nil
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return "jsonl", nil
+	}
+	return "", errors.New("set format=csv or format=jsonl, or send Content-Type text/csv or application/x-ndjson")
+}
+
+// csvImportColumns колонки CSV — имена полей TendersNewBody в JSON, в любом регистре
+var csvImportColumns = map[string]func(*model.TendersNewBody, string){
+	"name":            func(b *model.TendersNewBody, v string) { b.Name = v },
+	"description":     func(b *model.TendersNewBody, v string) { b.Description = v },
+	"servicetype":     func(b *model.TendersNewBody, v string) { b.ServiceType = model.TenderServiceType(v) },
+	"organizationid":  func(b *model.TendersNewBody, v string) { b.OrganizationId = v },
+	"creatorusername": func(b *model.TendersNewBody, v string) { b.CreatorUsername = v },
+}
+
+// parseCSVImport читает CSV с заголовком. Ошибка в строке попадает в ее
+// importRecord; ошибка заголовка (в том числе колонка status) или синтаксиса
+// CSV прерывает разбор.
+func parseCSVImport(r io.Reader) ([]importRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV header is missing")
+	}
+	if err != nil {
+		return nil, err
+	}
+	setters := make([]func(*model.TendersNewBody, string), len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "status" {
+			return nil, fmt.Errorf("CSV column %q: %w", name, errImportStatus)
+		}
+		set, ok := csvImportColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		seen[key] = true
+		setters[i] = set
+	}
+
+	var records []importRecord
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec := importRecord{row: len(records) + 1}
+		if len(fields) != len(header) {
+			rec.err = fmt.Errorf("expected %d fields, got %d", len(header), len(fields))
+		} else {
+			for i, v := range fields {
+				setters[i](&rec.body, v)
+			}
+		}
+		if records = append(records, rec); len(records) > maxImportRows {
+			return nil, errImportTooLarge
+		}
+	}
+}
+
+// parseJSONLImport читает по объекту TendersNewBody на строку; пустые строки
+// пропускаются. Строка с полем status получает ошибку errImportStatus.
+func parseJSONLImport(r io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	var records []importRecord
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		rec := importRecord{row: line}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec.body); err != nil {
+			rec.err = fmt.Errorf("invalid JSON: %w", err)
+		} else if rec.body.Status != "" {
+			rec.err = errImportStatus
+		}
+		if records = append(records, rec); len(records) > maxImportRows {
+			return nil, errImportTooLarge
+		}
+	}
+	return records, scanner.Err()
+}
+
+var errImportTooLarge = fmt.Errorf("import is limited to %d rows", maxImportRows)
+
+// ImportTenders массово создает тендеры из CSV или JSONL (POST /tenders/import).
+// Каждая строка проверяется так же, как тело POST /tenders/new. Строки
+// сохраняются пакетами по batchSize (по умолчанию 100), каждый в своей
+// транзакции; ошибка строки не отменяет остальные строки пакета. batchSize=0
+// сохраняет все строки в одной транзакции: ошибка в любой строке отменяет
+// импорт целиком; такой импорт ограничен maxImportBatchSize строками. dryRun выполняет проверки и вставку, но
+// откатывает транзакции.
+// Ответ — отчет с результатом по каждой строке.
+func (s *Server) ImportTenders(ctx echo.Context) error {
+	var params struct {
+		Format    *string `query:"format"`
+		DryRun    *bool   `query:"dryRun"`
+		BatchSize *int32  `query:"batchSize"`
+	}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+	format, err := importFormat(ctx, params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	report := model.TenderImportReport{
+		DryRun:    params.DryRun != nil && *params.DryRun,
+		BatchSize: defaultImportBatchSize,
+	}
+	if params.BatchSize != nil {
+		report.BatchSize = int(*params.BatchSize)
+	}
+	if report.BatchSize < 0 || report.BatchSize > maxImportBatchSize {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("batchSize must be between 0 and %d", maxImportBatchSize))
+	}
+
+	body := http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxImportBytes)
+	var records []importRecord
+	if format == "csv" {
+		records, err = parseCSVImport(body)
+	} else {
+		records, err = parseJSONLImport(body)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || errors.Is(err, errImportTooLarge) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("import is limited to %d rows and %d MiB", maxImportRows, maxImportBytes>>20))
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid import file: "+err.Error())
+	}
+
+	if report.BatchSize == 0 && len(records) > maxImportBatchSize {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("import in one transaction (batchSize=0) is limited to %d rows, use batchSize", maxImportBatchSize))
+	}
+
+	report.Total = len(records)
+	report.Rows = make([]model.TenderImportRow, len(records))
+	responsible := make(map[[2]string]bool)
+	var valid []int
+	for i, rec := range records {
+		report.Rows[i].Row = rec.row
+		err := rec.err
+		if err == nil {
+			err = validateTendersNewBody(rec.body)
+		}
+		if err == nil {
+			key := [2]string{rec.body.CreatorUsername, rec.body.OrganizationId}
+			ok, checked := responsible[key]
+			if !checked {
+				ok, err = s.storage.IsOrganizationResponsible(ctx.Request().Context(), key[0], key[1])
+				if err != nil {
+					s.logger.ErrorContext(ctx.Request().Context(), "IsOrganizationResponsible error", "error", err)
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check permissions")
+				}
+				responsible[key] = ok
+			}
+			if !ok {
+				err = errors.New("user is not responsible for the organization")
+			}
+		}
+		if err != nil {
+			report.Rows[i].Status = model.TenderImportRowFailed
+			report.Rows[i].Error = strings.ReplaceAll(err.Error(), "\n", "; ")
+			report.Failed++
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	// С batchSize=0 одна ошибка отменяет весь импорт, но строки все равно
+	// проходят вставку, чтобы отчет показал и ошибки базы
+	batchSize := report.BatchSize
+	allOrNothing := batchSize == 0
+	if allOrNothing {
+		batchSize = max(len(valid), 1)
+	}
+	rollback := report.DryRun || (allOrNothing && report.Failed > 0)
+	for start := 0; start < len(valid); start += batchSize {
+		batch := valid[start:min(start+batchSize, len(valid))]
+		bodies := make([]model.TendersNewBody, len(batch))
+		for j, i := range batch {
+			bodies[j] = records[i].body
+		}
+		results, committed, err := s.storage.ImportTenders(ctx.Request().Context(), bodies, allOrNothing, rollback)
+		for j, i := range batch {
+			row := &report.Rows[i]
+			switch {
+			case err != nil || results[j].Err != nil:
+				if err == nil {
+					s.logger.ErrorContext(ctx.Request().Context(), "ImportTenders row error", "row", row.Row, "error", results[j].Err)
+				}
+				row.Status, row.Error = model.TenderImportRowFailed, "Failed to save tender"
+				report.Failed++
+			case committed:
+				row.Status, row.TenderId = model.TenderImportRowCreated, results[j].Tender.Id
+				report.Created++
+			case report.DryRun:
+				row.Status = model.TenderImportRowValid
+			default:
+				row.Status = model.TenderImportRowSkipped
+			}
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx.Request().Context(), "ImportTenders error", "error", err)
+		}
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Колонки CSV в любом регистре и порядке; ошибки строк не прерывают разбор
+func TestParseCSVImport(t *testing.T) {
+	input := "\ufeffName,description,SERVICETYPE,organizationId,creatorUsername\n" +
+		"Доставка,Описание,Delivery,org-1,alice\n" +
+		"Лишнее поле,Описание,Delivery,org-1,alice,extra\n" +
+		"\"Стройка, этап 2\",Описание,Construction,org-2,bob\n"
+
+	records, err := parseCSVImport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseCSVImport: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %d, want 3", len(records))
+	}
+	want := model.TendersNewBody{
+		Name: "Доставка", Description: "Описание", ServiceType: model.Delivery,
+		OrganizationId: "org-1", CreatorUsername: "alice",
+	}
+	if records[0].err != nil || records[0].body != want || records[0].row != 1 {
+		t.Errorf("row 1 = %+v", records[0])
+	}
+	if records[1].err == nil {
+		t.Error("row with extra field has no error")
+	}
+	if records[2].err != nil || records[2].body.Name != "Стройка, этап 2" || records[2].row != 3 {
+		t.Errorf("row 3 = %+v", records[2])
+	}
+}
+
+// Ошибки заголовка прерывают разбор всего файла
+func TestParseCSVImportHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty file", "", "header is missing"},
+		{"unknown column", "name,price\n", `unknown CSV column "price"`},
+		{"duplicate column", "name,Name\n", `duplicate CSV column "Name"`},
+		{"status column", "name,status\nT,Published\n", "status is not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCSVImport(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// JSONL: пустые строки пропускаются, номер строки — номер в файле
+func TestParseJSONLImport(t *testing.T) {
+	input := `{"name":"Доставка","description":"Описание","serviceType":"Delivery","organizationId":"org-1","creatorUsername":"alice"}
+
+{"name":"Битый JSON"
+{"name":"Лишнее поле","price":100}
+{"name":"Со статусом","status":"Published"}
+`
+	records, err := parseJSONLImport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseJSONLImport: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want 4", len(records))
+	}
+	if records[0].err != nil || records[0].body.CreatorUsername != "alice" || records[0].row != 1 {
+		t.Errorf("row 1 = %+v", records[0])
+	}
+	for _, rec := range records[1:3] {
+		if rec.err == nil {
+			t.Errorf("row %d has no error", rec.row)
+		}
+	}
+	if records[3].row != 5 || !errors.Is(records[3].err, errImportStatus) {
+		t.Errorf("row with status = %+v, want errImportStatus", records[3])
+	}
+}
+
+// Число строк ограничено maxImportRows
+func TestParseImportTooLarge(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("name\n")
+	for i := 0; i <= maxImportRows; i++ {
+		b.WriteString("T\n")
+	}
+	if _, err := parseCSVImport(strings.NewReader(b.String())); !errors.Is(err, errImportTooLarge) {
+		t.Errorf("CSV error = %v, want errImportTooLarge", err)
+	}
+	jsonl := strings.Repeat("{}\n", maxImportRows+1)
+	if _, err := parseJSONLImport(strings.NewReader(jsonl)); !errors.Is(err, errImportTooLarge) {
+		t.Errorf("JSONL error = %v, want errImportTooLarge", err)
+	}
+}
+
+// importStorage считает размеры пакетов ImportTenders; строки сохраняются,
+// кроме строки с именем failName
+type importStorage struct {
+	lifecycleStorage
+	batches  []int
+	failName string
+}
+
+func (m *importStorage) IsOrganizationResponsible(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
+func (m *importStorage) ImportTenders(_ context.Context, bodies []model.TendersNewBody, allOrNothing, rollback bool) ([]storage.TenderImportResult, bool, error) {
+	m.batches = append(m.batches, len(bodies))
+	results := make([]storage.TenderImportResult, len(bodies))
+	failed := false
+	for i, body := range bodies {
+		if m.failName != "" && body.Name == m.failName {
+			results[i].Err = errors.New("value too long")
+			failed = true
+			continue
+		}
+		results[i].Tender.Id = fmt.Sprintf("t-%d-%d", len(m.batches), i)
+	}
+	return results, !rollback && !(failed && allOrNothing), nil
+}
+
+func importCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("name,description,serviceType,organizationId,creatorUsername\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "Тендер %d,Описание,Delivery,org-1,alice\n", i)
+	}
+	return b.String()
+}
+
+// Строки сохраняются пакетами; одна транзакция на файл ограничена maxImportBatchSize
+func TestImportTendersBatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		rows        int
+		want        int
+		wantBatches []int
+	}{
+		{"default batch size", "", 250, http.StatusOK, []int{100, 100, 50}},
+		{"custom batch size", "batchSize=120", 250, http.StatusOK, []int{120, 120, 10}},
+		{"single transaction", "batchSize=0", 250, http.StatusOK, []int{250}},
+		{"single transaction over limit", "batchSize=0", maxImportBatchSize + 1, http.StatusBadRequest, nil},
+		{"batch size over limit", "batchSize=501", 10, http.StatusBadRequest, nil},
+		{"negative batch size", "batchSize=-1", 10, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &importStorage{}
+			s := newLifecycleServer(t)
+			s.storage = store
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenders/import?"+tt.query, strings.NewReader(importCSV(tt.rows)))
+			req.Header.Set(echo.HeaderContentType, "text/csv")
+			rec := httptest.NewRecorder()
+			e := echo.New()
+			ctx := e.NewContext(req, rec)
+			if err := s.ImportTenders(ctx); err != nil {
+				e.HTTPErrorHandler(err, ctx)
+			}
+
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if fmt.Sprint(store.batches) != fmt.Sprint(tt.wantBatches) {
+				t.Errorf("batches = %v, want %v", store.batches, tt.wantBatches)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var report model.TenderImportReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Total != tt.rows || report.Created != tt.rows || report.Failed != 0 {
+				t.Errorf("report total %d created %d failed %d", report.Total, report.Created, report.Failed)
+			}
+		})
+	}
+}
+
+// Ошибка базы в строке пакета не отменяет остальные строки пакета; в одной
+// транзакции (batchSize=0) отменяет весь импорт
+func TestImportTendersRowFailure(t *testing.T) {
+	tests := []struct {
+		query       string
+		wantCreated int
+		wantStatus  []model.TenderImportRowStatus
+	}{
+		{"batchSize=2", 3, []model.TenderImportRowStatus{
+			model.TenderImportRowCreated, model.TenderImportRowFailed, model.TenderImportRowCreated, model.TenderImportRowCreated}},
+		{"batchSize=0", 0, []model.TenderImportRowStatus{
+			model.TenderImportRowSkipped, model.TenderImportRowFailed, model.TenderImportRowSkipped, model.TenderImportRowSkipped}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			s := newLifecycleServer(t)
+			s.storage = &importStorage{failName: "Тендер 1"}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenders/import?"+tt.query, strings.NewReader(importCSV(4)))
+			req.Header.Set(echo.HeaderContentType, "text/csv")
+			rec := httptest.NewRecorder()
+			if err := s.ImportTenders(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+
+			var report model.TenderImportReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Created != tt.wantCreated || report.Failed != 1 {
+				t.Fatalf("report created %d failed %d, want %d and 1", report.Created, report.Failed, tt.wantCreated)
+			}
+			for i, row := range report.Rows {
+				if row.Status != tt.wantStatus[i] {
+					t.Errorf("row %d status %s, want %s", row.Row, row.Status, tt.wantStatus[i])
+				}
+				if (row.Status == model.TenderImportRowCreated) != (row.TenderId != "") {
+					t.Errorf("row %d: status %s with tender %q", row.Row, row.Status, row.TenderId)
+				}
+			}
+			if report.Rows[1].Error != "Failed to save tender" {
+				t.Errorf("failed row error = %q", report.Rows[1].Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/mail"
+
+	"go-tenders/model"
+	"go-tenders/notify"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetNotificationPreferences настройки уведомлений пользователя (GET /notifications/preferences).
+// Пока пользователь их не сохранил, возвращаются значения по умолчанию без адреса.
+func (s *Server) GetNotificationPreferences(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+
+	prefs, err := s.storage.GetNotificationPreferences(ctx.Request().Context(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		prefs = model.NotificationPreferences{
+			Username:         username,
+			Locale:           notify.DefaultLocale,
+			BidReceived:      true,
+			DecisionRequired: true,
+			BidDecision:      true,
+		}
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetNotificationPreferences error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get notification preferences")
+	}
+	return ctx.JSON(http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences сохраняет настройки уведомлений пользователя (PUT /notifications/preferences)
+func (s *Server) UpdateNotificationPreferences(ctx echo.Context) error {
+	username := ctx.QueryParam("username")
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+
+	var prefs model.NotificationPreferences
+	if err := ctx.Bind(&prefs); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "UpdateNotificationPreferences bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	addr, err := mail.ParseAddress(prefs.Email)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid email")
+	}
+	// Сохраняется только адрес: "Имя <a@b>" не годится для RCPT TO
+	prefs.Email = addr.Address
+	if prefs.Locale == "" {
+		prefs.Locale = notify.DefaultLocale
+	}
+	if prefs.Locale != "ru" && prefs.Locale != "en" {
+		return echo.NewHTTPError(http.StatusBadRequest, "locale must be ru or en")
+	}
+	prefs.Username = username
+
+	if err := s.storage.SaveNotificationPreferences(ctx.Request().Context(), prefs); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "SaveNotificationPreferences error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save notification preferences")
+	}
+	return ctx.JSON(http.StatusOK, prefs)
+}
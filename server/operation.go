@@ -0,0 +1,39 @@
+package server
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// operationResolver возвращает имя операции API для запроса — имя метода-обработчика
+// маршрута (например, GetTenders), поэтому метрики и трассы не зависят от
+// идентификаторов в пути. Запросы без маршрута получают имя "unmatched".
+func operationResolver(e *echo.Echo) func(ctx echo.Context) string {
+	var once sync.Once
+	var operations map[string]string
+
+	return func(ctx echo.Context) string {
+		// Маршруты регистрируются после middleware, поэтому таблица строится при первом запросе
+		once.Do(func() {
+			operations = make(map[string]string)
+			for _, r := range e.Routes() {
+				operations[r.Method+" "+r.Path] = handlerName(r.Name)
+			}
+		})
+		if op, ok := operations[ctx.Request().Method+" "+ctx.Path()]; ok {
+			return op
+		}
+		return "unmatched"
+	}
+}
+
+// handlerName извлекает имя метода из полного имени функции,
+// например "go-tenders/api.(*ServerInterfaceWrapper).GetTenders-fm" → "GetTenders"
+func handlerName(fn string) string {
+	if i := strings.LastIndex(fn, "."); i >= 0 {
+		fn = fn[i+1:]
+	}
+	return strings.TrimSuffix(fn, "-fm")
+}
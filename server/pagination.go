@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageLimit = 5
+	maxPageLimit     = 50
+)
+
+// cursorPage разбирает параметр cursor. Keyset-пагинация включается, если параметр
+// присутствует в запросе; пустое значение означает первую страницу.
+func cursorPage(ctx echo.Context, limit *int32) (storage.Page, bool, error) {
+	if !ctx.QueryParams().Has("cursor") {
+		return storage.Page{}, false, nil
+	}
+	if ctx.QueryParams().Has("offset") {
+		return storage.Page{}, true, echo.NewHTTPError(http.StatusBadRequest, "cursor and offset cannot be used together")
+	}
+
+	page := storage.Page{Limit: defaultPageLimit}
+	if limit != nil {
+		page.Limit = int(*limit)
+	}
+	if page.Limit < 0 || page.Limit > maxPageLimit {
+		return page, true, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 0 and %d", maxPageLimit))
+	}
+
+	if token := ctx.QueryParam("cursor"); token != "" {
+		cursor, err := storage.DecodeCursor(token)
+		if err != nil {
+			return page, true, echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+		page.Cursor = cursor
+	}
+	return page, true, nil
+}
+
+// offsetPage разбирает limit/offset с ограничениями из спецификации
+func offsetPage(limit, offset *int32) (int, int, error) {
+	l, o := defaultPageLimit, 0
+	if limit != nil {
+		l = int(*limit)
+	}
+	if offset != nil {
+		o = int(*offset)
+	}
+	if l < 0 || l > maxPageLimit {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 0 and %d", maxPageLimit))
+	}
+	if o < 0 {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "offset must not be negative")
+	}
+	return l, o, nil
+}
+
+// listQuery разбирает параметры сортировки и фильтрации по белому списку схемы
+func listQuery(ctx echo.Context, schema storage.ListSchema) (storage.ListQuery, error) {
+	q, err := schema.Parse(ctx.QueryParams())
+	if err != nil {
+		return q, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return q, nil
+}
+
+// setPageLinks добавляет заголовок Link (RFC 8288) со ссылками на соседние страницы
+func setPageLinks(ctx echo.Context, info storage.PageInfo) {
+	var links []string
+	for _, l := range []struct{ rel, cursor string }{{"next", info.Next}, {"prev", info.Prev}} {
+		if l.cursor == "" {
+			continue
+		}
+		u := *ctx.Request().URL
+		q := u.Query()
+		q.Set("cursor", l.cursor)
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), l.rel))
+	}
+	if len(links) > 0 {
+		ctx.Response().Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func (s *Server) getTendersByCursor(ctx echo.Context, q storage.ListQuery, username string, page storage.Page) error {
+	if len(q.Sort) > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "sort cannot be used with cursor pagination")
+	}
+	tenders, info, err := s.storage.ListTenders(ctx.Request().Context(), q, username, page)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetTenders error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tenders")
+	}
+	setPageLinks(ctx, info)
+	if tenders == nil {
+		tenders = []model.Tender{}
+	}
+	return ctx.JSON(http.StatusOK, tenders)
+}
+
+func (s *Server) getUserTendersByCursor(ctx echo.Context, username string, page storage.Page) error {
+	tenders, info, err := s.storage.ListUserTenders(ctx.Request().Context(), username, page)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetUserTenders error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user tenders")
+	}
+	setPageLinks(ctx, info)
+	if tenders == nil {
+		tenders = []model.Tender{}
+	}
+	return ctx.JSON(http.StatusOK, tenders)
+}
+
+func (s *Server) getUserBidsByCursor(ctx echo.Context, username string, page storage.Page) error {
+	bids, info, err := s.storage.ListUserBids(ctx.Request().Context(), username, page)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetUserBids error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user bids")
+	}
+	setPageLinks(ctx, info)
+	if bids == nil {
+		bids = []model.Bid{}
+	}
+	return ctx.JSON(http.StatusOK, bids)
+}
+
+func (s *Server) getBidsForTenderByCursor(ctx echo.Context, tenderId model.TenderId, page storage.Page) error {
+	bids, info, err := s.storage.ListBidsForTender(ctx.Request().Context(), tenderId, page)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetBidsForTender error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bids for tender")
+	}
+	setPageLinks(ctx, info)
+	if bids == nil {
+		bids = []model.Bid{}
+	}
+	return ctx.JSON(http.StatusOK, bids)
+}
+
+func (s *Server) getBidReviewsByCursor(ctx echo.Context, tenderId model.TenderId, authorUsername string, page storage.Page) error {
+	reviews, info, err := s.storage.ListBidReviews(ctx.Request().Context(), tenderId, authorUsername, page)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetBidReviews error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bid reviews")
+	}
+	setPageLinks(ctx, info)
+	if reviews == nil {
+		reviews = []model.BidReview{}
+	}
+	return ctx.JSON(http.StatusOK, reviews)
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxRequestBodySize сколько тела запроса читается до обработчика
+const maxRequestBodySize = 1 << 20
+
+// Ключи значений запроса в echo.Context
+const (
+	requestBodyKey = "server.requestBody"
+	principalKey   = "server.principal"
+)
+
+// creatorOperations операции, автор которых передается в теле запроса
+var creatorOperations = map[string]bool{
+	"CreateTender": true,
+	"CreateBid":    true,
+}
+
+// creatorBody автор создаваемого тендера или предложения
+type creatorBody struct {
+	CreatorUsername string `json:"creatorUsername"`
+	OrganizationId  string `json:"organizationId"`
+}
+
+type cachedBody struct {
+	data []byte
+	err  error
+}
+
+// errorReader тело запроса, чтение которого завершилось ошибкой
+type errorReader struct{ err error }
+
+func (r errorReader) Read([]byte) (int, error) { return 0, r.err }
+
+// requestBody читает тело запроса не больше maxRequestBodySize и возвращает его
+// обработчику нетронутым. Повторный вызов отдает тело из контекста. Если тело
+// слишком большое, обработчик получит ту же ошибку при чтении.
+func requestBody(ctx echo.Context) ([]byte, error) {
+	if cached, ok := ctx.Get(requestBodyKey).(cachedBody); ok {
+		return cached.data, cached.err
+	}
+	req := ctx.Request()
+	data, err := io.ReadAll(http.MaxBytesReader(ctx.Response(), req.Body, maxRequestBodySize))
+	if err != nil {
+		req.Body = io.NopCloser(errorReader{err})
+	} else {
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	ctx.Set(requestBodyKey, cachedBody{data: data, err: err})
+	return data, err
+}
+
+// requestCreator автор из тела запроса на создание; для остальных операций
+// и нечитаемого тела — пустой
+func requestCreator(ctx echo.Context, op string) creatorBody {
+	var creator creatorBody
+	if !creatorOperations[op] {
+		return creator
+	}
+	body, err := requestBody(ctx)
+	if err != nil {
+		return creator
+	}
+	if json.Unmarshal(body, &creator) != nil {
+		return creatorBody{}
+	}
+	return creator
+}
+
+// identityParams параметры запроса, которыми клиент называет себя. С токеном
+// они должны совпадать с его владельцем; authorUsername в запросе отзывов —
+// автор, чьи отзывы смотрят, а не сам клиент, поэтому не сверяется.
+var identityParams = []string{"username", "requesterUsername"}
+
+// authMiddleware определяет сотрудника по заголовку Authorization: Bearer.
+// Неизвестный, просроченный или отозванный токен — 401; параметры из
+// identityParams или автор создаваемой сущности, отличные от владельца токена,
+// — 403. Отсутствующие username и requesterUsername (для отзывов)
+// подставляются из токена.
+//
+// Запросы без заголовка проходят как раньше, с пользователем из параметров:
+// имя никак не подтверждается, и клиент может назваться кем угодно. Такие
+// запросы ограничиваются только квотой IP.
+func (s *Server) authMiddleware(operation func(echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			header := ctx.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(ctx)
+			}
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid Authorization header")
+			}
+
+			reqCtx := ctx.Request().Context()
+			username, err := s.storage.APITokenUsername(reqCtx, strings.TrimSpace(token))
+			if errors.Is(err, sql.ErrNoRows) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired token")
+			}
+			if err != nil {
+				s.logger.ErrorContext(reqCtx, "APITokenUsername error", "error", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check token")
+			}
+
+			query := ctx.QueryParams()
+			for _, param := range identityParams {
+				if u := query.Get(param); u != "" && u != username {
+					return echo.NewHTTPError(http.StatusForbidden, param+" does not match the token")
+				}
+			}
+			op := operation(ctx)
+			if c := requestCreator(ctx, op).CreatorUsername; c != "" && c != username {
+				return echo.NewHTTPError(http.StatusForbidden, "creatorUsername does not match the token")
+			}
+			// QueryParams — кэш echo, поэтому обработчики увидят подставленный параметр
+			query.Set("username", username)
+			if op == "GetBidReviews" {
+				query.Set("requesterUsername", username)
+			}
+			ctx.Request().URL.RawQuery = query.Encode()
+			ctx.Set(principalKey, username)
+			return next(ctx)
+		}
+	}
+}
+
+// principal сотрудник, подтвердивший себя токеном; пусто — запрос без токена
+func principal(ctx echo.Context) string {
+	username, _ := ctx.Get(principalKey).(string)
+	return username
+}
+
+// requestUser пользователь запроса: владелец токена, автор из тела для
+// операций создания, иначе параметр username
+func requestUser(ctx echo.Context, op string) string {
+	if p := principal(ctx); p != "" {
+		return p
+	}
+	if creator := requestCreator(ctx, op); creator.CreatorUsername != "" {
+		return creator.CreatorUsername
+	}
+	return ctx.QueryParam("username")
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newBodyContext(body string) echo.Context {
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/new?username=query-user", strings.NewReader(body))
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+// Пользователь операции создания берется из тела, а не из параметра username
+func TestRequestUser(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+		body string
+		want string
+	}{
+		{"creator from body", "CreateTender", `{"creatorUsername":"alice","organizationId":"1"}`, "alice"},
+		{"no creator in body", "CreateBid", `{"name":"bid"}`, "query-user"},
+		{"invalid body", "CreateTender", `{`, "query-user"},
+		{"not a create operation", "EditTender", `{"creatorUsername":"alice"}`, "query-user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestUser(newBodyContext(tt.body), tt.op); got != tt.want {
+				t.Errorf("requestUser = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Прочитанное тело остается доступным обработчику
+func TestRequestBodyRestored(t *testing.T) {
+	body := `{"creatorUsername":"alice","organizationId":"1"}`
+	ctx := newBodyContext(body)
+	if creator := requestCreator(ctx, "CreateTender"); creator.OrganizationId != "1" {
+		t.Fatalf("organizationId = %q, want 1", creator.OrganizationId)
+	}
+	// Повторное чтение берет тело из контекста
+	if _, err := requestBody(ctx); err != nil {
+		t.Fatalf("requestBody: %v", err)
+	}
+	got, err := io.ReadAll(ctx.Request().Body)
+	if err != nil || string(got) != body {
+		t.Errorf("handler body = %q, %v; want %q", got, err, body)
+	}
+}
+
+// Слишком большое тело не читается целиком, обработчик получает ошибку
+func TestRequestBodyTooLarge(t *testing.T) {
+	ctx := newBodyContext(strings.Repeat("x", maxRequestBodySize+1))
+	var maxErr *http.MaxBytesError
+	if _, err := requestBody(ctx); !errors.As(err, &maxErr) {
+		t.Fatalf("requestBody error = %v, want MaxBytesError", err)
+	}
+	if requestUser(ctx, "CreateTender") != "query-user" {
+		t.Error("user taken from oversized body")
+	}
+	if _, err := io.ReadAll(ctx.Request().Body); !errors.As(err, &maxErr) {
+		t.Errorf("handler read error = %v, want MaxBytesError", err)
+	}
+}
+
+// tokenStorage действующие токены: токен → сотрудник
+type tokenStorage struct {
+	lifecycleStorage
+	tokens map[string]string
+}
+
+func (m tokenStorage) APITokenUsername(_ context.Context, token string) (string, error) {
+	username, ok := m.tokens[token]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return username, nil
+}
+
+// authMiddleware проверяет токен и сверяет с ним пользователя запроса
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		query         string
+		body          string
+		want          int
+		wantUser      string
+		wantPrincipal string
+	}{
+		{"no token", "", "username=alice", "", http.StatusOK, "alice", ""},
+		{"token injects username", "Bearer tnd_alice", "", "", http.StatusOK, "alice", "alice"},
+		{"token matches username", "bearer tnd_alice", "username=alice", "", http.StatusOK, "alice", "alice"},
+		{"token for other user", "Bearer tnd_alice", "username=bob", "", http.StatusForbidden, "", ""},
+		{"requester for other user", "Bearer tnd_alice", "requesterUsername=bob", "", http.StatusForbidden, "", ""},
+		{"creator does not match token", "Bearer tnd_alice", "", `{"creatorUsername":"bob"}`, http.StatusForbidden, "", ""},
+		{"unknown token", "Bearer tnd_revoked", "", "", http.StatusUnauthorized, "", ""},
+		{"not a bearer token", "Basic YWxpY2U6", "", "", http.StatusUnauthorized, "", ""},
+		{"empty token", "Bearer ", "", "", http.StatusUnauthorized, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLifecycleServer(t)
+			s.storage = tokenStorage{tokens: map[string]string{"tnd_alice": "alice"}}
+
+			var gotUser, gotPrincipal string
+			h := s.authMiddleware(func(echo.Context) string { return "CreateTender" })(func(ctx echo.Context) error {
+				gotUser, gotPrincipal = ctx.QueryParam("username"), principal(ctx)
+				return ctx.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tenders/new?"+tt.query, strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e := echo.New()
+			ctx := e.NewContext(req, rec)
+			// Журнал запросов читает параметры до проверки токена
+			ctx.QueryParam("username")
+			if err := h(ctx); err != nil {
+				e.HTTPErrorHandler(err, ctx)
+			}
+
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d", rec.Code, tt.want)
+			}
+			if gotUser != tt.wantUser || gotPrincipal != tt.wantPrincipal {
+				t.Errorf("username %q, principal %q; want %q, %q", gotUser, gotPrincipal, tt.wantUser, tt.wantPrincipal)
+			}
+			if tt.wantPrincipal != "" && requestUser(ctx, "CreateTender") != tt.wantPrincipal {
+				t.Errorf("requestUser = %q, want token owner", requestUser(ctx, "CreateTender"))
+			}
+		})
+	}
+}
+
+// В запрос отзывов подставляется requesterUsername владельца токена, а
+// authorUsername остается как есть
+func TestAuthMiddlewareReviewRequester(t *testing.T) {
+	s := newLifecycleServer(t)
+	s.storage = tokenStorage{tokens: map[string]string{"tnd_alice": "alice"}}
+
+	var requester, author string
+	h := s.authMiddleware(func(echo.Context) string { return "GetBidReviews" })(func(ctx echo.Context) error {
+		requester, author = ctx.QueryParam("requesterUsername"), ctx.QueryParam("authorUsername")
+		return ctx.NoContent(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bids/t-1/reviews?authorUsername=bob", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer tnd_alice")
+	rec := httptest.NewRecorder()
+	if err := h(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if requester != "alice" || author != "bob" {
+		t.Fatalf("requesterUsername %q, authorUsername %q; want alice, bob", requester, author)
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go-tenders/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// organizationsTTL сколько кэшируются организации пользователя для квот
+const organizationsTTL = time.Minute
+
+// SetRateLimitStore заменяет хранилище квот, например на общее для нескольких
+// экземпляров сервиса. Вызывается до Start.
+func (s *Server) SetRateLimitStore(store ratelimit.Store) {
+	s.rateLimits = store
+}
+
+// rateLimitMiddleware ограничивает частоту запросов к API по IP, пользователю
+// и его организациям; квоты берутся из действующей конфигурации. Квоты
+// пользователя и организаций считаются только для владельца токена: имя из
+// параметров или тела клиент может подставить чужое, поэтому запросы без
+// токена ограничиваются только квотой IP. Организации берутся из базы после
+// проверки квот IP и пользователя.
+func (s *Server) rateLimitMiddleware() echo.MiddlewareFunc {
+	orgs := &organizationCache{entries: make(map[string]organizationEntry)}
+	return ratelimit.Middleware(ratelimit.Config{
+		Store: s.rateLimits,
+		Limits: func() ratelimit.Limits {
+			cfg := s.config.Load()
+			if cfg == nil {
+				return ratelimit.Limits{}
+			}
+			return cfg.RateLimits()
+		},
+		User: principal,
+		Organizations: func(ctx echo.Context, user string) ([]string, error) {
+			return orgs.get(user, func() ([]string, error) {
+				return s.storage.ResponsibleOrganizations(ctx.Request().Context(), user)
+			})
+		},
+		// Пробы и метрики не ограничиваются
+		Skipper: func(ctx echo.Context) bool {
+			return !strings.HasPrefix(ctx.Request().URL.Path, "/api/")
+		},
+		Logger: s.logger,
+	})
+}
+
+// organizationCache организации пользователей, чтобы не ходить в базу на каждый запрос
+type organizationCache struct {
+	mu      sync.Mutex
+	entries map[string]organizationEntry
+}
+
+type organizationEntry struct {
+	organizations []string
+	expires       time.Time
+}
+
+func (c *organizationCache) get(username string, load func() ([]string, error)) ([]string, error) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[username]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.organizations, nil
+	}
+
+	organizations, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Удаляем устаревшие записи, чтобы кэш не рос без ограничений
+	for name, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, name)
+		}
+	}
+	c.entries[username] = organizationEntry{organizations: organizations, expires: now.Add(organizationsTTL)}
+	return organizations, nil
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-tenders/config"
+	"go-tenders/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rateLimitStorage токен tnd_alice сотрудника alice из организации org-1
+type rateLimitStorage struct {
+	tokenStorage
+}
+
+func (rateLimitStorage) ResponsibleOrganizations(context.Context, string) ([]string, error) {
+	return []string{"org-1"}, nil
+}
+
+func newRateLimitServer(t *testing.T, cfg *config.Config) http.Handler {
+	t.Helper()
+	s := newLifecycleServer(t)
+	s.storage = rateLimitStorage{tokenStorage{tokens: map[string]string{"tnd_alice": "alice"}}}
+	cfg.RateLimitEnabled = true
+	s.SetConfig(cfg)
+	return s.Handler()
+}
+
+func limitedRequest(h http.Handler, remoteAddr, forwardedFor, authorization string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ping?username=alice", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	}
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// Квота пользователя считается только по владельцу токена, а не по username
+func TestRateLimitUserQuotaRequiresToken(t *testing.T) {
+	h := newRateLimitServer(t, &config.Config{RateLimitUserRead: ratelimit.Limit{Requests: 1, Period: time.Minute}})
+
+	for i, addr := range []string{"192.0.2.1:1000", "192.0.2.2:1000"} {
+		if code := limitedRequest(h, addr, "", ""); code == http.StatusTooManyRequests {
+			t.Fatalf("request %d without token limited by claimed username", i)
+		}
+	}
+	if code := limitedRequest(h, "192.0.2.3:1000", "", "Bearer tnd_alice"); code == http.StatusTooManyRequests {
+		t.Fatal("first token request limited")
+	}
+	if code := limitedRequest(h, "192.0.2.4:1000", "", "Bearer tnd_alice"); code != http.StatusTooManyRequests {
+		t.Fatalf("second token request: status %d, want %d", code, http.StatusTooManyRequests)
+	}
+}
+
+// X-Forwarded-For учитывается только от доверенных прокси
+func TestRateLimitClientIP(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+
+	h := newRateLimitServer(t, &config.Config{RateLimitIPRead: limit})
+	limitedRequest(h, "192.0.2.1:1000", "203.0.113.1", "")
+	if code := limitedRequest(h, "192.0.2.1:1000", "203.0.113.2", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed X-Forwarded-For: status %d, want %d", code, http.StatusTooManyRequests)
+	}
+
+	h = newRateLimitServer(t, &config.Config{RateLimitIPRead: limit, TrustedProxies: []string{"192.0.2.0/24"}})
+	for i, client := range []string{"203.0.113.1", "203.0.113.2"} {
+		if code := limitedRequest(h, "192.0.2.1:1000", client, ""); code == http.StatusTooManyRequests {
+			t.Fatalf("client %d behind trusted proxy limited by proxy IP", i)
+		}
+	}
+	if code := limitedRequest(h, "192.0.2.1:1000", "203.0.113.1", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("repeated client behind trusted proxy: status %d, want %d", code, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"go-tenders/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Search полнотекстовый поиск по тендерам и предложениям (GET /search).
+// Неопубликованные сущности видны по тем же правилам, что и в списках, для username.
+func (s *Server) Search(ctx echo.Context) error {
+	var params model.SearchParams
+	if err := ctx.Bind(&params); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "Search bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid search parameters")
+	}
+
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Search query must not be empty")
+	}
+	if params.Kind != nil && *params.Kind != model.SearchResultKindTender && *params.Kind != model.SearchResultKindBid {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown search kind")
+	}
+	if params.CreatedFrom != nil && params.CreatedTo != nil && params.CreatedFrom.After(*params.CreatedTo) {
+		return echo.NewHTTPError(http.StatusBadRequest, "createdFrom must not be after createdTo")
+	}
+
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
+	}
+
+	results, err := s.storage.Search(ctx.Request().Context(), params, ctx.QueryParam("username"), limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "Search error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search")
+	}
+	if results == nil {
+		results = []model.SearchResult{}
+	}
+	return ctx.JSON(http.StatusOK, results)
+}
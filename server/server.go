@@ -1,12 +1,23 @@
 package server
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go-tenders/api"
 	"go-tenders/config"
+	"go-tenders/logging"
+	"go-tenders/metrics"
 	"go-tenders/model"
+	"go-tenders/ratelimit"
 	"go-tenders/storage"
+	"go-tenders/tracing"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -16,6 +27,9 @@ import (
 type Logger interface {
 	Info(args ...interface{})
 	Error(args ...interface{})
+	// InfoContext, ErrorContext добавляют к записи поля запроса из ctx (request_id, user)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
 }
 
 // Config структура конфигурации сервера
@@ -28,39 +42,193 @@ type Config struct {
 type Server struct {
 	storage storage.Storage
 	logger  Logger
-	config  *config.Config
+	// config действующая конфигурация; заменяется при перезагрузке через SetConfig
+	config atomic.Pointer[config.Config]
+	events *EventLog
+	hub    *Hub
+	// rateLimits хранилище квот; по умолчанию в памяти процесса
+	rateLimits ratelimit.Store
+
+	echo *echo.Echo
+	// ctx живет до Shutdown: по его отмене останавливаются фоновые задачи
+	// и закрываются потоковые соединения (SSE, WebSocket)
+	ctx        context.Context
+	cancel     context.CancelFunc
+	background sync.WaitGroup
+	// draining выставляется в начале остановки, чтобы /health/ready снял экземпляр с балансировки
+	draining atomic.Bool
 }
 
 // Проверка соответствия интерфейсу api.ServerInterface
 var _ api.ServerInterface = (*Server)(nil)
 
-// Конструктор сервера
+// accessLogger логгер, умеющий вести журнал запросов Echo
+type accessLogger interface {
+	AccessLog() echo.MiddlewareFunc
+}
+
+// Конструктор сервера; без логгера используется JSON-логгер на stdout
 func NewServer(storage storage.Storage, logger Logger, cfg *config.Config) *Server {
-	return &Server{
-		storage: storage,
-		logger:  logger,
-		config:  cfg,
+	if logger == nil {
+		logger = logging.Default()
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		storage:    storage,
+		logger:     logger,
+		events:     NewEventLog(defaultEventLogCapacity),
+		hub:        NewHub(defaultHubClientBuffer),
+		rateLimits: ratelimit.NewMemoryStore(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	s.config.Store(cfg)
+	return s
 }
 
-// Метод запуска HTTP сервера
+// SetConfig применяет перезагруженную конфигурацию без перезапуска сервера
+func (s *Server) SetConfig(cfg *config.Config) {
+	s.config.Store(cfg)
+}
+
+// Метод запуска HTTP сервера. Блокируется до остановки; после Shutdown
+// возвращает http.ErrServerClosed.
 func (s *Server) Start(address string) error {
-	e := echo.New()
+	s.Handler()
+	s.startBackground()
+
+	s.logger.Info("Server starting at ", address)
+	return s.echo.Start(address)
+}
+
+// Handler HTTP-обработчик со всеми маршрутами и middleware сервера — для
+// httptest и встраивания в собственный http.Server. Фоновые задачи не запускает.
+func (s *Server) Handler() http.Handler {
+	if s.echo == nil {
+		s.echo = s.newEcho()
+	}
+	return s.echo
+}
+
+// Shutdown останавливает сервер: снимает его с балансировки, закрывает потоковые
+// соединения и фоновые задачи, перестает принимать запросы и ждет завершения
+// текущих не дольше, чем позволяет ctx
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+	s.cancel()
 
-	// Добавляем middleware для логирования и восстановления после паники
-	e.Use(middleware.Logger())
+	var err error
+	if s.echo != nil {
+		err = s.echo.Shutdown(ctx)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// startBackground запускает фоновые задачи сервера; они завершаются по отмене s.ctx
+func (s *Server) startBackground() {
+	// Журнал событий для потоковых эндпоинтов читает outbox в фоне
+	s.background.Add(2)
+	go func() {
+		defer s.background.Done()
+		if err := s.events.Tail(s.ctx, s.storage, time.Second, s.logger); err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Error("event log stopped: ", err)
+		}
+	}()
+	go func() {
+		defer s.background.Done()
+		s.hub.Run(s.ctx, s.events, s.storage.BidRanking, s.logger)
+	}()
+}
+
+// newEcho создает Echo с middleware и всеми маршрутами сервера
+func (s *Server) newEcho() *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+	e.IPExtractor = ipExtractor(s.config.Load())
+
+	// Добавляем middleware для логирования и восстановления после паники.
+	// Журнал запросов пишется тем же логгером, что и сообщения сервера.
+	e.Use(middleware.RequestID())
+	if l, ok := s.logger.(accessLogger); ok {
+		e.Use(l.AccessLog())
+	} else {
+		e.Use(middleware.Logger())
+	}
 	e.Use(middleware.Recover())
+	operation := operationResolver(e)
+	e.Use(tracing.Middleware(operation))
+	e.Use(metrics.Middleware(operation))
+	e.Use(s.authMiddleware(operation))
+	e.Use(s.rateLimitMiddleware())
+	e.Use(s.idempotencyMiddleware(operation))
+	e.Use(auditMiddleware)
 
 	// Регистрируем обработчики API с префиксом "/api/v1"
 	api.RegisterHandlersWithBaseURL(e, s, "/api/v1")
 
-	s.logger.Info("Server starting at ", address)
-	return e.Start(address)
+	// Дополнительные маршруты, не описанные в сгенерированном api
+	e.GET("/api/v1/search", s.Search, s.feature(config.FeatureSearch))
+	e.POST("/api/v1/tenders/import", s.ImportTenders, s.feature(config.FeatureImport))
+	e.GET("/api/v1/tenders/export", s.ExportTenders, s.feature(config.FeatureExport))
+	e.GET("/api/v1/tenders/my/export", s.ExportUserTenders, s.feature(config.FeatureExport))
+	e.GET("/api/v1/bids/:tenderId/list/export", s.ExportBidsForTender, s.feature(config.FeatureExport))
+	e.POST("/api/v1/webhooks", s.CreateWebhook)
+	e.GET("/api/v1/webhooks", s.ListWebhooks)
+	e.DELETE("/api/v1/webhooks/:webhookId", s.DeleteWebhook)
+	e.GET("/api/v1/webhooks/:webhookId/deliveries", s.ListWebhookDeliveries)
+	e.GET("/api/v1/events/stream", s.StreamEvents)
+	e.GET("/api/v1/tenders/:tenderId/bids/ws", s.BidBoard)
+	e.GET("/api/v1/notifications/preferences", s.GetNotificationPreferences)
+	e.PUT("/api/v1/notifications/preferences", s.UpdateNotificationPreferences)
+	e.POST("/api/v1/digests/subscriptions", s.CreateDigestSubscription)
+	e.GET("/api/v1/digests/subscriptions", s.ListDigestSubscriptions)
+	e.DELETE("/api/v1/digests/subscriptions/:subscriptionId", s.DeleteDigestSubscription)
+	e.GET("/api/v1/audit", s.GetAuditLog)
+	e.GET("/api/v1/audit/verify", s.VerifyAuditLog)
+
+	// Метрики Prometheus; бизнес-показатели считаются при каждом сборе
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	// Пробы Kubernetes
+	e.GET("/health/live", s.Live)
+	e.GET("/health/ready", s.Ready)
+
+	return e
 }
 
-// Реализация метода проверки сервера
+// ipExtractor определяет IP клиента для квот и журнала запросов. По умолчанию
+// это адрес соединения: X-Forwarded-For принимается только от прокси из
+// TRUSTED_PROXIES, иначе клиент мог бы подменить свой IP.
+func ipExtractor(cfg *config.Config) echo.IPExtractor {
+	if cfg == nil || len(cfg.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range cfg.TrustedProxies {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			options = append(options, echo.TrustIPRange(ipNet))
+		}
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// Реализация метода проверки сервера; ответ "ok" требуется README.
+// Состояние зависимостей отдает /health/ready.
 func (s *Server) CheckServer(ctx echo.Context) error {
-	return ctx.String(http.StatusOK, "pong")
+	return ctx.String(http.StatusOK, "ok")
 }
 
 func (s *Server) GetUserBids(ctx echo.Context, params model.GetUserBidsParams) error {
@@ -68,119 +236,231 @@ func (s *Server) GetUserBids(ctx echo.Context, params model.GetUserBidsParams) e
 	if params.Username != nil {
 		username = *params.Username
 	}
-	limit := 0
-	if params.Limit != nil {
-		limit = *params.Limit
+	if page, ok, err := cursorPage(ctx, params.Limit); ok {
+		if err != nil {
+			return err
+		}
+		return s.getUserBidsByCursor(ctx, username, page)
 	}
-	offset := 0
-	if params.Offset != nil {
-		offset = *params.Offset
+
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
+	}
+	q, err := listQuery(ctx, storage.BidListSchema)
+	if err != nil {
+		return err
 	}
 
-	bids, err := s.storage.GetBids(limit, offset, username)
+	bids, err := s.storage.QueryUserBids(ctx.Request().Context(), username, q, limit, offset)
 	if err != nil {
-		s.logger.Error("GetUserBids error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "GetUserBids error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user bids")
 	}
 
 	return ctx.JSON(http.StatusOK, bids)
 }
 
-func (s *Server) CreateBid(ctx echo.Context, bid model.Bid) error {
-	err := s.storage.CreateBid(bid)
-	if err != nil {
-		s.logger.Error("CreateBid error: ", err)
+// CreateBid создает предложение от имени организации (POST /bids/new).
+// Сгенерированная обертка не разбирает тело, поэтому параметр bid не используется.
+func (s *Server) CreateBid(ctx echo.Context, _ model.Bid) error {
+	var body model.BidsNewBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateBid bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := validateBidsNewBody(body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	reqCtx := ctx.Request().Context()
+	// Неопубликованный тендер не раскрывается: ответ тот же, что для несуществующего
+	tenderOrganization, status, err := s.storage.TenderOrganizationStatus(reqCtx, body.TenderId)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && status != model.Published) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	} else if err != nil {
+		s.logger.ErrorContext(reqCtx, "TenderOrganizationStatus error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create bid")
 	}
-	return ctx.JSON(http.StatusCreated, bid)
+	if err := s.authorizeBidder(ctx, body, tenderOrganization); err != nil {
+		return err
+	}
+
+	bid, err := s.storage.CreateBid(reqCtx, body)
+	if errors.Is(err, storage.ErrTenderNotPublished) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	} else if err != nil {
+		s.logger.ErrorContext(reqCtx, "CreateBid error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create bid")
+	}
+	return ctx.JSON(http.StatusOK, bid)
+}
+
+// authorizeBidder проверяет автора предложения: от имени организации подает
+// ее ответственный, а без организации — пользователь. Ни организация, ни
+// пользователь не могут подать предложение на тендер своей организации.
+func (s *Server) authorizeBidder(ctx echo.Context, body model.BidsNewBody, tenderOrganization string) error {
+	if body.OrganizationId != "" {
+		if err := s.authorizeResponsible(ctx, body.CreatorUsername, body.OrganizationId); err != nil {
+			return err
+		}
+		if body.OrganizationId == tenderOrganization {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot bid on a tender of own organization")
+		}
+		return nil
+	}
+
+	own, err := s.storage.IsOrganizationResponsible(ctx.Request().Context(), body.CreatorUsername, tenderOrganization)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "IsOrganizationResponsible error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check permissions")
+	}
+	if own {
+		return echo.NewHTTPError(http.StatusForbidden, "Cannot bid on a tender of own organization")
+	}
+	return nil
 }
 
 func (s *Server) EditBid(ctx echo.Context, bidId model.BidId, params model.EditBidParams) error {
-	err := s.storage.UpdateBid(bidId, params)
+	var body model.BidIdEditBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "EditBid bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	bid, err := s.storage.EditBid(ctx.Request().Context(), bidId, params, body)
 	if err != nil {
-		s.logger.Error("EditBid error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "EditBid error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to edit bid")
 	}
-	return ctx.NoContent(http.StatusNoContent)
+	return ctx.JSON(http.StatusOK, bid)
 }
 
 func (s *Server) SubmitBidFeedback(ctx echo.Context, bidId model.BidId, params model.SubmitBidFeedbackParams) error {
-	err := s.storage.SaveBidFeedback(bidId, params)
-	if err != nil {
-		s.logger.Error("SubmitBidFeedback error: ", err)
+	err := s.storage.SubmitBidFeedback(ctx.Request().Context(), bidId, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Bid not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "SubmitBidFeedback error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to submit bid feedback")
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 func (s *Server) RollbackBid(ctx echo.Context, bidId model.BidId, version int32, params model.RollbackBidParams) error {
-	err := s.storage.RollbackBid(bidId, version, params)
-	if err != nil {
-		s.logger.Error("RollbackBid error: ", err)
+	err := s.storage.RollbackBid(ctx.Request().Context(), bidId, version, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Bid or version not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "RollbackBid error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to rollback bid")
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 func (s *Server) GetBidStatus(ctx echo.Context, bidId model.BidId, params model.GetBidStatusParams) error {
-	status, err := s.storage.GetBidStatus(bidId, params)
-	if err != nil {
-		s.logger.Error("GetBidStatus error: ", err)
+	status, err := s.storage.GetBidStatus(ctx.Request().Context(), bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Bid not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetBidStatus error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get status bid")
 	}
 	return ctx.JSON(http.StatusOK, status)
 }
 
 func (s *Server) UpdateBidStatus(ctx echo.Context, bidId model.BidId, params model.UpdateBidStatusParams) error {
-	err := s.storage.UpdateBidStatus(didId, params)
+	err := s.storage.UpdateBidStatus(ctx.Request().Context(), bidId, params)
 	if err != nil {
-		s.logger.Error("UpdateBidStatus error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "UpdateBidStatus error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update status bid")
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// SubmitBidDecision принимает голос ответственного за организацию тендера
 func (s *Server) SubmitBidDecision(ctx echo.Context, bidId model.BidId, params model.SubmitBidDecisionParams) error {
-	err := s.storage.SubmitBidDecision(didId, params)
-	if err != nil {
-		s.logger.Error("SubmitBidDecision error: ", err)
+	if err := checkEnum("decision", params.Decision, bidDecisions, true); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	reqCtx := ctx.Request().Context()
+	organizationId, err := s.storage.BidTenderOrganization(reqCtx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Bid not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "BidTenderOrganization error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to submit decision bid")
+	}
+	if err := s.authorizeResponsible(ctx, params.Username, organizationId); err != nil {
+		return err
+	}
+
+	err = s.storage.SubmitBidDecision(reqCtx, bidId, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Bid not found")
+	} else if errors.Is(err, storage.ErrBidNotPublished) {
+		return echo.NewHTTPError(http.StatusConflict, "Bid is not published")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "SubmitBidDecision error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to submit decision bid")
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 func (s *Server) GetBidsForTender(ctx echo.Context, tenderId model.TenderId, params model.GetBidsForTenderParams) error {
-	bids, err := s.storage.GetBidsForTender(tenderId, params)
+	if page, ok, err := cursorPage(ctx, params.Limit); ok {
+		if err != nil {
+			return err
+		}
+		page.Username = params.Username
+		return s.getBidsForTenderByCursor(ctx, tenderId, page)
+	}
+
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
+	}
+	q, err := listQuery(ctx, storage.BidListSchema)
 	if err != nil {
-		s.logger.Error("GetBidsForTender error: ", err)
+		return err
+	}
+
+	bids, err := s.storage.QueryBidsForTender(ctx.Request().Context(), tenderId, q, params.Username, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetBidsForTender error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bids for tender")
 	}
 	return ctx.JSON(http.StatusOK, bids)
 }
 
+// GetBidReviews отдает ответственному за организацию тендера отзывы на прошлые
+// предложения автора, подавшего предложение на этот тендер
 func (s *Server) GetBidReviews(ctx echo.Context, tenderId model.TenderId, params model.GetBidReviewsParams) error {
-	authorUsername := ""
-	if params.AuthorUsername != nil {
-		authorUsername = *params.AuthorUsername
+	organizationId, err := s.storage.TenderOrganization(ctx.Request().Context(), tenderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "TenderOrganization error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bid reviews")
 	}
-	requesterUsername := ""
-	if params.RequesterUsername != nil {
-		requesterUsername = *params.RequesterUsername
+	if err := s.authorizeResponsible(ctx, params.RequesterUsername, organizationId); err != nil {
+		return err
 	}
 
-	limit := 0
-	if params.Limit != nil {
-		limit = *params.Limit
+	if page, ok, err := cursorPage(ctx, params.Limit); ok {
+		if err != nil {
+			return err
+		}
+		return s.getBidReviewsByCursor(ctx, tenderId, params.AuthorUsername, page)
 	}
 
-	offset := 0
-	if params.Offset != nil {
-		offset = *params.Offset
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
 	}
-
-	reviews, err := s.storage.GetBidReviews(tenderId, authorUsername, requesterUsername, limit, offset)
+	reviews, err := s.storage.GetBidReviews(ctx.Request().Context(), tenderId, params.AuthorUsername, limit, offset)
 	if err != nil {
-		s.logger.Error("GetBidReviews error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "GetBidReviews error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get bid reviews")
 	}
 
@@ -188,93 +468,159 @@ func (s *Server) GetBidReviews(ctx echo.Context, tenderId model.TenderId, params
 }
 
 func (s *Server) GetTenders(ctx echo.Context, params model.GetTendersParams) error {
-	limit := 0
-	if params.Limit != nil {
-		limit = *params.Limit
+	// service_type уже разобран генератором в params, но ListSchema читает его
+	// из строки запроса вместе с остальными фильтрами
+	q, err := listQuery(ctx, storage.TenderListSchema)
+	if err != nil {
+		return err
 	}
-	offset := 0
-	if params.Offset != nil {
-		offset = *params.Offset
+	// Неопубликованные тендеры видны только ответственным за организацию
+	username := ctx.QueryParam("username")
+	if page, ok, err := cursorPage(ctx, params.Limit); ok {
+		if err != nil {
+			return err
+		}
+		return s.getTendersByCursor(ctx, q, username, page)
 	}
-	serviceType := ""
-	if params.ServiceType != nil {
-		serviceType = *params.ServiceType
+
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
 	}
 
-	tenders, err := s.storage.GetTenders(limit, offset, serviceType)
+	tenders, err := s.storage.QueryTenders(ctx.Request().Context(), q, username, limit, offset)
 	if err != nil {
-		s.logger.Error("GetTenders error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "GetTenders error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tenders")
 	}
 	return ctx.JSON(http.StatusOK, tenders)
 }
 
 func (s *Server) GetUserTenders(ctx echo.Context, params model.GetUserTendersParams) error {
-	limit := 0
-	if params.Limit != nil {
-		limit = *params.Limit
-	}
-	offset := 0
-	if params.Offset != nil {
-		offset = *params.Offset
-	}
 	username := ""
 	if params.Username != nil {
 		username = *params.Username
 	}
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+	if page, ok, err := cursorPage(ctx, params.Limit); ok {
+		if err != nil {
+			return err
+		}
+		return s.getUserTendersByCursor(ctx, username, page)
+	}
 
-	tenders, err := s.storage.GetUserTenders(limit, offset, username)
+	limit, offset, err := offsetPage(params.Limit, params.Offset)
+	if err != nil {
+		return err
+	}
+	tenders, err := s.storage.GetUserTenders(ctx.Request().Context(), username, limit, offset)
 	if err != nil {
-		s.logger.Error("GetUserTenders error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "GetUserTenders error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user tenders")
 	}
 
 	return ctx.JSON(http.StatusOK, tenders)
 }
 
+// CreateTender создает тендер организации (POST /tenders/new)
 func (s *Server) CreateTender(ctx echo.Context) error {
-	var tender model.Tender
-	if err := ctx.Bind(&tender); err != nil {
-		s.logger.Error("CreateTender bind error: ", err)
+	var body model.TendersNewBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateTender bind error", "error", err)
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
+	if err := validateTendersNewBody(body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := s.authorizeResponsible(ctx, body.CreatorUsername, body.OrganizationId); err != nil {
+		return err
+	}
 
-	err := s.storage.CreateTender(tender)
+	tender, err := s.storage.CreateTender(ctx.Request().Context(), body)
 	if err != nil {
-		s.logger.Error("CreateTender error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateTender error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create tender")
 	}
 
-	return ctx.JSON(http.StatusCreated, tender)
+	return ctx.JSON(http.StatusOK, tender)
 }
 
+// EditTender меняет параметры тендера (PATCH /tenders/{tenderId}/edit).
+// Сгенерированная обертка не разбирает тело, поэтому оно читается здесь.
 func (s *Server) EditTender(ctx echo.Context, tenderId model.TenderId, params model.EditTenderParams) error {
-	err := s.storage.UpdateTender(tenderId, params)
+	var body model.TenderIdEditBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "EditTender bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := validateTenderIdEditBody(body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := s.authorizeTender(ctx, params.Username, tenderId); err != nil {
+		return err
+	}
+
+	tender, err := s.storage.EditTender(ctx.Request().Context(), tenderId, params, body)
 	if err != nil {
-		s.logger.Error("EditTender error: ", err)
+		s.logger.ErrorContext(ctx.Request().Context(), "EditTender error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to edit tender")
 	}
-	return ctx.NoContent(http.StatusNoContent)
+	return ctx.JSON(http.StatusOK, tender)
 }
 
 func (s *Server) RollbackTender(ctx echo.Context, tenderId model.TenderId, version int32, params model.RollbackTenderParams) error {
-	err := s.storage.RollbackTender(ctx, tenderId, version, params)
-	if err != nil {
-		s.logger.Error("RollbackTender error: ", err)
+	if err := s.authorizeTender(ctx, params.Username, tenderId); err != nil {
+		return err
+	}
+	err := s.storage.RollbackTender(ctx.Request().Context(), tenderId, version, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender or version not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "RollbackTender error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to rollback tender")
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 func (s *Server) GetTenderStatus(ctx echo.Context, tenderId model.TenderId, params model.GetTenderStatusParams) error {
-	// Получаем стандартный context.Context из echo.Context
-	stdCtx := ctx.Request().Context()
-
-	// Вызываем метод хранения, передавая стандартный Context
-	status, err := s.storage.GetTenderStatus(stdCtx, tenderId, params)
-	if err != nil {
-		s.logger.Error("GetTenderStatus error: ", err)
+	status, err := s.storage.GetTenderStatus(ctx.Request().Context(), tenderId, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetTenderStatus error", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tender status")
 	}
 	return ctx.JSON(http.StatusOK, status)
 }
+
+// UpdateTenderStatus меняет статус тендера (PUT /tenders/{tenderId}/status).
+// Публикация и закрытие попадают в outbox и рассылаются подписчикам.
+func (s *Server) UpdateTenderStatus(ctx echo.Context, tenderId model.TenderId, params model.UpdateTenderStatusParams) error {
+	if err := checkEnum("status", params.Status, tenderStatuses, true); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := s.authorizeTender(ctx, params.Username, tenderId); err != nil {
+		return err
+	}
+
+	tender, err := s.storage.UpdateTenderStatus(ctx.Request().Context(), tenderId, params)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "UpdateTenderStatus error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update tender status")
+	}
+	return ctx.JSON(http.StatusOK, tender)
+}
+
+// authorizeTender проверяет, что тендер существует и пользователь отвечает за его организацию
+func (s *Server) authorizeTender(ctx echo.Context, username string, tenderId model.TenderId) error {
+	organizationId, err := s.storage.TenderOrganization(ctx.Request().Context(), tenderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tender not found")
+	} else if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "TenderOrganization error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check permissions")
+	}
+	return s.authorizeResponsible(ctx, username, organizationId)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-tenders/logging"
+	"go-tenders/model"
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// lifecycleStorage хранилище для проверок готовности и фонового журнала событий
+type lifecycleStorage struct {
+	storage.Storage
+}
+
+func (lifecycleStorage) Ping(context.Context) error { return nil }
+
+func (lifecycleStorage) MigrationVersion(context.Context) (int, bool, error) {
+	version, err := storage.LatestMigrationVersion()
+	return version, true, err
+}
+
+func (lifecycleStorage) LatestEventId(context.Context) (int64, error) { return 0, nil }
+
+func (lifecycleStorage) EventsAfter(context.Context, int64, int) ([]model.StreamEvent, error) {
+	return nil, nil
+}
+
+func newLifecycleServer(t *testing.T) *Server {
+	t.Helper()
+	logger, err := logging.New(io.Discard, "error", logging.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(lifecycleStorage{}, logger, nil)
+}
+
+func readyStatus(t *testing.T, h http.Handler) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	return rec.Code
+}
+
+// В начале остановки экземпляр сообщает о неготовности, чтобы балансировщик снял его
+func TestShutdownMarksNotReady(t *testing.T) {
+	s := newLifecycleServer(t)
+	h := s.Handler()
+	if code := readyStatus(t, h); code != http.StatusOK {
+		t.Fatalf("ready before shutdown = %d, want 200", code)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if code := readyStatus(t, h); code != http.StatusServiceUnavailable {
+		t.Fatalf("ready during shutdown = %d, want 503", code)
+	}
+}
+
+// Остановка дожидается текущего запроса и фоновых задач
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	s := newLifecycleServer(t)
+	s.Handler()
+	entered, release := make(chan struct{}), make(chan struct{})
+	s.echo.GET("/slow", func(ctx echo.Context) error {
+		close(entered)
+		<-release
+		return ctx.String(http.StatusOK, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.echo.Listener = ln
+	started := make(chan error, 1)
+	go func() { started <- s.Start("") }()
+
+	response := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			response <- 0
+			return
+		}
+		resp.Body.Close()
+		response <- resp.StatusCode
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Shutdown(ctx) }()
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown returned %v before the request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	if code := <-response; code != http.StatusOK {
+		t.Fatalf("in-flight request got %d, want 200", code)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("Shutdown = %v", err)
+	}
+	if err := <-started; !errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("Start = %v, want http.ErrServerClosed", err)
+	}
+}
+
+// Если запрос не успевает завершиться, остановка возвращает ошибку контекста
+func TestShutdownTimeout(t *testing.T) {
+	s := newLifecycleServer(t)
+	s.Handler()
+	entered, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	s.echo.GET("/slow", func(ctx echo.Context) error {
+		close(entered)
+		<-release
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.echo.Listener = ln
+	go s.Start("")
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String() + "/slow"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want context.DeadlineExceeded", err)
+	}
+}
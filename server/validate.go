@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"go-tenders/model"
+)
+
+// Ограничения длины полей из swagger.yaml
+const (
+	maxNameLength        = 100
+	maxDescriptionLength = 500
+	maxIdLength          = 100
+	maxUsernameLength    = 50
+)
+
+var (
+	tenderServiceTypes = []model.TenderServiceType{model.Construction, model.Delivery, model.Manufacture}
+	tenderStatuses     = []model.TenderStatus{model.Created, model.Published, model.Closed}
+	bidStatuses        = []model.BidStatus{model.BidStatusCreated, model.BidStatusPublished, model.BidStatusCanceled, model.BidStatusApproved, model.BidStatusRejected}
+	bidDecisions       = []model.BidDecision{model.BidDecisionApproved, model.BidDecisionRejected}
+)
+
+// validateTendersNewBody проверяет тело POST /tenders/new. Статус из тела
+// не сохраняется — новый тендер всегда создается в статусе Created.
+func validateTendersNewBody(body model.TendersNewBody) error {
+	return errors.Join(
+		checkString("name", body.Name, maxNameLength),
+		checkString("description", body.Description, maxDescriptionLength),
+		checkString("organizationId", body.OrganizationId, maxIdLength),
+		checkString("creatorUsername", body.CreatorUsername, maxUsernameLength),
+		checkEnum("serviceType", body.ServiceType, tenderServiceTypes, true),
+		checkEnum("status", body.Status, tenderStatuses, false),
+	)
+}
+
+// validateBidsNewBody проверяет тело POST /bids/new
+func validateBidsNewBody(body model.BidsNewBody) error {
+	errs := []error{
+		checkString("name", body.Name, maxNameLength),
+		checkString("description", body.Description, maxDescriptionLength),
+		checkString("tenderId", body.TenderId, maxIdLength),
+		checkString("creatorUsername", body.CreatorUsername, maxUsernameLength),
+		checkEnum("status", body.Status, bidStatuses, false),
+	}
+	// Без организации предложение подается от имени пользователя
+	if body.OrganizationId != "" {
+		errs = append(errs, checkString("organizationId", body.OrganizationId, maxIdLength))
+	}
+	return errors.Join(errs...)
+}
+
+// validateTenderIdEditBody проверяет тело PATCH /tenders/{tenderId}/edit; поля необязательны
+func validateTenderIdEditBody(body model.TenderIdEditBody) error {
+	var errs []error
+	if body.Name != nil {
+		errs = append(errs, checkString("name", *body.Name, maxNameLength))
+	}
+	if body.Description != nil {
+		errs = append(errs, checkString("description", *body.Description, maxDescriptionLength))
+	}
+	if body.ServiceType != nil {
+		errs = append(errs, checkEnum("serviceType", *body.ServiceType, tenderServiceTypes, true))
+	}
+	return errors.Join(errs...)
+}
+
+func checkString(field, value string, maxLength int) error {
+	switch {
+	case value == "":
+		return fmt.Errorf("%s is required", field)
+	case utf8.RuneCountInString(value) > maxLength:
+		return fmt.Errorf("%s must be at most %d characters", field, maxLength)
+	}
+	return nil
+}
+
+func checkEnum[T ~string](field string, value T, allowed []T, required bool) error {
+	if value == "" && !required {
+		return nil
+	}
+	if !slices.Contains(allowed, value) {
+		return fmt.Errorf("%s has invalid value %q", field, value)
+	}
+	return nil
+}
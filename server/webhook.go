@@ -0,0 +1,150 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+
+	"go-tenders/model"
+	"go-tenders/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+// authorizeOrganization проверяет, что пользователь из параметра username
+// ответственный за организацию
+func (s *Server) authorizeOrganization(ctx echo.Context, organizationId string) error {
+	return s.authorizeResponsible(ctx, ctx.QueryParam("username"), organizationId)
+}
+
+// authorizeResponsible проверяет, что пользователь username ответственный за организацию
+func (s *Server) authorizeResponsible(ctx echo.Context, username, organizationId string) error {
+	if username == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "username is required")
+	}
+	ok, err := s.storage.IsOrganizationResponsible(ctx.Request().Context(), username, organizationId)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "IsOrganizationResponsible error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check permissions")
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden, "User is not responsible for the organization")
+	}
+	return nil
+}
+
+// webhookForRequest загружает подписку из пути и проверяет права на нее
+func (s *Server) webhookForRequest(ctx echo.Context) (model.Webhook, error) {
+	wh, err := s.storage.GetWebhook(ctx.Request().Context(), ctx.Param("webhookId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return wh, echo.NewHTTPError(http.StatusNotFound, "Webhook not found")
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "GetWebhook error", "error", err)
+		return wh, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get webhook")
+	}
+	return wh, s.authorizeOrganization(ctx, wh.OrganizationId)
+}
+
+// CreateWebhook регистрирует вебхук организации (POST /webhooks).
+// Секрет подписи возвращается только в ответе на этот запрос.
+func (s *Server) CreateWebhook(ctx echo.Context) error {
+	var body model.WebhooksNewBody
+	if err := ctx.Bind(&body); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateWebhook bind error", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := webhook.ValidateURL(ctx.Request().Context(), body.Url); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, e := range body.Events {
+		if !slices.Contains(model.EventTypes, e) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown event type "+string(e))
+		}
+	}
+	if err := s.authorizeOrganization(ctx, body.OrganizationId); err != nil {
+		return err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateWebhook secret error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create webhook")
+	}
+	wh, err := s.storage.CreateWebhook(ctx.Request().Context(), model.Webhook{
+		OrganizationId: body.OrganizationId,
+		Url:            body.Url,
+		Events:         body.Events,
+		Secret:         secret,
+	}, ctx.QueryParam("username"))
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "CreateWebhook error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create webhook")
+	}
+	return ctx.JSON(http.StatusCreated, wh)
+}
+
+// ListWebhooks возвращает вебхуки организации (GET /webhooks)
+func (s *Server) ListWebhooks(ctx echo.Context) error {
+	organizationId := ctx.QueryParam("organizationId")
+	if organizationId == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "organizationId is required")
+	}
+	if err := s.authorizeOrganization(ctx, organizationId); err != nil {
+		return err
+	}
+
+	webhooks, err := s.storage.ListWebhooks(ctx.Request().Context(), organizationId)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "ListWebhooks error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list webhooks")
+	}
+	if webhooks == nil {
+		webhooks = []model.Webhook{}
+	}
+	return ctx.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook отключает вебхук (DELETE /webhooks/{webhookId})
+func (s *Server) DeleteWebhook(ctx echo.Context) error {
+	wh, err := s.webhookForRequest(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.DeleteWebhook(ctx.Request().Context(), wh.Id); err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "DeleteWebhook error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete webhook")
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries журнал доставок вебхука (GET /webhooks/{webhookId}/deliveries)
+func (s *Server) ListWebhookDeliveries(ctx echo.Context) error {
+	wh, err := s.webhookForRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	var page struct {
+		Limit  *int32 `query:"limit"`
+		Offset *int32 `query:"offset"`
+	}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &page); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid pagination parameters")
+	}
+	limit, offset, err := offsetPage(page.Limit, page.Offset)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := s.storage.ListWebhookDeliveries(ctx.Request().Context(), wh.Id, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx.Request().Context(), "ListWebhookDeliveries error", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list webhook deliveries")
+	}
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+	return ctx.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+)
+
+// auditChainLock ключ advisory-блокировки, упорядочивающей запись цепочки аудита
+const auditChainLock int64 = 0x61756469746c6f67
+
+// auditGenesisHash предыдущий хэш первой записи цепочки
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// AuditMeta данные запроса для журнала аудита, передаются через контекст
+type AuditMeta struct {
+	RequestId string
+	// Actor пользователь запроса; используется, если операция не знает автора сама
+	Actor string
+}
+
+type auditMetaKey struct{}
+
+// WithAuditMeta добавляет в контекст данные запроса для журнала аудита
+func WithAuditMeta(ctx context.Context, meta AuditMeta) context.Context {
+	return context.WithValue(ctx, auditMetaKey{}, meta)
+}
+
+func auditMetaFrom(ctx context.Context) AuditMeta {
+	meta, _ := ctx.Value(auditMetaKey{}).(AuditMeta)
+	return meta
+}
+
+// auditHash SHA-256 от хэша предыдущей записи и полей записи в фиксированном порядке
+func auditHash(prevHash string, e model.AuditEntry) (string, error) {
+	fields, err := json.Marshal([]interface{}{
+		e.Action, e.Actor, e.OrganizationId, e.EntityType, e.EntityId,
+		e.VersionBefore, e.VersionAfter, e.RequestId, e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(prevHash+"\n"), fields...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// entityState возвращает текущую версию сущности и организацию ее тендера
+func entityState(ctx context.Context, tx *sql.Tx, entityType model.AuditEntityType, entityId string) (*int32, string, error) {
+	query := `SELECT version, organization_id::text FROM tenders WHERE id::text = $1`
+	if entityType == model.AuditEntityBid {
+		query = `
+            SELECT b.version, t.organization_id::text
+            FROM bids b
+            JOIN tenders t ON t.id = b.tender_id
+            WHERE b.id::text = $1`
+	}
+	var version int32
+	var organizationId string
+	err := tx.QueryRowContext(ctx, query, entityId).Scan(&version, &organizationId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &version, organizationId, nil
+}
+
+// entityVersion версия сущности перед изменением, nil — сущности нет
+func entityVersion(ctx context.Context, tx *sql.Tx, entityType model.AuditEntityType, entityId string) (*int32, error) {
+	version, _, err := entityState(ctx, tx, entityType, entityId)
+	return version, err
+}
+
+// recordAudit добавляет запись в журнал аудита в транзакции изменения.
+// Версия после изменения и организация читаются из уже измененной сущности.
+func recordAudit(ctx context.Context, tx *sql.Tx, action model.AuditAction, entityType model.AuditEntityType, entityId, actor string, before *int32) error {
+	after, organizationId, err := entityState(ctx, tx, entityType, entityId)
+	if err != nil {
+		return err
+	}
+	meta := auditMetaFrom(ctx)
+	if actor == "" {
+		actor = meta.Actor
+	}
+	entry := model.AuditEntry{
+		Action:         action,
+		Actor:          actor,
+		OrganizationId: organizationId,
+		EntityType:     entityType,
+		EntityId:       entityId,
+		VersionBefore:  before,
+		VersionAfter:   after,
+		RequestId:      meta.RequestId,
+		// Postgres хранит микросекунды; округляем заранее, чтобы хэш сходился при проверке
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	// Блокировка до конца транзакции: записи цепочки добавляются строго по одной
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return err
+	}
+	entry.PrevHash = auditGenesisHash
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&entry.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if entry.Hash, err = auditHash(entry.PrevHash, entry); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO audit_log (action, actor, organization_id, entity_type, entity_id,
+            version_before, version_after, request_id, created_at, prev_hash, hash)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    `, entry.Action, entry.Actor, entry.OrganizationId, entry.EntityType, entry.EntityId,
+		entry.VersionBefore, entry.VersionAfter, entry.RequestId, entry.CreatedAt, entry.PrevHash, entry.Hash)
+	return err
+}
+
+const auditColumns = "id, action, actor, organization_id, entity_type, entity_id, version_before, version_after, request_id, created_at, prev_hash, hash"
+
+func scanAuditEntry(row interface{ Scan(...interface{}) error }) (model.AuditEntry, error) {
+	var e model.AuditEntry
+	var before, after sql.NullInt32
+	err := row.Scan(&e.Id, &e.Action, &e.Actor, &e.OrganizationId, &e.EntityType, &e.EntityId,
+		&before, &after, &e.RequestId, &e.CreatedAt, &e.PrevHash, &e.Hash)
+	if before.Valid {
+		e.VersionBefore = &before.Int32
+	}
+	if after.Valid {
+		e.VersionAfter = &after.Int32
+	}
+	return e, err
+}
+
+// GetAuditLog возвращает записи журнала аудита по фильтрам, новые первыми
+func (s *PostgresStorage) GetAuditLog(ctx context.Context, params model.AuditParams, limit, offset int) ([]model.AuditEntry, error) {
+	var args queryArgs
+	conds := []string{"TRUE"}
+	if params.Actor != nil {
+		conds = append(conds, "actor = "+args.add(*params.Actor))
+	}
+	if params.OrganizationId != nil {
+		conds = append(conds, "organization_id = "+args.add(*params.OrganizationId))
+	}
+	if params.EntityType != nil {
+		conds = append(conds, "entity_type = "+args.add(*params.EntityType))
+	}
+	if params.EntityId != nil {
+		conds = append(conds, "entity_id = "+args.add(*params.EntityId))
+	}
+	if params.Action != nil {
+		conds = append(conds, "action = "+args.add(*params.Action))
+	}
+	if params.From != nil {
+		conds = append(conds, "created_at >= "+args.add(*params.From))
+	}
+	if params.To != nil {
+		conds = append(conds, "created_at <= "+args.add(*params.To))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+auditColumns+`
+        FROM audit_log
+        WHERE `+strings.Join(conds, " AND ")+`
+        ORDER BY id DESC
+        LIMIT `+args.add(limit)+` OFFSET `+args.add(offset), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// auditChain проверяет записи цепочки в порядке id
+type auditChain struct {
+	prevHash string
+	result   model.AuditVerification
+}
+
+func newAuditChain() *auditChain {
+	return &auditChain{prevHash: auditGenesisHash, result: model.AuditVerification{Valid: true}}
+}
+
+// add проверяет очередную запись; false — цепочка нарушена на ней, result.BrokenAt указывает запись
+func (c *auditChain) add(e model.AuditEntry) (bool, error) {
+	c.result.Checked++
+	hash, err := auditHash(c.prevHash, e)
+	if err != nil {
+		return false, err
+	}
+	if e.PrevHash != c.prevHash || e.Hash != hash {
+		c.result.Valid = false
+		c.result.BrokenAt = &e.Id
+		return false, nil
+	}
+	c.prevHash = e.Hash
+	return true, nil
+}
+
+// VerifyAuditChain пересчитывает хэши всей цепочки и сообщает первую
+// поврежденную запись. Удаленная запись обнаруживается по несовпадению prev_hash.
+func (s *PostgresStorage) VerifyAuditChain(ctx context.Context) (model.AuditVerification, error) {
+	chain := newAuditChain()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+auditColumns+` FROM audit_log ORDER BY id`)
+	if err != nil {
+		return chain.result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return chain.result, err
+		}
+		if ok, err := chain.add(e); err != nil || !ok {
+			return chain.result, err
+		}
+	}
+	return chain.result, rows.Err()
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// auditTestChain строит корректную цепочку из n записей
+func auditTestChain(t *testing.T, n int) []model.AuditEntry {
+	t.Helper()
+	created := time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.UTC)
+	prevHash := auditGenesisHash
+	entries := make([]model.AuditEntry, n)
+	for i := range entries {
+		version := int32(i + 1)
+		e := model.AuditEntry{
+			Id:             int64(i + 1),
+			Action:         model.AuditTenderEdit,
+			Actor:          "user1",
+			OrganizationId: "1",
+			EntityType:     model.AuditEntityTender,
+			EntityId:       "tender-1",
+			VersionBefore:  &version,
+			VersionAfter:   &version,
+			RequestId:      "req",
+			CreatedAt:      created.Add(time.Duration(i) * time.Second),
+			PrevHash:       prevHash,
+		}
+		hash, err := auditHash(prevHash, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Hash = hash
+		prevHash = hash
+		entries[i] = e
+	}
+	return entries
+}
+
+func verifyEntries(t *testing.T, entries []model.AuditEntry) model.AuditVerification {
+	t.Helper()
+	chain := newAuditChain()
+	for _, e := range entries {
+		ok, err := chain.add(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+	}
+	return chain.result
+}
+
+func TestAuditChainVerification(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   func([]model.AuditEntry) []model.AuditEntry
+		brokenAt int64
+		checked  int64
+	}{
+		{
+			name:    "intact chain",
+			tamper:  func(e []model.AuditEntry) []model.AuditEntry { return e },
+			checked: 4,
+		},
+		{
+			name: "changed field",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				e[1].Actor = "intruder"
+				return e
+			},
+			brokenAt: 2,
+			checked:  2,
+		},
+		{
+			name: "changed field with recomputed hash",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				e[1].Actor = "intruder"
+				e[1].Hash, _ = auditHash(e[1].PrevHash, e[1])
+				return e
+			},
+			brokenAt: 3,
+			checked:  3,
+		},
+		{
+			name: "removed version",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				e[2].VersionBefore = nil
+				return e
+			},
+			brokenAt: 3,
+			checked:  3,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				return append(e[:1], e[2:]...)
+			},
+			brokenAt: 3,
+			checked:  2,
+		},
+		{
+			name: "deleted first entry",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				return e[1:]
+			},
+			brokenAt: 2,
+			checked:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verifyEntries(t, tt.tamper(auditTestChain(t, 4)))
+			if got.Checked != tt.checked {
+				t.Errorf("checked = %d, want %d", got.Checked, tt.checked)
+			}
+			if tt.brokenAt == 0 {
+				if !got.Valid || got.BrokenAt != nil {
+					t.Fatalf("result = %+v, want valid", got)
+				}
+				return
+			}
+			if got.Valid || got.BrokenAt == nil || *got.BrokenAt != tt.brokenAt {
+				t.Fatalf("result = %+v, want broken at %d", got, tt.brokenAt)
+			}
+		})
+	}
+}
+
+// Хэш не зависит от часового пояса, в котором база вернула created_at
+func TestAuditHashNormalizesTimeZone(t *testing.T) {
+	e := auditTestChain(t, 1)[0]
+	moscow := e
+	moscow.CreatedAt = e.CreatedAt.In(time.FixedZone("MSK", 3*60*60))
+	want, _ := auditHash(auditGenesisHash, e)
+	got, err := auditHash(auditGenesisHash, moscow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("hash differs across time zones: %s != %s", got, want)
+	}
+}
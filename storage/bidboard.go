@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+
+	"go-tenders/model"
+)
+
+// TenderOrganization возвращает организацию, которой принадлежит тендер
+func (s *PostgresStorage) TenderOrganization(ctx context.Context, tenderId string) (string, error) {
+	var organizationId string
+	err := s.db.QueryRowContext(ctx, `SELECT organization_id::text FROM tenders WHERE id::text = $1`, tenderId).Scan(&organizationId)
+	return organizationId, err
+}
+
+// TenderOrganizationStatus возвращает организацию и статус тендера
+func (s *PostgresStorage) TenderOrganizationStatus(ctx context.Context, tenderId string) (string, model.TenderStatus, error) {
+	var organizationId string
+	var status model.TenderStatus
+	err := s.db.QueryRowContext(ctx, `SELECT organization_id::text, status FROM tenders WHERE id::text = $1`, tenderId).
+		Scan(&organizationId, &status)
+	return organizationId, status, err
+}
+
+// BidTenderOrganization возвращает организацию тендера, на который подано предложение
+func (s *PostgresStorage) BidTenderOrganization(ctx context.Context, bidId string) (string, error) {
+	var organizationId string
+	err := s.db.QueryRowContext(ctx, `
+        SELECT t.organization_id::text
+        FROM bids b
+        JOIN tenders t ON t.id = b.tender_id
+        WHERE b.id::text = $1
+    `, bidId).Scan(&organizationId)
+	return organizationId, err
+}
+
+// BidRanking возвращает рейтинг видимых организации предложений тендера:
+// без отклонений выше, затем по числу согласований и времени подачи
+func (s *PostgresStorage) BidRanking(ctx context.Context, tenderId string) ([]model.BidRank, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT b.id, b.name, b.status,
+               COUNT(d.*) FILTER (WHERE d.decision = 'Approved') AS approvals,
+               COUNT(d.*) FILTER (WHERE d.decision = 'Rejected') AS rejections
+        FROM bids b
+        LEFT JOIN bid_decisions d ON d.bid_id = b.id
+        WHERE b.tender_id::text = $1 AND b.status IN ('Published', 'Approved', 'Rejected')
+        GROUP BY b.id, b.name, b.status, b.created_at
+        ORDER BY rejections > 0, approvals DESC, b.created_at, b.id
+    `, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranking []model.BidRank
+	for rows.Next() {
+		r := model.BidRank{Rank: len(ranking) + 1}
+		if err := rows.Scan(&r.BidId, &r.Name, &r.Status, &r.Approvals, &r.Rejections); err != nil {
+			return nil, err
+		}
+		ranking = append(ranking, r)
+	}
+	return ranking, rows.Err()
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor возвращается, если токен курсора поврежден или подделан
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor позиция в списке, отсортированном по (created_at, id) по убыванию
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	Id        string    `json:"i"`
+	// Backward означает переход на предыдущую страницу
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode возвращает непрозрачный токен курсора для передачи клиенту
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor разбирает токен, полученный от клиента
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Id == "" || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Page параметры запроса страницы при keyset-пагинации
+type Page struct {
+	// Cursor позиция, от которой читается страница; nil — первая страница
+	Cursor *Cursor
+	Limit  int
+	// Username пользователь, для которого действует правило видимости строк
+	Username string
+}
+
+// PageInfo курсоры соседних страниц; пустая строка — страницы нет
+type PageInfo struct {
+	Next string
+	Prev string
+}
+
+// keyset добавляет к запросу условие по курсору и возвращает его вместе с порядком сортировки.
+// Для движения назад сортировка обратная, страница разворачивается в finishPage.
+func keyset(alias string, args *queryArgs, page Page) (cond string, order string) {
+	if page.Cursor == nil {
+		return "TRUE", alias + ".created_at DESC, " + alias + ".id DESC"
+	}
+	op, dir := "<", "DESC"
+	if page.Cursor.Backward {
+		op, dir = ">", "ASC"
+	}
+	cond = "(" + alias + ".created_at, " + alias + ".id) " + op +
+		" (" + args.add(page.Cursor.CreatedAt) + ", " + args.add(page.Cursor.Id) + ")"
+	return cond, alias + ".created_at " + dir + ", " + alias + ".id " + dir
+}
+
+// finishPage обрезает лишнюю строку (запрос читает limit+1), восстанавливает порядок
+// для движения назад и вычисляет курсоры соседних страниц
+func finishPage[T any](items []T, keys []Cursor, page Page) ([]T, PageInfo) {
+	hasMore := len(items) > page.Limit
+	if hasMore {
+		items, keys = items[:page.Limit], keys[:page.Limit]
+	}
+	backward := page.Cursor != nil && page.Cursor.Backward
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	var info PageInfo
+	if len(items) == 0 {
+		return items, info
+	}
+	first, last := keys[0], keys[len(keys)-1]
+	if (!backward && hasMore) || backward {
+		info.Next = Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+	if (backward && hasMore) || (!backward && page.Cursor != nil) {
+		info.Prev = Cursor{CreatedAt: first.CreatedAt, Id: first.Id, Backward: true}.Encode()
+	}
+	return items, info
+}
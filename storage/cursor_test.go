@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"forward", Cursor{CreatedAt: createdAt, Id: "7f1c1a6e-0000-4000-8000-000000000001"}},
+		{"backward", Cursor{CreatedAt: createdAt, Id: "7f1c1a6e-0000-4000-8000-000000000002", Backward: true}},
+		{"non-utc", Cursor{CreatedAt: createdAt.In(time.FixedZone("MSK", 3*60*60)), Id: "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.cursor.Encode()
+			if strings.ContainsAny(token, "+/=") {
+				t.Fatalf("token %q is not URL-safe", token)
+			}
+			got, err := DecodeCursor(token)
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.Id != tt.cursor.Id || got.Backward != tt.cursor.Backward {
+				t.Fatalf("got %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	valid := Cursor{CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Id: "b1"}.Encode()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"c":"2024-05-01T00:00:00Z","i":"b1"}`))},
+		{"truncated", valid[:len(valid)-3]},
+		{"not json", encode("cursor")},
+		{"missing id", encode(`{"c":"2024-05-01T00:00:00Z"}`)},
+		{"missing created_at", encode(`{"i":"b1"}`)},
+		{"bad created_at", encode(`{"c":"yesterday","i":"b1"}`)},
+		{"wrong id type", encode(`{"c":"2024-05-01T00:00:00Z","i":1}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := DecodeCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeCursor(%q) = %+v, %v; want ErrInvalidCursor", tt.token, c, err)
+			}
+		})
+	}
+}
+
+func TestFinishPage(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	key := func(id string) Cursor {
+		return Cursor{CreatedAt: base.Add(time.Duration(id[0]) * time.Minute), Id: id}
+	}
+	// rows строит результат запроса: ключи в порядке выдачи базой
+	rows := func(ids ...string) ([]string, []Cursor) {
+		keys := make([]Cursor, len(ids))
+		for i, id := range ids {
+			keys[i] = key(id)
+		}
+		return append([]string(nil), ids...), keys
+	}
+	next := func(id string) string { return Cursor{CreatedAt: key(id).CreatedAt, Id: id}.Encode() }
+	prev := func(id string) string { return Cursor{CreatedAt: key(id).CreatedAt, Id: id, Backward: true}.Encode() }
+	after := &Cursor{CreatedAt: base, Id: "x"}
+	before := &Cursor{CreatedAt: base, Id: "x", Backward: true}
+
+	tests := []struct {
+		name string
+		ids  []string
+		page Page
+		want []string
+		next string
+		prev string
+	}{
+		{"empty first page", nil, Page{Limit: 3}, []string{}, "", ""},
+		{"first page shorter than limit", []string{"c", "b"}, Page{Limit: 3}, []string{"c", "b"}, "", ""},
+		{"first page exactly limit", []string{"c", "b", "a"}, Page{Limit: 3}, []string{"c", "b", "a"}, "", ""},
+		{"first page limit+1", []string{"d", "c", "b", "a"}, Page{Limit: 3}, []string{"d", "c", "b"}, next("b"), ""},
+		{"middle page forward", []string{"d", "c", "b", "a"}, Page{Cursor: after, Limit: 3}, []string{"d", "c", "b"}, next("b"), prev("d")},
+		{"last page forward", []string{"b", "a"}, Page{Cursor: after, Limit: 3}, []string{"b", "a"}, "", prev("b")},
+		{"backward limit+1", []string{"a", "b", "c", "d"}, Page{Cursor: before, Limit: 3}, []string{"c", "b", "a"}, next("a"), prev("c")},
+		{"backward to first page", []string{"a", "b"}, Page{Cursor: before, Limit: 3}, []string{"b", "a"}, next("a"), ""},
+		{"zero limit", []string{"a"}, Page{Limit: 0}, []string{}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, keys := rows(tt.ids...)
+			got, info := finishPage(items, keys, tt.page)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("items = %v, want %v", got, tt.want)
+			}
+			if info.Next != tt.next {
+				t.Errorf("next = %q, want %q", info.Next, tt.next)
+			}
+			if info.Prev != tt.prev {
+				t.Errorf("prev = %q, want %q", info.Prev, tt.prev)
+			}
+		})
+	}
+}
+
+// Страница по курсору видит те же предложения, что и страница по смещению:
+// правило видимости применяется независимо от позиции курсора
+func TestBidsForTenderPageQueryAppliesVisibility(t *testing.T) {
+	after := &Cursor{CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Id: "x"}
+	for _, page := range []Page{{Limit: 5, Username: "user1"}, {Cursor: after, Limit: 5, Username: "user1"}} {
+		query, args := bidsForTenderPageQuery("tender-1", page)
+		if args[0] != "tender-1" || args[1] != "user1" {
+			t.Fatalf("args = %v; want tender id and username first", args)
+		}
+		if !strings.Contains(query, "b.creator_username = $2") || strings.Count(query, "FROM organization_responsible r") != 2 {
+			t.Fatalf("visibility rule missing: %s", query)
+		}
+		if n := len(placeholderRe.FindAllString(query, -1)); n < len(args) {
+			t.Fatalf("query uses %d placeholders for %d args", n, len(args))
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+
+	"go-tenders/model"
+)
+
+func TestDecisionOutcome(t *testing.T) {
+	tests := []struct {
+		name                               string
+		approvals, rejections, responsible int
+		want                               model.BidStatus
+	}{
+		{"single approval of many", 1, 0, 5, model.BidStatusPublished},
+		{"quorum of three", 3, 0, 5, model.BidStatusApproved},
+		{"two responsibles need two", 1, 0, 2, model.BidStatusPublished},
+		{"all of two responsibles", 2, 0, 2, model.BidStatusApproved},
+		{"sole responsible", 1, 0, 1, model.BidStatusApproved},
+		{"any rejection wins", 3, 1, 5, model.BidStatusRejected},
+		{"no responsibles", 1, 0, 0, model.BidStatusPublished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decisionOutcome(tt.approvals, tt.rejections, tt.responsible); got != tt.want {
+				t.Fatalf("decisionOutcome = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
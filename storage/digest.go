@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+)
+
+const digestSubscriptionColumns = "id, username, service_types, keywords, channel, target, locale, created_at, last_sent_at"
+
+// scanDigestSubscription читает подписку; extra — колонки, выбранные после digestSubscriptionColumns
+func scanDigestSubscription(row interface{ Scan(...interface{}) error }, extra ...interface{}) (model.DigestSubscription, error) {
+	var d model.DigestSubscription
+	var serviceTypes, keywords []byte
+	var createdAt time.Time
+	dest := []interface{}{&d.Id, &d.Username, &serviceTypes, &keywords, &d.Channel, &d.Target, &d.Locale, &createdAt, &d.LastSentAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return d, err
+	}
+	d.CreatedAt = createdAt.Format(time.RFC3339)
+	if err := json.Unmarshal(serviceTypes, &d.ServiceTypes); err != nil {
+		return d, err
+	}
+	return d, json.Unmarshal(keywords, &d.Keywords)
+}
+
+// CreateDigestSubscription сохраняет подписку на сводку; секрет генерирует вызывающая сторона.
+// Первая сводка включит тендеры, опубликованные после создания подписки.
+func (s *PostgresStorage) CreateDigestSubscription(ctx context.Context, sub model.DigestSubscription) (model.DigestSubscription, error) {
+	if sub.ServiceTypes == nil {
+		sub.ServiceTypes = []model.TenderServiceType{}
+	}
+	if sub.Keywords == nil {
+		sub.Keywords = []string{}
+	}
+	serviceTypes, err := json.Marshal(sub.ServiceTypes)
+	if err != nil {
+		return sub, err
+	}
+	keywords, err := json.Marshal(sub.Keywords)
+	if err != nil {
+		return sub, err
+	}
+	created, err := scanDigestSubscription(s.db.QueryRowContext(ctx, `
+        INSERT INTO digest_subscriptions (username, service_types, keywords, channel, target, secret, locale)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING `+digestSubscriptionColumns,
+		sub.Username, serviceTypes, keywords, sub.Channel, sub.Target, sub.Secret, sub.Locale))
+	created.Secret = sub.Secret
+	return created, err
+}
+
+// queryDigestSubscriptions читает подписки; forDelivery — запрос выбирает еще
+// secret и attempts, нужные задаче рассылки
+func (s *PostgresStorage) queryDigestSubscriptions(ctx context.Context, forDelivery bool, query string, args ...interface{}) ([]model.DigestSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.DigestSubscription
+	for rows.Next() {
+		var extra []interface{}
+		var secret string
+		var attempts int
+		if forDelivery {
+			extra = append(extra, &secret, &attempts)
+		}
+		d, err := scanDigestSubscription(rows, extra...)
+		d.Secret, d.Attempts = secret, attempts
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, d)
+	}
+	return subs, rows.Err()
+}
+
+// ListDigestSubscriptions возвращает подписки пользователя без секретов
+func (s *PostgresStorage) ListDigestSubscriptions(ctx context.Context, username string) ([]model.DigestSubscription, error) {
+	return s.queryDigestSubscriptions(ctx, false, `
+        SELECT `+digestSubscriptionColumns+`
+        FROM digest_subscriptions
+        WHERE username = $1 AND deleted_at IS NULL
+        ORDER BY created_at, id
+    `, username)
+}
+
+// DeleteDigestSubscription отключает подписку пользователя; sql.ErrNoRows, если ее нет
+func (s *PostgresStorage) DeleteDigestSubscription(ctx context.Context, subscriptionId string, username string) error {
+	var id string
+	return s.db.QueryRowContext(ctx, `
+        UPDATE digest_subscriptions SET deleted_at = NOW()
+        WHERE id::text = $1 AND username = $2 AND deleted_at IS NULL
+        RETURNING id
+    `, subscriptionId, username).Scan(&id)
+}
+
+// DueDigestSubscriptions возвращает подписки с секретами, предыдущая сводка
+// которых была не позже cutoff; подписки, ожидающие повтора, пропускаются до next_attempt_at
+func (s *PostgresStorage) DueDigestSubscriptions(ctx context.Context, now, cutoff time.Time, limit int) ([]model.DigestSubscription, error) {
+	return s.queryDigestSubscriptions(ctx, true, `
+        SELECT `+digestSubscriptionColumns+`, secret, attempts
+        FROM digest_subscriptions
+        WHERE deleted_at IS NULL AND last_sent_at <= $1
+          AND (next_attempt_at IS NULL OR next_attempt_at <= $2)
+        ORDER BY last_sent_at, id
+        LIMIT $3
+    `, cutoff, now, limit)
+}
+
+// PublishedTenders возвращает тендеры, опубликованные в периоде (from, to] и
+// по-прежнему открытые. Момент публикации берется из outbox (событие tender.published).
+func (s *PostgresStorage) PublishedTenders(ctx context.Context, from, to time.Time, serviceTypes []model.TenderServiceType, keywords []string) ([]model.Tender, error) {
+	var args queryArgs
+	conds := []string{
+		"o.event_type = '" + string(model.EventTenderPublished) + "'",
+		"o.created_at > " + args.add(from),
+		"o.created_at <= " + args.add(to),
+		"t.status = '" + string(model.Published) + "'",
+	}
+	if len(serviceTypes) > 0 {
+		placeholders := make([]string, len(serviceTypes))
+		for i, st := range serviceTypes {
+			placeholders[i] = args.add(st)
+		}
+		conds = append(conds, "t.service_type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if len(keywords) > 0 {
+		matches := make([]string, len(keywords))
+		for i, kw := range keywords {
+			p := args.add(kw)
+			matches[i] = "t.search_vector @@ (plainto_tsquery('russian', " + p + ") || plainto_tsquery('english', " + p + "))"
+		}
+		conds = append(conds, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT DISTINCT ON (t.id) `+tenderColumns+`
+        FROM outbox o
+        JOIN tenders t ON t.id::text = o.aggregate_id
+        WHERE `+strings.Join(conds, " AND ")+`
+        ORDER BY t.id, o.created_at
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenders []model.Tender
+	for rows.Next() {
+		t, _, err := scanTender(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, t)
+	}
+	return tenders, rows.Err()
+}
+
+// MarkDigestSent сдвигает границу сводки подписки на sentUntil и сбрасывает счетчик повторов
+func (s *PostgresStorage) MarkDigestSent(ctx context.Context, subscriptionId string, sentUntil time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE digest_subscriptions
+        SET last_sent_at = $2, attempts = 0, next_attempt_at = NULL
+        WHERE id::text = $1
+    `, subscriptionId, sentUntil)
+	return err
+}
+
+// RecordDigestFailure откладывает следующую попытку сводки подписки до retryAt
+func (s *PostgresStorage) RecordDigestFailure(ctx context.Context, subscriptionId string, retryAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE digest_subscriptions
+        SET attempts = attempts + 1, next_attempt_at = $2
+        WHERE id::text = $1
+    `, subscriptionId, retryAt)
+	return err
+}
+
+// digestLockId ключ advisory-блокировки задачи сводок: рассылает один экземпляр
+const digestLockId = 4_373_202
+
+// WithDigestLock выполняет fn, если удалось взять блокировку задачи сводок;
+// ok ложно, если проход уже выполняет другой экземпляр
+func (s *PostgresStorage) WithDigestLock(ctx context.Context, fn func(ctx context.Context) error) (ok bool, err error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, digestLockId).Scan(&ok); err != nil || !ok {
+		return false, err
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, digestLockId)
+	return true, fn(ctx)
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+
+	"go-tenders/model"
+)
+
+// queryEach выполняет запрос и передает строки в fn по одной, не накапливая
+// результат; ошибка fn прерывает чтение
+func queryEach[T any](ctx context.Context, s *PostgresStorage, query string, args queryArgs,
+	scan func(rowScanner) (T, error), fn func(T) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportTenders передает в fn все тендеры GET /tenders без пагинации: видимые
+// username, с сортировкой и фильтрами из ListQuery
+func (s *PostgresStorage) ExportTenders(ctx context.Context, q ListQuery, username string, fn func(model.Tender) error) error {
+	query, args := tendersQuery(q, username)
+	return queryEach(ctx, s, query, args, scanTenderRow, fn)
+}
+
+// ExportUserTenders передает в fn все тендеры GET /tenders/my без пагинации
+func (s *PostgresStorage) ExportUserTenders(ctx context.Context, username string, fn func(model.Tender) error) error {
+	query, args := userTendersQuery(username)
+	return queryEach(ctx, s, query, args, scanTenderRow, fn)
+}
+
+// ExportBidsForTender передает в fn все предложения GET /bids/{tenderId}/list
+// без пагинации: видимые username, с сортировкой и фильтрами из ListQuery
+func (s *PostgresStorage) ExportBidsForTender(ctx context.Context, tenderId string, q ListQuery, username string, fn func(model.Bid) error) error {
+	query, args := bidsForTenderQuery(tenderId, q, username)
+	return queryEach(ctx, s, query, args, scanBidRow, fn)
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// Выгрузка выполняет тот же запрос, что и JSON-список, только без LIMIT и
+// OFFSET: фильтры, видимость и порядок строк совпадают
+func TestExportMatchesListQuery(t *testing.T) {
+	values, _ := url.ParseQuery("status=Published&sort=name&order=desc")
+	tenderQuery, err := TenderListSchema.Parse(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bidQuery, err := BidListSchema.Parse(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		list   func(s *PostgresStorage) error
+		export func(s *PostgresStorage) error
+	}{
+		{
+			"tenders",
+			func(s *PostgresStorage) error {
+				_, err := s.QueryTenders(context.Background(), tenderQuery, "user1", 5, 10)
+				return err
+			},
+			func(s *PostgresStorage) error {
+				return s.ExportTenders(context.Background(), tenderQuery, "user1", func(model.Tender) error { return nil })
+			},
+		},
+		{
+			"user tenders",
+			func(s *PostgresStorage) error {
+				_, err := s.GetUserTenders(context.Background(), "user1", 5, 10)
+				return err
+			},
+			func(s *PostgresStorage) error {
+				return s.ExportUserTenders(context.Background(), "user1", func(model.Tender) error { return nil })
+			},
+		},
+		{
+			"bids for tender",
+			func(s *PostgresStorage) error {
+				_, err := s.QueryBidsForTender(context.Background(), "tender-1", bidQuery, "user1", 5, 10)
+				return err
+			},
+			func(s *PostgresStorage) error {
+				return s.ExportBidsForTender(context.Background(), "tender-1", bidQuery, "user1", func(model.Bid) error { return nil })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newFakeStorage(t)
+			if err := tt.list(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.export(s); err != nil {
+				t.Fatal(err)
+			}
+			queries := db.executed("SELECT")
+			if len(queries) != 2 {
+				t.Fatalf("executed %d queries, want 2", len(queries))
+			}
+			list, export := queries[0], queries[1]
+
+			limit := strings.LastIndex(list.SQL, "LIMIT")
+			if limit < 0 || strings.TrimSpace(list.SQL[:limit]) != strings.TrimSpace(export.SQL) {
+				t.Fatalf("export query differs from the list:\n%s\n---\n%s", list.SQL, export.SQL)
+			}
+			n := len(list.Args)
+			if !reflect.DeepEqual(list.Args[:n-2], export.Args) || list.Args[n-2] != 5 || list.Args[n-1] != 10 {
+				t.Fatalf("list args %v, export args %v", list.Args, export.Args)
+			}
+		})
+	}
+}
+
+// Тендеры пользователя и в списке, и в выгрузке идут по алфавиту
+func TestUserTendersQueryOrder(t *testing.T) {
+	query, args := userTendersQuery("user1")
+	if !strings.HasSuffix(strings.TrimSpace(query), "ORDER BY t.name, t.id") || len(args) != 1 || args[0] != "user1" {
+		t.Fatalf("query = %s, args = %v", query, args)
+	}
+}
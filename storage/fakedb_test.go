@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeDB драйвер database/sql для тестов без PostgreSQL: запоминает выполненные
+// запросы и отвечает на них по первому подходящему правилу
+type fakeDB struct {
+	mu      sync.Mutex
+	rules   []fakeRule
+	queries []fakeQuery
+}
+
+// fakeQuery выполненный запрос; BEGIN, COMMIT и ROLLBACK тоже записываются
+type fakeQuery struct {
+	SQL  string
+	Args []driver.Value
+}
+
+// fakeRule ответ на запросы, содержащие фрагмент match
+type fakeRule struct {
+	match    string
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+	// fn вычисляет ответ по аргументам; если задана, заменяет rows и err
+	fn func(args []driver.Value) ([][]driver.Value, error)
+}
+
+// newFakeStorage создает хранилище поверх fakeDB
+func newFakeStorage(t *testing.T) (*PostgresStorage, *fakeDB) {
+	t.Helper()
+	db := &fakeDB{}
+	conn := sql.OpenDB(db)
+	t.Cleanup(func() { conn.Close() })
+	return NewPostgresStorage(sqlx.NewDb(conn, "postgres")), db
+}
+
+// on добавляет правило; правила проверяются в порядке добавления
+func (db *fakeDB) on(match string, rule fakeRule) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	rule.match = match
+	db.rules = append(db.rules, rule)
+}
+
+// executed возвращает запросы, содержащие фрагмент match
+func (db *fakeDB) executed(match string) []fakeQuery {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var found []fakeQuery
+	for _, q := range db.queries {
+		if strings.Contains(q.SQL, match) {
+			found = append(found, q)
+		}
+	}
+	return found
+}
+
+// reset забывает выполненные запросы; правила сохраняются
+func (db *fakeDB) reset() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = nil
+}
+
+func (db *fakeDB) record(query string, args []driver.NamedValue) (fakeRule, []driver.Value) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	db.queries = append(db.queries, fakeQuery{SQL: query, Args: values})
+	for _, r := range db.rules {
+		if strings.Contains(query, r.match) {
+			return r, values
+		}
+	}
+	return fakeRule{}, values
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, driver.ErrSkip }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN", nil)
+	return fakeTx{c.db}, nil
+}
+
+// CheckNamedValue принимает аргументы любых типов без преобразования
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rule, values := c.db.record(query, args)
+	rows, err := rule.rows, rule.err
+	if rule.fn != nil {
+		rows, err = rule.fn(values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: rule.columns, rows: rows}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	rule, values := c.db.record(query, args)
+	err := rule.err
+	if rule.fn != nil {
+		_, err = rule.fn(values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(rule.affected), nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx fakeTx) Commit() error {
+	tx.db.record("COMMIT", nil)
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.db.record("ROLLBACK", nil)
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if r.columns == nil && len(r.rows) > 0 {
+		return make([]string, len(r.rows[0]))
+	}
+	return r.columns
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
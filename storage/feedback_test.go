@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// Отзыв записывается в bid_reviews — туда, откуда его читает GetBidReviews
+func TestSubmitBidFeedbackStoresReview(t *testing.T) {
+	s, db := newFakeStorage(t)
+	db.on("JOIN tenders t ON t.id = b.tender_id", fakeRule{rows: [][]driver.Value{{int64(2), "org-1"}}})
+	db.on("SELECT tender_id FROM bids", fakeRule{rows: [][]driver.Value{{"tender-1"}}})
+
+	params := model.SubmitBidFeedbackParams{BidFeedback: "Хорошее предложение", Username: "owner"}
+	if err := s.SubmitBidFeedback(context.Background(), "bid-1", params); err != nil {
+		t.Fatal(err)
+	}
+	reviews := db.executed("INSERT INTO bid_reviews")
+	if len(reviews) != 1 {
+		t.Fatalf("got %d review inserts, want 1", len(reviews))
+	}
+	if args := reviews[0].Args; args[0] != "bid-1" || args[1] != "owner" || args[2] != "Хорошее предложение" {
+		t.Fatalf("review args = %v; want bid id, author and feedback text", args)
+	}
+	var event model.BidFeedbackEvent
+	eventPayload(t, db, model.EventBidFeedbackSubmitted, &event)
+	if event.TenderId != "tender-1" || event.BidFeedback != params.BidFeedback {
+		t.Fatalf("event = %+v", event)
+	}
+}
+
+func TestSubmitBidFeedbackUnknownBid(t *testing.T) {
+	s, db := newFakeStorage(t)
+	err := s.SubmitBidFeedback(context.Background(), "missing", model.SubmitBidFeedbackParams{BidFeedback: "x", Username: "owner"})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SubmitBidFeedback = %v, want sql.ErrNoRows", err)
+	}
+	if len(db.executed("INSERT INTO bid_reviews")) != 0 {
+		t.Fatal("review stored for unknown bid")
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-tenders/model"
+)
+
+// BeginIdempotentRequest резервирует ключ за запросом. Если ключ свободен,
+// истек или брошен выполнявшим его запросом (lock истек), возвращается новая
+// запись и acquired=true. Иначе возвращается существующая запись.
+func (s *PostgresStorage) BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (model.IdempotencyRecord, bool, error) {
+	now := time.Now()
+	rec := model.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	var created string
+	err := s.db.QueryRowContext(ctx, `
+        INSERT INTO idempotency_keys (scope, key, request_hash, created_at, locked_until, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (scope, key) DO UPDATE
+            SET request_hash = EXCLUDED.request_hash,
+                status_code = NULL,
+                content_type = NULL,
+                response_body = NULL,
+                created_at = EXCLUDED.created_at,
+                locked_until = EXCLUDED.locked_until,
+                expires_at = EXCLUDED.expires_at
+            WHERE idempotency_keys.expires_at <= $4
+               OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= $4)
+        RETURNING key
+    `, scope, key, requestHash, now, now.Add(lock), rec.ExpiresAt).Scan(&created)
+	if err == nil {
+		return rec, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return rec, false, err
+	}
+
+	var status sql.NullInt64
+	var contentType sql.NullString
+	err = s.db.QueryRowContext(ctx, `
+        SELECT request_hash, status_code, content_type, response_body, created_at, expires_at
+        FROM idempotency_keys
+        WHERE scope = $1 AND key = $2
+    `, scope, key).Scan(&rec.RequestHash, &status, &contentType, &rec.Body, &rec.CreatedAt, &rec.ExpiresAt)
+	rec.StatusCode = int(status.Int64)
+	rec.ContentType = contentType.String
+	return rec, false, err
+}
+
+// CompleteIdempotentRequest сохраняет ответ на запрос с ключом
+func (s *PostgresStorage) CompleteIdempotentRequest(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = $3, content_type = $4, response_body = $5
+        WHERE scope = $1 AND key = $2
+    `, scope, key, statusCode, contentType, body)
+	return err
+}
+
+// ReleaseIdempotentRequest освобождает ключ запроса, завершившегося ошибкой,
+// чтобы клиент мог повторить его с тем же ключом
+func (s *PostgresStorage) ReleaseIdempotentRequest(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+        DELETE FROM idempotency_keys
+        WHERE scope = $1 AND key = $2 AND status_code IS NULL
+    `, scope, key)
+	return err
+}
+
+// PurgeExpiredIdempotencyKeys удаляет истекшие ключи и возвращает их число
+func (s *PostgresStorage) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-tenders/model"
+)
+
+// errImportRollback откатывает транзакцию импорта без ошибки для вызывающего
+var errImportRollback = errors.New("import rolled back")
+
+// TenderImportResult результат вставки строки импорта: тендер или ошибка строки
+type TenderImportResult struct {
+	Tender model.Tender
+	Err    error
+}
+
+// ImportTenders создает тендеры в одной транзакции. Каждая строка вставляется в
+// своей точке сохранения, поэтому ошибка строки попадает в ее результат и не
+// мешает сохранить остальные. Транзакция фиксируется с успешно вставленными
+// строками; с allOrNothing — только если вставлены все. rollback откатывает ее
+// в любом случае (dryRun). committed сообщает, сохранены ли вставленные строки.
+func (s *PostgresStorage) ImportTenders(ctx context.Context, bodies []model.TendersNewBody, allOrNothing, rollback bool) ([]TenderImportResult, bool, error) {
+	results := make([]TenderImportResult, len(bodies))
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		failed := false
+		for i, body := range bodies {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+				return err
+			}
+			tender, err := createTender(ctx, tx, body)
+			if err != nil {
+				if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); rbErr != nil {
+					return rbErr
+				}
+				results[i].Err = err
+				failed = true
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_row`); err != nil {
+				return err
+			}
+			results[i].Tender = tender
+		}
+		if rollback || (failed && allOrNothing) {
+			return errImportRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errImportRollback) {
+		return results, false, nil
+	}
+	return results, err == nil, err
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// newImportStorage fakeDB, в котором вставка тендера с именем "bad" падает
+func newImportStorage(t *testing.T) (*PostgresStorage, *fakeDB) {
+	s, db := newFakeStorage(t)
+	db.on("INSERT INTO tenders", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		if args[1] == "bad" {
+			return nil, errors.New("value too long")
+		}
+		return [][]driver.Value{{args[0], args[1], "", "Delivery", "Created", "org-1", int64(1), rollbackCreatedAt}}, nil
+	}})
+	return s, db
+}
+
+func importBodies(names ...string) []model.TendersNewBody {
+	bodies := make([]model.TendersNewBody, len(names))
+	for i, name := range names {
+		bodies[i] = model.TendersNewBody{Name: name, ServiceType: model.Delivery, OrganizationId: "org-1", CreatorUsername: "alice"}
+	}
+	return bodies
+}
+
+// Ошибочная строка откатывается до своей точки сохранения; остальные строки
+// пакета фиксируются, а с allOrNothing или rollback транзакция откатывается
+func TestImportTendersPartialFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		allOrNothing  bool
+		rollback      bool
+		wantCommitted bool
+	}{
+		{"batch commits valid rows", false, false, true},
+		{"single transaction", true, false, false},
+		{"dry run", false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newImportStorage(t)
+			results, committed, err := s.ImportTenders(context.Background(), importBodies("first", "bad", "third"), tt.allOrNothing, tt.rollback)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if committed != tt.wantCommitted {
+				t.Fatalf("committed = %v, want %v", committed, tt.wantCommitted)
+			}
+			if results[0].Err != nil || results[0].Tender.Name != "first" || results[2].Err != nil || results[2].Tender.Name != "third" {
+				t.Fatalf("valid rows: %+v", results)
+			}
+			if results[1].Err == nil {
+				t.Fatal("failed row has no error")
+			}
+			if n := len(db.executed("ROLLBACK TO SAVEPOINT import_row")); n != 1 {
+				t.Fatalf("rolled back to savepoint %d times, want 1", n)
+			}
+			if n := len(db.executed("RELEASE SAVEPOINT import_row")); n != 2 {
+				t.Fatalf("released %d savepoints, want 2", n)
+			}
+			// ROLLBACK TO SAVEPOINT тоже содержит ROLLBACK
+			commits, rollbacks := len(db.executed("COMMIT")), len(db.executed("ROLLBACK"))-1
+			if tt.wantCommitted != (commits == 1 && rollbacks == 0) {
+				t.Fatalf("%d commits, %d rollbacks", commits, rollbacks)
+			}
+		})
+	}
+}
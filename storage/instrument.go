@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"runtime"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Defines values for QueryKind.
+const (
+	QueryKindQuery    QueryKind = "query"
+	QueryKindExec     QueryKind = "exec"
+	QueryKindBegin    QueryKind = "begin"
+	QueryKindCommit   QueryKind = "commit"
+	QueryKindRollback QueryKind = "rollback"
+)
+
+// QueryKind вид обращения к базе
+type QueryKind string
+
+// QueryInfo описание обращения к базе для хуков
+type QueryInfo struct {
+	// Operation метод PostgresStorage, выполняющий обращение, например "CreateBid";
+	// "unknown", если обращение сделано в обход PostgresStorage
+	Operation string
+	Kind      QueryKind
+	SQL       string
+}
+
+// QueryHook наблюдает за обращениями к базе. BeforeQuery вызывается перед обращением
+// и может дополнить контекст; возвращенная функция вызывается по его завершении.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, q QueryInfo) (context.Context, func(err error))
+}
+
+// OpenInstrumented открывает пул соединений через зарегистрированный драйвер
+// driverName, вызывая hooks для каждого запроса и транзакции
+func OpenInstrumented(driverName, dsn string, hooks ...QueryHook) (*sqlx.DB, error) {
+	probe, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	var connector driver.Connector
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: drv}
+	}
+
+	db := sql.OpenDB(&instrumentedConnector{Connector: connector, hooks: hooks})
+	return sqlx.NewDb(db, driverName), nil
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type instrumentedConnector struct {
+	driver.Connector
+	hooks []QueryHook
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, hooks: c.hooks}, nil
+}
+
+// observe вызывает хуки перед обращением и возвращает функцию завершения
+func observe(ctx context.Context, hooks []QueryHook, kind QueryKind, query string) (context.Context, func(error)) {
+	if len(hooks) == 0 {
+		return ctx, func(error) {}
+	}
+	info := QueryInfo{Operation: callerOperation(), Kind: kind, SQL: query}
+	done := make([]func(error), 0, len(hooks))
+	for _, h := range hooks {
+		var finish func(error)
+		ctx, finish = h.BeforeQuery(ctx, info)
+		done = append(done, finish)
+	}
+	return ctx, func(err error) {
+		// driver.ErrSkip означает переход database/sql на другой путь выполнения, а не ошибку запроса
+		if errors.Is(err, driver.ErrSkip) {
+			err = nil
+		}
+		for i := len(done) - 1; i >= 0; i-- {
+			done[i](err)
+		}
+	}
+}
+
+const storageMethodPrefix = "go-tenders/storage.(*PostgresStorage)."
+
+// callerOperation находит в стеке метод PostgresStorage, из которого выполняется запрос
+func callerOperation() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, storageMethodPrefix); ok {
+			// Замыкания внутри метода выглядят как "CreateBid.func1"
+			name, _, _ = strings.Cut(name, ".")
+			return name
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+type instrumentedConn struct {
+	driver.Conn
+	hooks []QueryHook
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := observe(ctx, c.hooks, QueryKindQuery, query)
+	rows, err := q.QueryContext(ctx, query, args)
+	done(err)
+	return rows, err
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := observe(ctx, c.hooks, QueryKindExec, query)
+	res, err := e.ExecContext(ctx, query, args)
+	done(err)
+	return res, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, query: query, hooks: c.hooks}, nil
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	ctx, done := observe(ctx, c.hooks, QueryKindBegin, "BEGIN")
+	var tx driver.Tx
+	var err error
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{Tx: tx, ctx: ctx, hooks: c.hooks}, nil
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type instrumentedTx struct {
+	driver.Tx
+	// ctx контекст начала транзакции: завершение транзакции относится к нему
+	ctx   context.Context
+	hooks []QueryHook
+}
+
+func (t *instrumentedTx) Commit() error {
+	_, done := observe(t.ctx, t.hooks, QueryKindCommit, "COMMIT")
+	err := t.Tx.Commit()
+	done(err)
+	return err
+}
+
+func (t *instrumentedTx) Rollback() error {
+	_, done := observe(t.ctx, t.hooks, QueryKindRollback, "ROLLBACK")
+	err := t.Tx.Rollback()
+	done(err)
+	return err
+}
+
+type instrumentedStmt struct {
+	driver.Stmt
+	query string
+	hooks []QueryHook
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, done := observe(ctx, s.hooks, QueryKindExec, s.query)
+	var res driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	done(err)
+	return res, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, done := observe(ctx, s.hooks, QueryKindQuery, s.query)
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	done(err)
+	return rows, err
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("driver does not support named parameters")
+		}
+		values[i] = a.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+)
+
+const (
+	tenderColumns = "t.id, t.name, t.description, t.service_type, t.status, t.organization_id, t.version, t.created_at"
+	bidColumns    = "b.id, b.name, b.description, b.status, b.tender_id, b.author_type, b.author_id, b.version, b.created_at"
+)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTender(rows *sql.Rows) (model.Tender, Cursor, error) {
+	t, createdAt, err := scanTenderColumns(rows)
+	return t, Cursor{CreatedAt: createdAt, Id: t.Id}, err
+}
+
+// scanTenderRow читает тендер, выбранный колонками tenderColumns
+func scanTenderRow(row rowScanner) (model.Tender, error) {
+	t, _, err := scanTenderColumns(row)
+	return t, err
+}
+
+func scanTenderColumns(row rowScanner) (model.Tender, time.Time, error) {
+	var t model.Tender
+	var createdAt time.Time
+	err := row.Scan(&t.Id, &t.Name, &t.Description, &t.ServiceType, &t.Status, &t.OrganizationId, &t.Version, &createdAt)
+	t.CreatedAt = createdAt.Format(time.RFC3339)
+	return t, createdAt, err
+}
+
+func scanBid(rows *sql.Rows) (model.Bid, Cursor, error) {
+	b, createdAt, err := scanBidColumns(rows)
+	return b, Cursor{CreatedAt: createdAt, Id: b.Id}, err
+}
+
+// scanBidRow читает предложение, выбранное колонками bidColumns
+func scanBidRow(row rowScanner) (model.Bid, error) {
+	b, _, err := scanBidColumns(row)
+	return b, err
+}
+
+func scanBidColumns(row rowScanner) (model.Bid, time.Time, error) {
+	var b model.Bid
+	var createdAt time.Time
+	err := row.Scan(&b.Id, &b.Name, &b.Description, &b.Status, &b.TenderId, &b.AuthorType, &b.AuthorId, &b.Version, &createdAt)
+	b.CreatedAt = createdAt.Format(time.RFC3339)
+	return b, createdAt, err
+}
+
+// queryPage выполняет запрос страницы и сканирует строки функцией scan
+func queryPage[T any](ctx context.Context, s *PostgresStorage, query string, args queryArgs, page Page,
+	scan func(*sql.Rows) (T, Cursor, error)) ([]T, PageInfo, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var items []T
+	var keys []Cursor
+	for rows.Next() {
+		item, key, err := scan(rows)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, PageInfo{}, err
+	}
+	items, info := finishPage(items, keys, page)
+	return items, info, nil
+}
+
+// ListTenders возвращает страницу видимых username тендеров с фильтрами из ListQuery;
+// сортировка при keyset-пагинации всегда по (created_at, id)
+func (s *PostgresStorage) ListTenders(ctx context.Context, q ListQuery, username string, page Page) ([]model.Tender, PageInfo, error) {
+	var args queryArgs
+	conds := tenderVisibility(q, username, &args)
+	cond, order := keyset("t", &args, page)
+	query := `
+        SELECT ` + tenderColumns + `
+        FROM tenders t
+        WHERE ` + strings.Join(append(conds, cond), " AND ") + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(page.Limit+1)
+	return queryPage(ctx, s, query, args, page, scanTender)
+}
+
+// ListUserTenders возвращает страницу тендеров, созданных пользователем
+func (s *PostgresStorage) ListUserTenders(ctx context.Context, username string, page Page) ([]model.Tender, PageInfo, error) {
+	args := queryArgs{username}
+	cond, order := keyset("t", &args, page)
+	query := `
+        SELECT ` + tenderColumns + `
+        FROM tenders t
+        WHERE t.creator_username = $1 AND ` + cond + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(page.Limit+1)
+	return queryPage(ctx, s, query, args, page, scanTender)
+}
+
+// ListUserBids возвращает страницу предложений, созданных пользователем
+func (s *PostgresStorage) ListUserBids(ctx context.Context, username string, page Page) ([]model.Bid, PageInfo, error) {
+	args := queryArgs{username}
+	cond, order := keyset("b", &args, page)
+	query := `
+        SELECT ` + bidColumns + `
+        FROM bids b
+        WHERE b.creator_username = $1 AND ` + cond + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(page.Limit+1)
+	return queryPage(ctx, s, query, args, page, scanBid)
+}
+
+// ListBidsForTender возвращает страницу видимых page.Username предложений по тендеру
+func (s *PostgresStorage) ListBidsForTender(ctx context.Context, tenderId string, page Page) ([]model.Bid, PageInfo, error) {
+	query, args := bidsForTenderPageQuery(tenderId, page)
+	return queryPage(ctx, s, query, args, page, scanBid)
+}
+
+func bidsForTenderPageQuery(tenderId string, page Page) (string, queryArgs) {
+	args := queryArgs{tenderId, page.Username}
+	visible := bidVisibility("(SELECT organization_id FROM tenders WHERE id = b.tender_id)", "$2", &args)
+	cond, order := keyset("b", &args, page)
+	query := `
+        SELECT ` + bidColumns + `
+        FROM bids b
+        WHERE b.tender_id = $1 AND ` + visible + ` AND ` + cond + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(page.Limit+1)
+	return query, args
+}
+
+// ListBidReviews возвращает страницу отзывов на предложения автора,
+// который подавал предложение на указанный тендер
+func (s *PostgresStorage) ListBidReviews(ctx context.Context, tenderId, authorUsername string, page Page) ([]model.BidReview, PageInfo, error) {
+	args := queryArgs{tenderId, authorUsername}
+	cond, order := keyset("r", &args, page)
+	query := `
+        SELECT r.id, r.description, r.created_at
+        FROM bid_reviews r
+        JOIN bids b ON b.id = r.bid_id
+        WHERE b.creator_username = $2
+          AND EXISTS (SELECT 1 FROM bids tb WHERE tb.tender_id = $1 AND tb.creator_username = $2)
+          AND ` + cond + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(page.Limit+1)
+	return queryPage(ctx, s, query, args, page, func(rows *sql.Rows) (model.BidReview, Cursor, error) {
+		var r model.BidReview
+		var createdAt time.Time
+		err := rows.Scan(&r.Id, &r.Description, &createdAt)
+		r.CreatedAt = createdAt.Format(time.RFC3339)
+		return r, Cursor{CreatedAt: createdAt, Id: r.Id}, err
+	})
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go-tenders/model"
+)
+
+// ListQueryError ошибка разбора параметров сортировки и фильтрации, возвращается клиенту как 400
+type ListQueryError struct {
+	Reason string
+}
+
+func (e *ListQueryError) Error() string {
+	return e.Reason
+}
+
+// SortField поле сортировки
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Filter условие «колонка равна одному из значений»
+type Filter struct {
+	Column string
+	Values []string
+}
+
+// ListQuery разобранные параметры сортировки и фильтрации списка.
+// Колонки берутся только из ListSchema, значения передаются параметрами запроса.
+type ListQuery struct {
+	Sort    []SortField
+	Filters []Filter
+}
+
+// FilterField описание поля, по которому разрешена фильтрация
+type FilterField struct {
+	Column string
+	// Allowed допустимые значения; пустой список — любое значение
+	Allowed []string
+}
+
+// ListSchema белый список полей сортировки и фильтрации для сущности
+type ListSchema struct {
+	Sortable    map[string]string
+	Filterable  map[string]FilterField
+	DefaultSort []SortField
+	// IdColumn добавляется последним ключом сортировки для стабильного порядка
+	IdColumn string
+}
+
+// TenderListSchema поля тендеров, доступные для сортировки и фильтрации
+var TenderListSchema = ListSchema{
+	Sortable: map[string]string{
+		"name":      "t.name",
+		"createdAt": "t.created_at",
+		"version":   "t.version",
+	},
+	Filterable: map[string]FilterField{
+		"status":         {Column: "t.status", Allowed: []string{string(model.Created), string(model.Published), string(model.Closed)}},
+		"organizationId": {Column: "t.organization_id"},
+		"service_type":   {Column: "t.service_type", Allowed: []string{string(model.Construction), string(model.Delivery), string(model.Manufacture)}},
+	},
+	DefaultSort: []SortField{{Column: "t.name"}},
+	IdColumn:    "t.id",
+}
+
+// BidListSchema поля предложений, доступные для сортировки и фильтрации
+var BidListSchema = ListSchema{
+	Sortable: map[string]string{
+		"name":      "b.name",
+		"createdAt": "b.created_at",
+		"version":   "b.version",
+	},
+	Filterable: map[string]FilterField{
+		"status": {Column: "b.status", Allowed: []string{
+			string(model.BidStatusCreated), string(model.BidStatusPublished), string(model.BidStatusCanceled),
+			string(model.BidStatusApproved), string(model.BidStatusRejected),
+		}},
+		"authorType": {Column: "b.author_type", Allowed: []string{string(model.Organization), string(model.User)}},
+	},
+	DefaultSort: []SortField{{Column: "b.name"}},
+	IdColumn:    "b.id",
+}
+
+// Parse разбирает параметр sort ("name,-createdAt") и фильтры из строки запроса.
+// Фильтр принимает несколько значений: повтором параметра или через запятую.
+func (ls ListSchema) Parse(values url.Values) (ListQuery, error) {
+	var q ListQuery
+
+	for _, raw := range values["sort"] {
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			desc := strings.HasPrefix(name, "-")
+			column, ok := ls.Sortable[strings.TrimPrefix(name, "-")]
+			if !ok {
+				return ListQuery{}, &ListQueryError{Reason: fmt.Sprintf("unsupported sort field %q", strings.TrimPrefix(name, "-"))}
+			}
+			q.Sort = append(q.Sort, SortField{Column: column, Desc: desc})
+		}
+	}
+
+	for name, field := range ls.Filterable {
+		var vals []string
+		for _, raw := range values[name] {
+			for _, v := range strings.Split(raw, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					vals = append(vals, v)
+				}
+			}
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		if len(field.Allowed) > 0 {
+			for _, v := range vals {
+				if !slices.Contains(field.Allowed, v) {
+					return ListQuery{}, &ListQueryError{Reason: fmt.Sprintf("invalid value %q for filter %s", v, name)}
+				}
+			}
+		}
+		q.Filters = append(q.Filters, Filter{Column: field.Column, Values: vals})
+	}
+	// Порядок фильтров не должен зависеть от обхода map
+	slices.SortFunc(q.Filters, func(a, b Filter) int { return strings.Compare(a.Column, b.Column) })
+
+	return q, nil
+}
+
+// HasFilter сообщает, задан ли фильтр по колонке
+func (q ListQuery) HasFilter(column string) bool {
+	return slices.ContainsFunc(q.Filters, func(f Filter) bool { return f.Column == column })
+}
+
+// where переводит фильтры в условия с плейсхолдерами
+func (q ListQuery) where(args *queryArgs) []string {
+	var conds []string
+	for _, f := range q.Filters {
+		placeholders := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			placeholders[i] = args.add(v)
+		}
+		conds = append(conds, f.Column+" IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	return conds
+}
+
+// orderBy возвращает выражение ORDER BY с сортировкой по умолчанию и id в конце
+func (q ListQuery) orderBy(ls ListSchema) string {
+	fields := q.Sort
+	if len(fields) == 0 {
+		fields = ls.DefaultSort
+	}
+	parts := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		if f.Desc {
+			parts = append(parts, f.Column+" DESC")
+		} else {
+			parts = append(parts, f.Column+" ASC")
+		}
+	}
+	return strings.Join(append(parts, ls.IdColumn+" ASC"), ", ")
+}
+
+// responsibleFor условие «пользователь (плейсхолдер user) ответственный за организацию organization»
+func responsibleFor(organization, user string) string {
+	return `EXISTS (
+            SELECT 1
+            FROM organization_responsible r
+            JOIN employee e ON e.id = r.user_id
+            WHERE e.username = ` + user + ` AND r.organization_id::text = ` + organization + `::text
+        )`
+}
+
+// tenderVisibility добавляет к фильтрам правило видимости: опубликованные тендеры
+// видны всем, остальные — только ответственным за организацию тендера.
+// Фильтр status лишь сужает выборку и не расширяет видимость.
+func tenderVisibility(q ListQuery, username string, args *queryArgs) []string {
+	conds := q.where(args)
+	return append(conds, "(t.status = "+args.add(model.Published)+" OR "+responsibleFor("t.organization_id", args.add(username))+")")
+}
+
+// bidVisibility правило видимости предложений для пользователя (плейсхолдер user):
+// свои и своей организации, а ответственным за организацию тендера tenderOrganization —
+// все, кроме еще не опубликованных (Created)
+func bidVisibility(tenderOrganization, user string, args *queryArgs) string {
+	return `(
+            b.creator_username = ` + user + `
+            OR (b.author_type = ` + args.add(model.Organization) + ` AND ` + responsibleFor("b.author_id", user) + `)
+            OR (b.status <> ` + args.add(model.BidStatusCreated) + ` AND ` + responsibleFor(tenderOrganization, user) + `)
+        )`
+}
+
+// QueryTenders возвращает видимые username тендеры с сортировкой и фильтрами из ListQuery
+func (s *PostgresStorage) QueryTenders(ctx context.Context, q ListQuery, username string, limit, offset int) ([]model.Tender, error) {
+	query, args := tendersQuery(q, username)
+	query, args = paged(query, args, limit, offset)
+	tenders, _, err := queryPage(ctx, s, query, args, Page{Limit: limit}, scanTender)
+	return tenders, err
+}
+
+// tendersQuery видимые username тендеры с фильтрами и сортировкой из ListQuery;
+// общий запрос GET /tenders и его выгрузки
+func tendersQuery(q ListQuery, username string) (string, queryArgs) {
+	var args queryArgs
+	query := `
+        SELECT ` + tenderColumns + `
+        FROM tenders t
+        WHERE ` + strings.Join(tenderVisibility(q, username, &args), " AND ") + `
+        ORDER BY ` + q.orderBy(TenderListSchema)
+	return query, args
+}
+
+// userTendersQuery тендеры, созданные пользователем, по алфавиту; общий запрос
+// GET /tenders/my и его выгрузки
+func userTendersQuery(username string) (string, queryArgs) {
+	query := `
+        SELECT ` + tenderColumns + `
+        FROM tenders t
+        WHERE t.creator_username = $1
+        ORDER BY t.name, t.id`
+	return query, queryArgs{username}
+}
+
+// QueryBidsForTender возвращает видимые username предложения по тендеру
+// с сортировкой и фильтрами из ListQuery
+func (s *PostgresStorage) QueryBidsForTender(ctx context.Context, tenderId string, q ListQuery, username string, limit, offset int) ([]model.Bid, error) {
+	query, args := bidsForTenderQuery(tenderId, q, username)
+	query, args = paged(query, args, limit, offset)
+	bids, _, err := queryPage(ctx, s, query, args, Page{Limit: limit}, scanBid)
+	return bids, err
+}
+
+// bidsForTenderQuery видимые username предложения по тендеру с фильтрами и
+// сортировкой из ListQuery; общий запрос GET /bids/{tenderId}/list и его выгрузки
+func bidsForTenderQuery(tenderId string, q ListQuery, username string) (string, queryArgs) {
+	args := queryArgs{tenderId, username}
+	conds := append(q.where(&args), "b.tender_id = $1",
+		bidVisibility("(SELECT organization_id FROM tenders WHERE id = b.tender_id)", "$2", &args))
+	query := `
+        SELECT ` + bidColumns + `
+        FROM bids b
+        WHERE ` + strings.Join(conds, " AND ") + `
+        ORDER BY ` + q.orderBy(BidListSchema)
+	return query, args
+}
+
+// paged добавляет к запросу списка LIMIT и OFFSET
+func paged(query string, args queryArgs, limit, offset int) (string, queryArgs) {
+	query += `
+        LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(offset)
+	return query, args
+}
+
+// QueryUserBids возвращает предложения пользователя с сортировкой и фильтрами из ListQuery
+func (s *PostgresStorage) QueryUserBids(ctx context.Context, username string, q ListQuery, limit, offset int) ([]model.Bid, error) {
+	query, args := userBidsQuery(username, q, limit, offset)
+	bids, _, err := queryPage(ctx, s, query, args, Page{Limit: limit}, scanBid)
+	return bids, err
+}
+
+func userBidsQuery(username string, q ListQuery, limit, offset int) (string, queryArgs) {
+	args := queryArgs{username}
+	conds := append(q.where(&args), "b.creator_username = $1",
+		bidVisibility("(SELECT organization_id FROM tenders WHERE id = b.tender_id)", "$1", &args))
+	query := `
+        SELECT ` + bidColumns + `
+        FROM bids b
+        WHERE ` + strings.Join(conds, " AND ") + `
+        ORDER BY ` + q.orderBy(BidListSchema) + `
+        LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(offset)
+	return query, args
+}
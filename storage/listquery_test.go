@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// Фильтр status сужает выборку, но не снимает правило видимости
+func TestTenderVisibilityKeepsRuleWithStatusFilter(t *testing.T) {
+	for _, raw := range []string{"", "status=Created", "status=Closed,Published&organizationId=org1"} {
+		t.Run(raw, func(t *testing.T) {
+			values, _ := url.ParseQuery(raw)
+			q, err := TenderListSchema.Parse(values)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var args queryArgs
+			conds := tenderVisibility(q, "user1", &args)
+			rule := conds[len(conds)-1]
+			if !strings.HasPrefix(rule, "(t.status = $") || !strings.Contains(rule, "OR EXISTS") {
+				t.Fatalf("visibility rule missing: %q", strings.Join(conds, " AND "))
+			}
+			if len(conds) != len(q.Filters)+1 {
+				t.Fatalf("got %d conditions for %d filters", len(conds), len(q.Filters))
+			}
+			if args[len(args)-2] != model.Published || args[len(args)-1] != "user1" {
+				t.Fatalf("args = %v", args)
+			}
+		})
+	}
+}
+
+// Черновик (Created) чужой организации скрыт от ответственного за организацию
+// тендера: доступ по тендеру ограничен статусом, а свои предложения и предложения
+// своей организации видны без ограничения. Фильтр status правило не снимает.
+func TestBidsForTenderQueryHidesForeignDrafts(t *testing.T) {
+	draftRule := regexp.MustCompile(`b\.status <> \$(\d+) AND EXISTS \([^)]*e\.username = \$2 AND r\.organization_id::text = \(SELECT organization_id FROM tenders WHERE id = b\.tender_id\)::text`)
+	for _, raw := range []string{"", "status=Created", "sort=name&order=asc"} {
+		t.Run(raw, func(t *testing.T) {
+			values, _ := url.ParseQuery(raw)
+			q, err := BidListSchema.Parse(values)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			query, args := bidsForTenderQuery("tender-1", q, "user1")
+			if args[0] != "tender-1" || args[1] != "user1" {
+				t.Fatalf("args = %v; want tender id and username first", args)
+			}
+			m := draftRule.FindStringSubmatch(query)
+			if m == nil {
+				t.Fatalf("tender organization access is not limited to published bids: %s", query)
+			}
+			var n int
+			fmt.Sscan(m[1], &n)
+			if args[n-1] != model.BidStatusCreated {
+				t.Fatalf("$%d = %v, want %s", n, args[n-1], model.BidStatusCreated)
+			}
+			if !strings.Contains(query, "b.creator_username = $2") || !strings.Contains(query, "e.username = $2 AND r.organization_id::text = b.author_id::text") {
+				t.Fatalf("own bids are not visible: %s", query)
+			}
+		})
+	}
+}
+
+// Список предложений пользователя собирается с тем же правилом видимости
+func TestUserBidsQueryAppliesVisibility(t *testing.T) {
+	query, args := userBidsQuery("user1", ListQuery{}, 10, 0)
+	if args[0] != "user1" || !strings.Contains(query, "b.creator_username = $1") {
+		t.Fatalf("query = %s, args = %v", query, args)
+	}
+	if n := strings.Count(query, "FROM organization_responsible r"); n != 2 {
+		t.Fatalf("got %d responsibility checks, want 2: %s", n, query)
+	}
+}
@@ -0,0 +1,105 @@
+-- Базовая схема: сотрудники и организации (см. README) и сущности сервиса.
+-- Все объекты создаются только при отсутствии, поэтому миграция безопасна
+-- для баз, где схема была создана вручную до появления migrate.
+
+CREATE TABLE IF NOT EXISTS employee (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) UNIQUE NOT NULL,
+    first_name VARCHAR(50),
+    last_name VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+BEGIN
+    CREATE TYPE organization_type AS ENUM ('IE', 'LLC', 'JSC');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS organization (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    type organization_type,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_responsible (
+    id SERIAL PRIMARY KEY,
+    organization_id INT REFERENCES organization(id) ON DELETE CASCADE,
+    user_id INT REFERENCES employee(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tenders (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    service_type VARCHAR(32) NOT NULL CHECK (service_type IN ('Construction', 'Delivery', 'Manufacture')),
+    status VARCHAR(32) NOT NULL DEFAULT 'Created' CHECK (status IN ('Created', 'Published', 'Closed')),
+    organization_id INT NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    creator_username VARCHAR(50) NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS tenders_organization_idx ON tenders (organization_id);
+
+CREATE TABLE IF NOT EXISTS bids (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'Created'
+        CHECK (status IN ('Created', 'Published', 'Canceled', 'Approved', 'Rejected')),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    author_type VARCHAR(16) NOT NULL CHECK (author_type IN ('Organization', 'User')),
+    author_id VARCHAR(100) NOT NULL,
+    creator_username VARCHAR(50) NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Решения ответственных по предложению: одно на пользователя, для кворума
+CREATE TABLE IF NOT EXISTS bid_decisions (
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL,
+    decision VARCHAR(16) NOT NULL CHECK (decision IN ('Approved', 'Rejected')),
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bid_id, username)
+);
+
+-- Отзывы ответственных за организацию тендера на предложения (PUT /bids/{bidId}/feedback)
+CREATE TABLE IF NOT EXISTS bid_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL,
+    description VARCHAR(1000) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Параметры прошлых версий для отката: текущая версия сохраняется перед каждой правкой
+CREATE TABLE IF NOT EXISTS tenders_history (
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    service_type VARCHAR(32) NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS tenders_history_tender_version_idx ON tenders_history (tender_id, version);
+
+CREATE TABLE IF NOT EXISTS bid_history (
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS bid_history_bid_version_idx ON bid_history (bid_id, version);
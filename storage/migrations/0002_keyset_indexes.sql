@@ -0,0 +1,7 @@
+-- Индексы для keyset-пагинации списков по (created_at, id)
+
+CREATE INDEX IF NOT EXISTS tenders_created_at_id_idx ON tenders (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS tenders_creator_created_at_idx ON tenders (creator_username, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS bids_creator_created_at_idx ON bids (creator_username, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS bids_tender_created_at_idx ON bids (tender_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS bid_reviews_bid_created_at_idx ON bid_reviews (bid_id, created_at DESC, id DESC);
@@ -0,0 +1,31 @@
+-- Email-уведомления: настройки пользователей и очередь отправки
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    username VARCHAR(50) PRIMARY KEY,
+    email VARCHAR(254) NOT NULL,
+    locale VARCHAR(8) NOT NULL DEFAULT 'ru',
+    bid_received BOOLEAN NOT NULL DEFAULT TRUE,
+    decision_required BOOLEAN NOT NULL DEFAULT TRUE,
+    bid_decision BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL,
+    username VARCHAR(50) NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    dead_at TIMESTAMPTZ,
+    UNIQUE (event_id, username, kind)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_pending_idx ON notifications (next_attempt_at, id)
+    WHERE sent_at IS NULL AND dead_at IS NULL;
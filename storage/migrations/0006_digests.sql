@@ -0,0 +1,21 @@
+-- Подписки на ежедневную сводку новых опубликованных тендеров
+
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    username VARCHAR(50) NOT NULL,
+    service_types JSONB NOT NULL DEFAULT '[]',
+    keywords JSONB NOT NULL DEFAULT '[]',
+    channel VARCHAR(16) NOT NULL,
+    target TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL DEFAULT '',
+    locale VARCHAR(8) NOT NULL DEFAULT 'ru',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS digest_subscriptions_username_idx ON digest_subscriptions (username) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS digest_subscriptions_due_idx ON digest_subscriptions (last_sent_at) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_tender_published_idx ON outbox (created_at)
+    WHERE event_type = 'tender.published';
@@ -0,0 +1,17 @@
+-- Ключи идемпотентности POST /tenders/new и POST /bids/new: повтор запроса
+-- с тем же ключом получает сохраненный ответ вместо создания дубликата
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(128) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INT,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,5 @@
+-- Повторы неудачных сводок с экспоненциальной задержкой: подписка с ошибкой
+-- не выбирается снова до next_attempt_at
+
+ALTER TABLE digest_subscriptions ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE digest_subscriptions ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
@@ -0,0 +1,13 @@
+-- История версий хранит параметры тендеров и предложений в колонках, откат
+-- восстанавливает их напрямую. Схемы, созданные до базовой миграции, хранили
+-- снимок в колонке data; записать его было невозможно (откат завершался ошибкой),
+-- поэтому таблицы пусты и колонки меняются без переноса данных.
+
+ALTER TABLE tenders_history DROP COLUMN IF EXISTS data;
+ALTER TABLE tenders_history ADD COLUMN IF NOT EXISTS name VARCHAR(100) NOT NULL;
+ALTER TABLE tenders_history ADD COLUMN IF NOT EXISTS description VARCHAR(500) NOT NULL;
+ALTER TABLE tenders_history ADD COLUMN IF NOT EXISTS service_type VARCHAR(32) NOT NULL;
+
+ALTER TABLE bid_history DROP COLUMN IF EXISTS data;
+ALTER TABLE bid_history ADD COLUMN IF NOT EXISTS name VARCHAR(100) NOT NULL;
+ALTER TABLE bid_history ADD COLUMN IF NOT EXISTS description VARCHAR(500) NOT NULL;
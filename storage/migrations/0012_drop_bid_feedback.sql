@@ -0,0 +1,14 @@
+-- Отзывы хранятся в bid_reviews, где их читает GET /bids/{tenderId}/reviews.
+-- Отзывы, записанные раньше в bid_feedback (автор — в колонке rating),
+-- переносятся, после чего таблица удаляется.
+
+DO $$
+BEGIN
+    IF to_regclass('bid_feedback') IS NOT NULL THEN
+        INSERT INTO bid_reviews (bid_id, username, description, created_at)
+        SELECT bid_id, COALESCE(rating, ''), feedback_text, updated_at
+        FROM bid_feedback;
+        DROP TABLE bid_feedback;
+    END IF;
+END
+$$;
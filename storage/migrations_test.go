@@ -0,0 +1,19 @@
+package storage
+
+import "testing"
+
+// Базовая схема идет первой: остальные миграции изменяют ее таблицы
+func TestMigrationsStartWithBaseline(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 0 || migrations[0].Name != "0000_baseline.sql" {
+		t.Fatalf("first migration = %+v, want 0000_baseline.sql", migrations)
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			t.Fatalf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+}
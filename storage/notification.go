@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-tenders/model"
+)
+
+// notificationPreferenceColumn колонка настройки, разрешающей уведомление вида kind
+var notificationPreferenceColumn = map[model.NotificationKind]string{
+	model.NotificationBidReceived:      "p.bid_received",
+	model.NotificationDecisionRequired: "p.decision_required",
+	model.NotificationBidApproved:      "p.bid_decision",
+	model.NotificationBidRejected:      "p.bid_decision",
+}
+
+const preferenceColumns = "p.username, p.email, p.locale, p.bid_received, p.decision_required, p.bid_decision"
+
+// GetNotificationPreferences возвращает настройки уведомлений пользователя
+func (s *PostgresStorage) GetNotificationPreferences(ctx context.Context, username string) (model.NotificationPreferences, error) {
+	var p model.NotificationPreferences
+	err := s.db.QueryRowContext(ctx, `
+        SELECT `+preferenceColumns+`
+        FROM notification_preferences p
+        WHERE p.username = $1
+    `, username).Scan(&p.Username, &p.Email, &p.Locale, &p.BidReceived, &p.DecisionRequired, &p.BidDecision)
+	return p, err
+}
+
+// SaveNotificationPreferences создает или заменяет настройки уведомлений пользователя
+func (s *PostgresStorage) SaveNotificationPreferences(ctx context.Context, p model.NotificationPreferences) error {
+	_, err := s.db.ExecContext(ctx, `
+        INSERT INTO notification_preferences (username, email, locale, bid_received, decision_required, bid_decision)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (username) DO UPDATE SET
+            email = EXCLUDED.email,
+            locale = EXCLUDED.locale,
+            bid_received = EXCLUDED.bid_received,
+            decision_required = EXCLUDED.decision_required,
+            bid_decision = EXCLUDED.bid_decision,
+            updated_at = NOW()
+    `, p.Username, p.Email, p.Locale, p.BidReceived, p.DecisionRequired, p.BidDecision)
+	return err
+}
+
+func (s *PostgresStorage) queryRecipients(ctx context.Context, query string, args ...interface{}) ([]model.NotificationPreferences, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []model.NotificationPreferences
+	for rows.Next() {
+		var p model.NotificationPreferences
+		if err := rows.Scan(&p.Username, &p.Email, &p.Locale, &p.BidReceived, &p.DecisionRequired, &p.BidDecision); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, p)
+	}
+	return recipients, rows.Err()
+}
+
+// ResponsibleRecipients возвращает ответственных за организацию тендера,
+// включивших уведомления вида kind
+func (s *PostgresStorage) ResponsibleRecipients(ctx context.Context, tenderId string, kind model.NotificationKind) ([]model.NotificationPreferences, error) {
+	column, ok := notificationPreferenceColumn[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+	return s.queryRecipients(ctx, `
+        SELECT `+preferenceColumns+`
+        FROM tenders t
+        JOIN organization_responsible r ON r.organization_id::text = t.organization_id::text
+        JOIN employee e ON e.id = r.user_id
+        JOIN notification_preferences p ON p.username = e.username
+        WHERE t.id::text = $1 AND `+column, tenderId)
+}
+
+// BidAuthorRecipients возвращает автора предложения, если он включил уведомления вида kind
+func (s *PostgresStorage) BidAuthorRecipients(ctx context.Context, bidId string, kind model.NotificationKind) ([]model.NotificationPreferences, error) {
+	column, ok := notificationPreferenceColumn[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+	return s.queryRecipients(ctx, `
+        SELECT `+preferenceColumns+`
+        FROM bids b
+        JOIN notification_preferences p ON p.username = b.creator_username
+        WHERE b.id::text = $1 AND `+column, bidId)
+}
+
+// EnqueueNotifications ставит письма в очередь; повтор того же события игнорируется
+func (s *PostgresStorage) EnqueueNotifications(ctx context.Context, notifications []model.Notification) error {
+	for _, n := range notifications {
+		_, err := s.db.ExecContext(ctx, `
+            INSERT INTO notifications (event_id, username, email, kind, subject, body)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            ON CONFLICT (event_id, username, kind) DO NOTHING
+        `, n.EventId, n.Username, n.Email, n.Kind, n.Subject, n.Body)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimNotifications забирает письма, готовые к отправке, и резервирует их на lease
+func (s *PostgresStorage) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]model.Notification, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        UPDATE notifications
+        SET next_attempt_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id FROM notifications
+            WHERE sent_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, event_id, username, email, kind, subject, body, attempts
+    `, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		if err := rows.Scan(&n.Id, &n.EventId, &n.Username, &n.Email, &n.Kind, &n.Subject, &n.Body, &n.Attempts); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+// RecordNotificationAttempt сохраняет результат отправки письма. Для неудачной
+// попытки retryAt задает следующую, nil означает, что попытки исчерпаны.
+func (s *PostgresStorage) RecordNotificationAttempt(ctx context.Context, id int64, sendErr error, retryAt *time.Time) error {
+	if sendErr == nil {
+		_, err := s.db.ExecContext(ctx, `
+            UPDATE notifications SET attempts = attempts + 1, last_error = NULL, sent_at = NOW()
+            WHERE id = $1
+        `, id)
+		return err
+	}
+	if retryAt == nil {
+		_, err := s.db.ExecContext(ctx, `
+            UPDATE notifications SET attempts = attempts + 1, last_error = $2, dead_at = NOW()
+            WHERE id = $1
+        `, id, sendErr.Error())
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE notifications SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+        WHERE id = $1
+    `, id, sendErr.Error(), *retryAt)
+	return err
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go-tenders/model"
+)
+
+// withTx выполняет fn в транзакции: фиксирует при успехе, откатывает при ошибке или панике
+func (s *PostgresStorage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertEvent записывает доменное событие в outbox в рамках транзакции изменения
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType model.EventType, aggregateId string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO outbox (event_type, aggregate_id, payload)
+        VALUES ($1, $2, $3)
+    `, eventType, aggregateId, data)
+	return err
+}
+
+// ClaimEvents забирает до limit событий, готовых к доставке, и откладывает их
+// повторную выдачу на lease, чтобы параллельные relay не доставляли их одновременно
+func (s *PostgresStorage) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        UPDATE outbox
+        SET next_attempt_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id FROM outbox
+            WHERE delivered_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, event_type, aggregate_id, payload, created_at, attempts
+    `, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.Event
+	for rows.Next() {
+		var e model.Event
+		if err := rows.Scan(&e.Id, &e.Type, &e.AggregateId, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не гарантирует порядок, а потребителям он важен
+	slices.SortFunc(events, func(a, b model.Event) int { return cmp.Compare(a.Id, b.Id) })
+	return events, nil
+}
+
+// MarkEventDelivered отмечает событие доставленным во все получатели
+func (s *PostgresStorage) MarkEventDelivered(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE outbox SET delivered_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// MarkEventFailed учитывает неудачную попытку доставки. Если retryAt равен nil,
+// попытки исчерпаны и событие больше не выдается relay.
+func (s *PostgresStorage) MarkEventFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := s.db.ExecContext(ctx, `
+            UPDATE outbox
+            SET attempts = attempts + 1, last_error = $2, dead_at = NOW()
+            WHERE id = $1
+        `, id, reason)
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE outbox
+        SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+        WHERE id = $1
+    `, id, reason, *retryAt)
+	return err
+}
+
+// EventsAfter возвращает события с id больше afterId по возрастанию id вместе
+// с организацией и видом услуги тендера, автором и статусом предложения. В отличие от
+// ClaimEvents не меняет состояние outbox, поэтому читать могут все экземпляры сервиса.
+func (s *PostgresStorage) EventsAfter(ctx context.Context, afterId int64, limit int) ([]model.StreamEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT o.id, o.event_type, o.aggregate_id, o.payload, o.created_at,
+               COALESCE(t.organization_id::text, ''), COALESCE(t.service_type::text, ''),
+               COALESCE(b.creator_username, ''), COALESCE(b.author_type::text, ''),
+               COALESCE(b.author_id::text, ''), COALESCE(b.status::text, '')
+        FROM outbox o
+        LEFT JOIN bids b ON o.event_type LIKE 'bid.%' AND b.id::text = o.aggregate_id
+        LEFT JOIN tenders t ON t.id::text = CASE
+            WHEN o.event_type LIKE 'tender.%' THEN o.aggregate_id
+            ELSE b.tender_id::text
+        END
+        WHERE o.id > $1
+        ORDER BY o.id
+        LIMIT $2
+    `, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.StreamEvent
+	for rows.Next() {
+		var e model.StreamEvent
+		if err := rows.Scan(&e.Id, &e.Type, &e.AggregateId, &e.Payload, &e.CreatedAt,
+			&e.OrganizationId, &e.ServiceType, &e.BidAuthor, &e.BidAuthorType, &e.BidAuthorId, &e.BidStatus); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// LatestEventId возвращает id последнего записанного события, 0 если событий нет
+func (s *PostgresStorage) LatestEventId(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM outbox`).Scan(&id)
+	return id, err
+}
+
+// PurgeOutbox удаляет доставленные и отброшенные события старше olderThan и
+// возвращает их число. Последнее событие сохраняется, чтобы LatestEventId не
+// откатывался к нулю для клиентов /events/stream.
+func (s *PostgresStorage) PurgeOutbox(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `
+        DELETE FROM outbox
+        WHERE (delivered_at IS NOT NULL OR dead_at IS NOT NULL)
+          AND created_at < $1
+          AND id < (SELECT MAX(id) FROM outbox)
+    `, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ClaimEvents резервирует события на lease и отдает их по возрастанию id,
+// даже если RETURNING вернул строки в другом порядке
+func TestClaimEventsLease(t *testing.T) {
+	s, db := newFakeStorage(t)
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	db.on("UPDATE outbox", fakeRule{rows: [][]driver.Value{
+		{int64(3), "bid.created", "b3", []byte(`{}`), created, int64(0)},
+		{int64(1), "tender.created", "t1", []byte(`{}`), created, int64(2)},
+	}})
+
+	events, err := s.ClaimEvents(context.Background(), 10, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Id != 1 || events[1].Id != 3 || events[0].Attempts != 2 {
+		t.Fatalf("events = %+v", events)
+	}
+
+	q := db.executed("UPDATE outbox")[0]
+	if q.Args[0] != 10 || q.Args[1] != 30.0 {
+		t.Fatalf("args = %v; want limit and lease in seconds", q.Args)
+	}
+	for _, part := range []string{"next_attempt_at = NOW() + make_interval(secs => $2)", "next_attempt_at <= NOW()",
+		"delivered_at IS NULL AND dead_at IS NULL", "FOR UPDATE SKIP LOCKED"} {
+		if !strings.Contains(q.SQL, part) {
+			t.Errorf("query does not contain %q", part)
+		}
+	}
+}
+
+// MarkEventFailed откладывает повтор до retryAt или отбрасывает событие
+func TestMarkEventFailed(t *testing.T) {
+	retryAt := time.Date(2024, 5, 1, 0, 0, 4, 0, time.UTC)
+	tests := []struct {
+		name    string
+		retryAt *time.Time
+		set     string
+	}{
+		{"retry after backoff", &retryAt, "next_attempt_at = $3"},
+		{"attempts exhausted", nil, "dead_at = NOW()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newFakeStorage(t)
+			if err := s.MarkEventFailed(context.Background(), 7, "sink down", tt.retryAt); err != nil {
+				t.Fatal(err)
+			}
+			q := db.executed("UPDATE outbox")[0]
+			if !strings.Contains(q.SQL, "attempts = attempts + 1, last_error = $2") || !strings.Contains(q.SQL, tt.set) {
+				t.Fatalf("query = %s", q.SQL)
+			}
+			if q.Args[0] != int64(7) || q.Args[1] != "sink down" {
+				t.Fatalf("args = %v", q.Args)
+			}
+			if tt.retryAt != nil && q.Args[2] != retryAt {
+				t.Fatalf("retry at %v, want %v", q.Args[2], retryAt)
+			}
+			if tt.retryAt == nil && len(q.Args) != 2 {
+				t.Fatalf("args = %v", q.Args)
+			}
+		})
+	}
+}
+
+// PurgeOutbox удаляет только обработанные старые события и сохраняет последнее
+func TestPurgeOutbox(t *testing.T) {
+	s, db := newFakeStorage(t)
+	db.on("DELETE FROM outbox", fakeRule{affected: 5})
+
+	before := time.Now()
+	n, err := s.PurgeOutbox(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Fatalf("purged %d, want 5", n)
+	}
+
+	q := db.executed("DELETE FROM outbox")[0]
+	cutoff, _ := q.Args[0].(time.Time)
+	if want := before.Add(-24 * time.Hour); cutoff.Before(want) || cutoff.After(time.Now().Add(-24*time.Hour)) {
+		t.Fatalf("cutoff = %v, want about %v", cutoff, want)
+	}
+	for _, part := range []string{"delivered_at IS NOT NULL OR dead_at IS NOT NULL", "created_at < $1", "id < (SELECT MAX(id) FROM outbox)"} {
+		if !strings.Contains(q.SQL, part) {
+			t.Errorf("query does not contain %q", part)
+		}
+	}
+}
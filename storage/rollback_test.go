@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+// versionedRow параметры сущности в памяти для fakeDB
+type versionedRow struct {
+	name, description, serviceType string
+	version                        int64
+}
+
+// versionStore предложение bid-1 на тендер tender-1 и их история версий
+type versionStore struct {
+	bid, tender   versionedRow
+	bidHistory    []versionedRow
+	tenderHistory []versionedRow
+}
+
+var rollbackCreatedAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func (v *versionStore) bidRow() []driver.Value {
+	return []driver.Value{"bid-1", v.bid.name, v.bid.description, "Published", "tender-1", "Organization", "org-2", v.bid.version, rollbackCreatedAt}
+}
+
+func (v *versionStore) tenderRow() []driver.Value {
+	return []driver.Value{"tender-1", v.tender.name, v.tender.description, v.tender.serviceType, "Published", "org-1", v.tender.version, rollbackCreatedAt}
+}
+
+// restore ищет версию в истории и делает ее текущей с новым номером
+func restore(row *versionedRow, history []versionedRow, version driver.Value) bool {
+	for _, h := range history {
+		if int64(h.version) == int64(version.(int32)) {
+			*row = versionedRow{name: h.name, description: h.description, serviceType: h.serviceType, version: row.version + 1}
+			return true
+		}
+	}
+	return false
+}
+
+// stringArg значение необязательного текстового аргумента
+func stringArg(v driver.Value, current string) string {
+	if s, ok := v.(*string); ok && s != nil {
+		return *s
+	}
+	return current
+}
+
+// newVersionStorage fakeDB, который выполняет запросы правок, отката и аудита
+// над versionStore
+func newVersionStorage(t *testing.T, v *versionStore) (*PostgresStorage, *fakeDB) {
+	s, db := newFakeStorage(t)
+	db.on("FROM bids b\n            JOIN tenders t", fakeRule{fn: func([]driver.Value) ([][]driver.Value, error) {
+		return [][]driver.Value{{v.bid.version, "org-1"}}, nil
+	}})
+	db.on("FROM tenders WHERE id::text = $1", fakeRule{fn: func([]driver.Value) ([][]driver.Value, error) {
+		return [][]driver.Value{{v.tender.version, "org-1"}}, nil
+	}})
+	db.on("INSERT INTO bid_history", fakeRule{fn: func([]driver.Value) ([][]driver.Value, error) {
+		v.bidHistory = append(v.bidHistory, v.bid)
+		return nil, nil
+	}})
+	db.on("INSERT INTO tenders_history", fakeRule{fn: func([]driver.Value) ([][]driver.Value, error) {
+		v.tenderHistory = append(v.tenderHistory, v.tender)
+		return nil, nil
+	}})
+	db.on("FROM bid_history h", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		if !restore(&v.bid, v.bidHistory, args[1]) {
+			return nil, nil
+		}
+		return [][]driver.Value{v.bidRow()}, nil
+	}})
+	db.on("FROM tenders_history h", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		if !restore(&v.tender, v.tenderHistory, args[1]) {
+			return nil, nil
+		}
+		return [][]driver.Value{v.tenderRow()}, nil
+	}})
+	db.on("UPDATE bids b\n        SET name = COALESCE", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		v.bid = versionedRow{name: stringArg(args[0], v.bid.name), description: stringArg(args[1], v.bid.description), version: v.bid.version + 1}
+		return [][]driver.Value{v.bidRow()}, nil
+	}})
+	return s, db
+}
+
+// eventPayload разбирает единственное событие outbox
+func eventPayload(t *testing.T, db *fakeDB, want model.EventType, v interface{}) {
+	t.Helper()
+	events := db.executed("INSERT INTO outbox")
+	if len(events) != 1 || events[0].Args[0] != want {
+		t.Fatalf("outbox events = %v, want one %s", events, want)
+	}
+	if err := json.Unmarshal(events[0].Args[2].([]byte), v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// auditVersions версии до и после из единственной записи аудита
+func auditVersions(t *testing.T, db *fakeDB) (before, after int32) {
+	t.Helper()
+	entries := db.executed("INSERT INTO audit_log")
+	if len(entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(entries))
+	}
+	return *entries[0].Args[5].(*int32), *entries[0].Args[6].(*int32)
+}
+
+// Правка сохраняет прежнюю версию в историю, откат возвращает ее параметры
+// под новым номером версии и записывает аудит и событие, как правка
+func TestRollbackBidRestoresHistory(t *testing.T) {
+	v := &versionStore{bid: versionedRow{name: "v1", description: "first", version: 1}}
+	s, db := newVersionStorage(t, v)
+	ctx := context.Background()
+
+	name := "v2"
+	if _, err := s.EditBid(ctx, "bid-1", model.EditBidParams{Username: "u"}, model.BidIdEditBody{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.bidHistory) != 1 || v.bidHistory[0] != (versionedRow{name: "v1", description: "first", version: 1}) {
+		t.Fatalf("history after edit = %+v", v.bidHistory)
+	}
+
+	db.reset()
+	if err := s.RollbackBid(ctx, "bid-1", 1, model.RollbackBidParams{Username: "u"}); err != nil {
+		t.Fatal(err)
+	}
+	if v.bid != (versionedRow{name: "v1", description: "first", version: 3}) {
+		t.Fatalf("bid after rollback = %+v", v.bid)
+	}
+	if last := v.bidHistory[len(v.bidHistory)-1]; last.name != "v2" || last.version != 2 {
+		t.Fatalf("replaced version is not archived: %+v", v.bidHistory)
+	}
+	if before, after := auditVersions(t, db); before != 2 || after != 3 {
+		t.Fatalf("audit versions %d -> %d, want 2 -> 3", before, after)
+	}
+	var bid model.Bid
+	eventPayload(t, db, model.EventBidEdited, &bid)
+	if bid.Name != "v1" || bid.Version != 3 {
+		t.Fatalf("event payload = %+v", bid)
+	}
+	if len(db.executed("COMMIT")) != 1 {
+		t.Fatal("rollback is not committed")
+	}
+}
+
+func TestRollbackTenderRestoresHistory(t *testing.T) {
+	v := &versionStore{
+		tender:        versionedRow{name: "v2", description: "second", serviceType: "Delivery", version: 2},
+		tenderHistory: []versionedRow{{name: "v1", description: "first", serviceType: "Construction", version: 1}},
+	}
+	s, db := newVersionStorage(t, v)
+
+	if err := s.RollbackTender(context.Background(), "tender-1", 1, model.RollbackTenderParams{Username: "u"}); err != nil {
+		t.Fatal(err)
+	}
+	if v.tender != (versionedRow{name: "v1", description: "first", serviceType: "Construction", version: 3}) {
+		t.Fatalf("tender after rollback = %+v", v.tender)
+	}
+	if len(v.tenderHistory) != 2 || v.tenderHistory[1].name != "v2" {
+		t.Fatalf("replaced version is not archived: %+v", v.tenderHistory)
+	}
+	if before, after := auditVersions(t, db); before != 2 || after != 3 {
+		t.Fatalf("audit versions %d -> %d, want 2 -> 3", before, after)
+	}
+	var tender model.Tender
+	eventPayload(t, db, model.EventTenderEdited, &tender)
+	if tender.Name != "v1" || tender.ServiceType != model.Construction {
+		t.Fatalf("event payload = %+v", tender)
+	}
+}
+
+// Откат к несуществующей версии ничего не меняет
+func TestRollbackToUnknownVersion(t *testing.T) {
+	v := &versionStore{bid: versionedRow{name: "v1", version: 1}, tender: versionedRow{name: "v1", version: 1}}
+	s, db := newVersionStorage(t, v)
+	ctx := context.Background()
+
+	if err := s.RollbackBid(ctx, "bid-1", 7, model.RollbackBidParams{Username: "u"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("RollbackBid = %v, want sql.ErrNoRows", err)
+	}
+	if err := s.RollbackTender(ctx, "tender-1", 7, model.RollbackTenderParams{Username: "u"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("RollbackTender = %v, want sql.ErrNoRows", err)
+	}
+	if len(db.executed("ROLLBACK")) != 2 || len(db.executed("COMMIT")) != 0 {
+		t.Fatal("transactions are not rolled back")
+	}
+	if len(db.executed("INSERT INTO outbox")) != 0 || len(db.executed("INSERT INTO audit_log")) != 0 {
+		t.Fatal("failed rollback wrote audit or events")
+	}
+	if v.bid.version != 1 || v.tender.version != 1 {
+		t.Fatalf("versions changed: %+v %+v", v.bid, v.tender)
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-tenders/model"
+)
+
+// searchTsQuery объединяет запрос с русской и английской морфологией,
+// поэтому «поставка» и «delivery» находятся без указания языка
+const searchTsQuery = `(plainto_tsquery('russian', $1) || plainto_tsquery('english', $1))`
+
+// queryArgs накапливает аргументы запроса и выдает для них плейсхолдеры $N
+type queryArgs []interface{}
+
+func (a *queryArgs) add(v interface{}) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// Search выполняет полнотекстовый поиск по тендерам и предложениям, которые видит
+// username. Результаты отсортированы по релевантности, затем по дате создания.
+func (s *PostgresStorage) Search(ctx context.Context, params model.SearchParams, username string, limit, offset int) ([]model.SearchResult, error) {
+	query, args := searchQuery(params, username, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []model.SearchResult
+	for rows.Next() {
+		var (
+			kind, id, name, description, status   string
+			serviceType, organizationId, tenderId string
+			authorType, authorId                  string
+			version                               int32
+			createdAt                             time.Time
+			rank                                  float32
+		)
+		if err := rows.Scan(&kind, &id, &name, &description, &status, &serviceType, &organizationId,
+			&tenderId, &authorType, &authorId, &version, &createdAt, &rank); err != nil {
+			return nil, err
+		}
+
+		r := model.SearchResult{Kind: model.SearchResultKind(kind), Rank: rank}
+		switch r.Kind {
+		case model.SearchResultKindTender:
+			r.Tender = &model.Tender{
+				Id:             id,
+				Name:           name,
+				Description:    description,
+				Status:         model.TenderStatus(status),
+				ServiceType:    model.TenderServiceType(serviceType),
+				OrganizationId: organizationId,
+				Version:        version,
+				CreatedAt:      createdAt.Format(time.RFC3339),
+			}
+		case model.SearchResultKindBid:
+			r.Bid = &model.Bid{
+				Id:          id,
+				Name:        name,
+				Description: description,
+				Status:      model.BidStatus(status),
+				TenderId:    tenderId,
+				AuthorType:  model.BidAuthorType(authorType),
+				AuthorId:    authorId,
+				Version:     version,
+				CreatedAt:   createdAt.Format(time.RFC3339),
+			}
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// searchQuery строит запрос поиска. Видимость та же, что у списков: тендеры —
+// опубликованные и организаций, за которые отвечает username; предложения —
+// по правилу bidVisibility.
+func searchQuery(params model.SearchParams, username string, limit, offset int) (string, queryArgs) {
+	args := queryArgs{params.Query}
+	user := args.add(username)
+
+	// Фильтры по тендеру общие для обоих подзапросов: у предложения
+	// вид услуги и организация берутся из связанного тендера
+	var common []string
+	if params.ServiceType != nil {
+		common = append(common, "t.service_type = "+args.add(*params.ServiceType))
+	}
+	if params.OrganizationId != nil {
+		common = append(common, "t.organization_id = "+args.add(*params.OrganizationId))
+	}
+
+	entityFilters := func(alias string) []string {
+		conds := []string{alias + ".search_vector @@ " + searchTsQuery}
+		if params.Status != nil {
+			conds = append(conds, alias+".status = "+args.add(*params.Status))
+		}
+		if params.CreatedFrom != nil {
+			conds = append(conds, alias+".created_at >= "+args.add(*params.CreatedFrom))
+		}
+		if params.CreatedTo != nil {
+			conds = append(conds, alias+".created_at <= "+args.add(*params.CreatedTo))
+		}
+		return append(conds, common...)
+	}
+
+	var parts []string
+	if params.Kind == nil || *params.Kind == model.SearchResultKindTender {
+		conds := append(entityFilters("t"),
+			"(t.status = "+args.add(model.Published)+" OR "+responsibleFor("t.organization_id", user)+")")
+		parts = append(parts, `
+            SELECT 'tender' AS kind, t.id, t.name, t.description, t.status,
+                   t.service_type, t.organization_id, '' AS tender_id,
+                   '' AS author_type, '' AS author_id, t.version, t.created_at,
+                   ts_rank(t.search_vector, `+searchTsQuery+`) AS rank
+            FROM tenders t
+            WHERE `+strings.Join(conds, " AND "))
+	}
+	if params.Kind == nil || *params.Kind == model.SearchResultKindBid {
+		conds := append(entityFilters("b"), bidVisibility("t.organization_id", user, &args))
+		parts = append(parts, `
+            SELECT 'bid' AS kind, b.id, b.name, b.description, b.status,
+                   t.service_type, t.organization_id, b.tender_id,
+                   b.author_type, b.author_id, b.version, b.created_at,
+                   ts_rank(b.search_vector, `+searchTsQuery+`) AS rank
+            FROM bids b
+            JOIN tenders t ON t.id = b.tender_id
+            WHERE `+strings.Join(conds, " AND "))
+	}
+
+	query := `
+        SELECT kind, id, name, description, status, service_type, organization_id,
+               tender_id, author_type, author_id, version, created_at, rank
+        FROM (` + strings.Join(parts, "\n            UNION ALL") + `
+        ) r
+        ORDER BY rank DESC, created_at DESC, id
+        LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(offset)
+	return query, args
+}
+
+// ReindexSearch пересчитывает search_vector тендеров и предложений и
+// перестраивает их GIN-индексы — после смены словарей или конфигурации
+// полнотекстового поиска в базе. Вычисляемая колонка пересчитывается
+// только при изменении строки, поэтому строки обновляются без изменения данных.
+func (s *PostgresStorage) ReindexSearch(ctx context.Context) error {
+	for _, stmt := range []string{
+		`UPDATE tenders SET name = name`,
+		`UPDATE bids SET name = name`,
+		`REINDEX INDEX tenders_search_vector_idx`,
+		`REINDEX INDEX bids_search_vector_idx`,
+		`ANALYZE tenders`,
+		`ANALYZE bids`,
+	} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+)
+
+var placeholderRe = regexp.MustCompile(`\$(\d+)`)
+
+func TestSearchQuery(t *testing.T) {
+	tender, bid := model.SearchResultKindTender, model.SearchResultKindBid
+	status := "Created"
+	serviceType := model.Delivery
+	organizationId := "org1"
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		params  model.SearchParams
+		tenders bool
+		bids    bool
+		contain []string
+	}{
+		{
+			name:    "all kinds",
+			params:  model.SearchParams{Query: "поставка"},
+			tenders: true,
+			bids:    true,
+		},
+		{
+			name:    "tenders only",
+			params:  model.SearchParams{Query: "поставка", Kind: &tender},
+			tenders: true,
+		},
+		{
+			name:   "bids only",
+			params: model.SearchParams{Query: "поставка", Kind: &bid},
+			bids:   true,
+		},
+		{
+			name: "filters narrow visible results",
+			params: model.SearchParams{Query: "поставка", Status: &status, ServiceType: &serviceType,
+				OrganizationId: &organizationId, CreatedFrom: &from},
+			tenders: true,
+			bids:    true,
+			contain: []string{"t.status = $", "b.status = $", "t.service_type = $", "t.organization_id = $", "t.created_at >= $", "b.created_at >= $"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := searchQuery(tt.params, "user1", 10, 20)
+
+			if args[0] != tt.params.Query || args[1] != "user1" {
+				t.Fatalf("args = %v; want query and username first", args)
+			}
+			if args[len(args)-2] != 10 || args[len(args)-1] != 20 {
+				t.Fatalf("args = %v; want limit and offset last", args)
+			}
+			// Каждый аргумент используется в запросе, лишних плейсхолдеров нет
+			used := map[string]bool{}
+			for _, m := range placeholderRe.FindAllStringSubmatch(query, -1) {
+				used[m[1]] = true
+			}
+			for i := range args {
+				if !used[fmt.Sprint(i+1)] {
+					t.Errorf("argument $%d (%v) is not used", i+1, args[i])
+				}
+			}
+			if len(used) != len(args) {
+				t.Errorf("query uses %d placeholders for %d args", len(used), len(args))
+			}
+
+			tenderRule := "(t.status = $"
+			if got := strings.Contains(query, "FROM tenders t") && strings.Contains(query, tenderRule); got != tt.tenders {
+				t.Errorf("tender subquery with visibility rule = %v, want %v", got, tt.tenders)
+			}
+			if got := strings.Contains(query, "FROM bids b") && strings.Contains(query, "b.creator_username = $2"); got != tt.bids {
+				t.Errorf("bid subquery with visibility rule = %v, want %v", got, tt.bids)
+			}
+			if tt.tenders && tt.bids && !strings.Contains(query, "UNION ALL") {
+				t.Errorf("subqueries are not combined: %s", query)
+			}
+			for _, c := range tt.contain {
+				if !strings.Contains(query, c) {
+					t.Errorf("query does not contain %q", c)
+				}
+			}
+		})
+	}
+}
+
+// Фильтр статуса не заменяет правило видимости: неопубликованные тендеры
+// по-прежнему требуют ответственного за организацию
+func TestSearchQueryStatusFilterKeepsVisibility(t *testing.T) {
+	status := string(model.Created)
+	query, _ := searchQuery(model.SearchParams{Query: "x", Status: &status}, "", 5, 0)
+	if n := strings.Count(query, "FROM organization_responsible r"); n != 3 {
+		t.Fatalf("got %d responsibility checks, want 3 (tender, bid author, tender organization): %s", n, query)
+	}
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-tenders/model"
+)
+
+// Демо-данные для локального запуска и ручной проверки API
+var (
+	seedEmployees = []struct{ Username, FirstName, LastName string }{
+		{"user1", "Иван", "Петров"},
+		{"user2", "Анна", "Смирнова"},
+		{"user3", "Олег", "Кузнецов"},
+	}
+	seedOrganizations = []struct {
+		Name, Description, Type string
+		Responsible             []string
+	}{
+		{"ООО Стройресурс", "Строительство и ремонт", "LLC", []string{"user1"}},
+		{"АО ГрузТранс", "Грузоперевозки по России", "JSC", []string{"user2"}},
+		{"ИП Кузнецов", "Мелкосерийное производство", "IE", []string{"user3", "user1"}},
+	}
+	seedTenders = []struct {
+		Organization, Creator, Name, Description string
+		ServiceType                              model.TenderServiceType
+	}{
+		{"ООО Стройресурс", "user1", "Ремонт офиса", "Косметический ремонт офиса 120 м²", model.Construction},
+		{"ООО Стройресурс", "user1", "Доставка материалов", "Доставка стройматериалов на объект", model.Delivery},
+		{"АО ГрузТранс", "user2", "Обслуживание автопарка", "Ремонт и ТО грузовиков", model.Manufacture},
+	}
+)
+
+// SeedResult сколько записей добавил Seed
+type SeedResult struct {
+	Employees     int
+	Organizations int
+	Tenders       int
+}
+
+// Seed загружает демо-сотрудников, организации и тендеры в одной транзакции.
+// Уже существующие записи (по username, названию организации и тендера) не дублируются,
+// поэтому команду можно запускать повторно.
+func (s *PostgresStorage) Seed(ctx context.Context) (SeedResult, error) {
+	var result SeedResult
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		result = SeedResult{}
+		for _, e := range seedEmployees {
+			res, err := tx.ExecContext(ctx, `
+                INSERT INTO employee (username, first_name, last_name)
+                VALUES ($1, $2, $3)
+                ON CONFLICT (username) DO NOTHING
+            `, e.Username, e.FirstName, e.LastName)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			result.Employees += int(n)
+		}
+
+		organizations := make(map[string]string, len(seedOrganizations))
+		for _, o := range seedOrganizations {
+			var id string
+			err := tx.QueryRowContext(ctx, `SELECT id::text FROM organization WHERE name = $1 ORDER BY id LIMIT 1`, o.Name).Scan(&id)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = tx.QueryRowContext(ctx, `
+                    INSERT INTO organization (name, description, type)
+                    VALUES ($1, $2, $3)
+                    RETURNING id::text
+                `, o.Name, o.Description, o.Type).Scan(&id)
+				result.Organizations++
+			}
+			if err != nil {
+				return err
+			}
+			organizations[o.Name] = id
+
+			for _, username := range o.Responsible {
+				if _, err := tx.ExecContext(ctx, `
+                    INSERT INTO organization_responsible (organization_id, user_id)
+                    SELECT $1::int, e.id
+                    FROM employee e
+                    WHERE e.username = $2
+                      AND NOT EXISTS (
+                          SELECT 1 FROM organization_responsible r
+                          WHERE r.organization_id = $1::int AND r.user_id = e.id
+                      )
+                `, id, username); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, t := range seedTenders {
+			organizationId := organizations[t.Organization]
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `
+                SELECT EXISTS (SELECT 1 FROM tenders WHERE organization_id::text = $1 AND name = $2)
+            `, organizationId, t.Name).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			if _, err := createTender(ctx, tx, model.TendersNewBody{
+				Name:            t.Name,
+				Description:     t.Description,
+				ServiceType:     t.ServiceType,
+				OrganizationId:  organizationId,
+				CreatorUsername: t.Creator,
+			}); err != nil {
+				return err
+			}
+			result.Tenders++
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+
+	"go-tenders/model"
+)
+
+// CountTendersByStatus число тендеров в каждом статусе
+func (s *PostgresStorage) CountTendersByStatus(ctx context.Context) (map[model.TenderStatus]int, error) {
+	counts := make(map[model.TenderStatus]int)
+	err := s.countByStatus(ctx, `SELECT status, COUNT(*) FROM tenders GROUP BY status`, func(status string, n int) {
+		counts[model.TenderStatus(status)] = n
+	})
+	return counts, err
+}
+
+// CountBidsByStatus число предложений в каждом статусе
+func (s *PostgresStorage) CountBidsByStatus(ctx context.Context) (map[model.BidStatus]int, error) {
+	counts := make(map[model.BidStatus]int)
+	err := s.countByStatus(ctx, `SELECT status, COUNT(*) FROM bids GROUP BY status`, func(status string, n int) {
+		counts[model.BidStatus(status)] = n
+	})
+	return counts, err
+}
+
+func (s *PostgresStorage) countByStatus(ctx context.Context, query string, add func(status string, n int)) error {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return err
+		}
+		add(status, n)
+	}
+	return rows.Err()
+}
@@ -2,66 +2,133 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"go-tenders/model"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	"github.com/labstack/echo/v4"
 )
 
 type Storage interface {
-	GetUserBids(ctx echo.Context, params model.GetUserBidsParams) error
-
 	// Создание нового предложения (POST /bids/new)
-	CreateBid(ctx echo.Context, bid model.Bid) error
+	CreateBid(ctx context.Context, body model.BidsNewBody) (model.Bid, error)
 
 	// Редактирование параметров предложения (PATCH /bids/{bidId}/edit)
-	EditBid(ctx echo.Context, bidId model.BidId, params model.EditBidParams) error
+	EditBid(ctx context.Context, bidId model.BidId, params model.EditBidParams, body model.BidIdEditBody) (model.Bid, error)
 
 	// Отправка отзыва по предложению (PUT /bids/{bidId}/feedback)
-	SubmitBidFeedback(ctx echo.Context, bidId model.BidId, params model.SubmitBidFeedbackParams) error
+	SubmitBidFeedback(ctx context.Context, bidId model.BidId, params model.SubmitBidFeedbackParams) error
 
 	// Откат версии предложения (PUT /bids/{bidId}/rollback/{version})
-	RollbackBid(ctx echo.Context, bidId model.BidId, version int32, params model.RollbackBidParams) error
+	RollbackBid(ctx context.Context, bidId model.BidId, version int32, params model.RollbackBidParams) error
 
 	// Получение текущего статуса предложения (GET /bids/{bidId}/status)
-	GetBidStatus(ctx echo.Context, bidId model.BidId, params model.GetBidStatusParams) error
+	GetBidStatus(ctx context.Context, bidId model.BidId) (*model.BidStatus, error)
 
 	// Изменение статуса предложения (PUT /bids/{bidId}/status)
-	UpdateBidStatus(ctx echo.Context, bidId model.BidId, params model.UpdateBidStatusParams) error
+	UpdateBidStatus(ctx context.Context, bidId model.BidId, params model.UpdateBidStatusParams) error
 
 	// Отправка решения по предложению (PUT /bids/{bidId}/submit_decision)
-	SubmitBidDecision(ctx echo.Context, bidId model.BidId, params model.SubmitBidDecisionParams) error
-
-	// Получение списка предложений для тендера (GET /bids/{tenderId}/list)
-	GetBidsForTender(ctx echo.Context, tenderId model.TenderId, params model.GetBidsForTenderParams) error
+	SubmitBidDecision(ctx context.Context, bidId model.BidId, params model.SubmitBidDecisionParams) error
 
 	// Просмотр отзывов на прошлые предложения (GET /bids/{tenderId}/reviews)
-	GetBidReviews(ctx echo.Context, tenderId model.TenderId, params model.GetBidReviewsParams) error
-
-	// Проверка доступности сервера (GET /ping)
-	CheckServer(ctx echo.Context) error
-
-	// Получение списка тендеров (GET /tenders)
-	GetTenders(ctx echo.Context, params model.GetTendersParams) error
+	GetBidReviews(ctx context.Context, tenderId, authorUsername string, limit, offset int) ([]model.BidReview, error)
 
 	// Получить тендеры пользователя (GET /tenders/my)
-	GetUserTenders(ctx echo.Context, params model.GetUserTendersParams) error
+	GetUserTenders(ctx context.Context, username string, limit, offset int) ([]model.Tender, error)
 
 	// Создание нового тендера (POST /tenders/new)
-	CreateTender(ctx echo.Context) error
+	CreateTender(ctx context.Context, body model.TendersNewBody) (model.Tender, error)
 
 	// Редактирование тендера (PATCH /tenders/{tenderId}/edit)
-	EditTender(ctx echo.Context, tenderId model.TenderId, params model.EditTenderParams) error
+	EditTender(ctx context.Context, tenderId model.TenderId, params model.EditTenderParams, body model.TenderIdEditBody) (model.Tender, error)
 
 	// Откат версии тендера (PUT /tenders/{tenderId}/rollback/{version})
-	RollbackTender(ctx echo.Context, tenderId model.TenderId, version int32, params model.RollbackTenderParams) error
+	RollbackTender(ctx context.Context, tenderId model.TenderId, version int32, params model.RollbackTenderParams) error
 
 	// Получение текущего статуса тендера (GET /tenders/{tenderId}/status)
-	GetTenderStatus(ctx echo.Context, tenderId model.TenderId, params model.GetTenderStatusParams) error
+	GetTenderStatus(ctx context.Context, tenderId model.TenderId, params model.GetTenderStatusParams) (*model.TenderStatus, error)
 
 	// Изменение статуса тендера (PUT /tenders/{tenderId}/status)
-	UpdateTenderStatus(ctx echo.Context, tenderId model.TenderId, params model.UpdateTenderStatusParams) error
+	UpdateTenderStatus(ctx context.Context, tenderId model.TenderId, params model.UpdateTenderStatusParams) (model.Tender, error)
+
+	// Полнотекстовый поиск по тендерам и предложениям (GET /search)
+	Search(ctx context.Context, params model.SearchParams, username string, limit, offset int) ([]model.SearchResult, error)
+
+	// Keyset-пагинация списков по курсору (created_at, id)
+	ListTenders(ctx context.Context, q ListQuery, username string, page Page) ([]model.Tender, PageInfo, error)
+	ListUserTenders(ctx context.Context, username string, page Page) ([]model.Tender, PageInfo, error)
+	ListUserBids(ctx context.Context, username string, page Page) ([]model.Bid, PageInfo, error)
+	ListBidsForTender(ctx context.Context, tenderId string, page Page) ([]model.Bid, PageInfo, error)
+	ListBidReviews(ctx context.Context, tenderId, authorUsername string, page Page) ([]model.BidReview, PageInfo, error)
+
+	// Списки с сортировкой и фильтрацией по белому списку полей (ListSchema)
+	QueryTenders(ctx context.Context, q ListQuery, username string, limit, offset int) ([]model.Tender, error)
+	QueryBidsForTender(ctx context.Context, tenderId string, q ListQuery, username string, limit, offset int) ([]model.Bid, error)
+	QueryUserBids(ctx context.Context, username string, q ListQuery, limit, offset int) ([]model.Bid, error)
+
+	// Проверка, что пользователь ответственный за организацию
+	IsOrganizationResponsible(ctx context.Context, username string, organizationId string) (bool, error)
+
+	// Подписки на вебхуки организаций (/webhooks)
+	CreateWebhook(ctx context.Context, webhook model.Webhook, createdBy string) (model.Webhook, error)
+	GetWebhook(ctx context.Context, webhookId string) (model.Webhook, error)
+	ListWebhooks(ctx context.Context, organizationId string) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookId string) error
+	ListWebhookDeliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error)
+
+	// Организации, за которые отвечает пользователь
+	ResponsibleOrganizations(ctx context.Context, username string) ([]string, error)
+
+	// Чтение outbox для потоковых эндпоинтов (/events/stream)
+	EventsAfter(ctx context.Context, afterId int64, limit int) ([]model.StreamEvent, error)
+	LatestEventId(ctx context.Context) (int64, error)
+
+	// Доска предложений тендера (/tenders/{tenderId}/bids/ws)
+	TenderOrganization(ctx context.Context, tenderId string) (string, error)
+	TenderOrganizationStatus(ctx context.Context, tenderId string) (string, model.TenderStatus, error)
+	BidTenderOrganization(ctx context.Context, bidId string) (string, error)
+	BidRanking(ctx context.Context, tenderId string) ([]model.BidRank, error)
+
+	// Настройки email-уведомлений (/notifications/preferences)
+	GetNotificationPreferences(ctx context.Context, username string) (model.NotificationPreferences, error)
+	SaveNotificationPreferences(ctx context.Context, prefs model.NotificationPreferences) error
+
+	// Подписки на ежедневную сводку тендеров (/digests/subscriptions)
+	CreateDigestSubscription(ctx context.Context, sub model.DigestSubscription) (model.DigestSubscription, error)
+	ListDigestSubscriptions(ctx context.Context, username string) ([]model.DigestSubscription, error)
+	DeleteDigestSubscription(ctx context.Context, subscriptionId string, username string) error
+
+	// Журнал аудита (/audit)
+	GetAuditLog(ctx context.Context, params model.AuditParams, limit, offset int) ([]model.AuditEntry, error)
+	VerifyAuditChain(ctx context.Context) (model.AuditVerification, error)
+
+	// Бизнес-показатели для /metrics
+	CountTendersByStatus(ctx context.Context) (map[model.TenderStatus]int, error)
+	CountBidsByStatus(ctx context.Context) (map[model.BidStatus]int, error)
+
+	// API-токены сотрудников (Authorization: Bearer)
+	APITokenUsername(ctx context.Context, token string) (string, error)
+
+	// Проверки готовности (/health/ready)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version int, tracked bool, err error)
+
+	// Ключи идемпотентности (POST /tenders/new, POST /bids/new)
+	BeginIdempotentRequest(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (model.IdempotencyRecord, bool, error)
+	CompleteIdempotentRequest(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotentRequest(ctx context.Context, scope, key string) error
+
+	// Потоковая выгрузка списков в CSV/XLSX (/export)
+	ExportTenders(ctx context.Context, q ListQuery, username string, fn func(model.Tender) error) error
+	ExportUserTenders(ctx context.Context, username string, fn func(model.Tender) error) error
+	ExportBidsForTender(ctx context.Context, tenderId string, q ListQuery, username string, fn func(model.Bid) error) error
+
+	// Массовый импорт тендеров (POST /tenders/import)
+	ImportTenders(ctx context.Context, bodies []model.TendersNewBody, allOrNothing, rollback bool) ([]TenderImportResult, bool, error)
 }
 
 type PostgresStorage struct {
@@ -72,94 +139,153 @@ func NewPostgresStorage(db *sqlx.DB) *PostgresStorage {
 	return &PostgresStorage{db: db}
 }
 
-func (s *PostgresStorage) GetUserBids(ctx context.Context, userId string, limit, offset int) ([]model.Bid, error) {
-	query := `
-        SELECT id, title, description, created_at, updated_at
-        FROM bids
-        WHERE user_id = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3
-    `
-	rows, err := s.db.QueryContext(ctx, query, userId, limit, offset)
-	if err != nil {
-		return nil, err
+// ErrTenderNotPublished предложение подается только на опубликованный тендер
+var ErrTenderNotPublished = errors.New("tender is not published")
+
+// CreateBid сохраняет новое предложение от имени организации, а без нее — от
+// имени пользователя creatorUsername. Идентификатор, дата создания, версия 1
+// и статус Created назначаются сервером; возвращается сохраненная запись.
+// Тендер должен быть опубликован; его строка блокируется до конца транзакции,
+// чтобы тендер не закрыли одновременно с подачей предложения.
+func (s *PostgresStorage) CreateBid(ctx context.Context, body model.BidsNewBody) (model.Bid, error) {
+	authorType, authorId := model.Organization, body.OrganizationId
+	if body.OrganizationId == "" {
+		authorType, authorId = model.User, body.CreatorUsername
 	}
-	defer rows.Close()
 
-	var bids []model.Bid
-	for rows.Next() {
-		var b model.Bid
-		if err := rows.Scan(&b.Id, &b.AuthorId, &b.Description, &b.CreatedAt); err != nil {
-			return nil, err
+	var bid model.Bid
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var status model.TenderStatus
+		err := tx.QueryRowContext(ctx, `SELECT status FROM tenders WHERE id::text = $1 FOR SHARE`, body.TenderId).Scan(&status)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && status != model.Published) {
+			return ErrTenderNotPublished
+		}
+		if err != nil {
+			return err
 		}
-		bids = append(bids, b)
-	}
-	return bids, rows.Err()
-}
 
-func (s *PostgresStorage) CreateBid(ctx context.Context, bid model.Bid) error {
-	query := `INSERT INTO bids (id, name, description) VALUES ($1, $2, $3)`
-	_, err := s.db.ExecContext(ctx, query, bid.Id, bid.Name, bid.Description)
-	return err
+		bid, err = scanBidRow(tx.QueryRowContext(ctx, `
+        INSERT INTO bids AS b (id, name, description, status, tender_id, author_type, author_id,
+                               creator_username, version, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 1, NOW())
+        RETURNING `+bidColumns,
+			uuid.NewString(), body.Name, body.Description, model.BidStatusCreated, body.TenderId,
+			authorType, authorId, body.CreatorUsername))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidCreate, model.AuditEntityBid, bid.Id, body.CreatorUsername, nil); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventBidCreated, bid.Id, bid)
+	})
+	return bid, err
 }
 
-func (s *PostgresStorage) EditBid(ctx context.Context, bidId string, params model.EditBidParams) error {
-	query := `
-        UPDATE bids
-        SET title = $1,
-            description = $2
+func (s *PostgresStorage) EditBid(ctx context.Context, bidId string, params model.EditBidParams, body model.BidIdEditBody) (model.Bid, error) {
+	var bid model.Bid
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		before, err := entityVersion(ctx, tx, model.AuditEntityBid, bidId)
+		if err != nil {
+			return err
+		}
+		if err := archiveBid(ctx, tx, bidId); err != nil {
+			return err
+		}
+		query := `
+        UPDATE bids b
+        SET name = COALESCE($1, name),
+            description = COALESCE($2, description),
+            version = version + 1,
+            updated_at = NOW()
         WHERE id = $3
-    `
-	_, err := s.db.ExecContext(ctx, query, params.Username, bidId)
-	return err
+        RETURNING ` + bidColumns
+		bid, err = updateBidReturning(ctx, tx, query, body.Name, body.Description, bidId)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidEdit, model.AuditEntityBid, bid.Id, params.Username, before); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventBidEdited, bid.Id, bid)
+	})
+	return bid, err
 }
 
+// SubmitBidFeedback сохраняет отзыв на предложение в bid_reviews, откуда его
+// читает GetBidReviews; каждый отзыв — отдельная запись
 func (s *PostgresStorage) SubmitBidFeedback(ctx context.Context, bidId string, params model.SubmitBidFeedbackParams) error {
-	query := `
-        INSERT INTO bid_feedback (bid_id, feedback_text, rating, created_at, updated_at)
-        VALUES ($1, $2, $3, NOW(), NOW())
-        ON CONFLICT (bid_id) DO UPDATE SET
-            feedback_text = EXCLUDED.feedback_text,
-            rating = EXCLUDED.rating,
-            updated_at = NOW()
-    `
-	_, err := s.db.ExecContext(ctx, query, bidId, params.BidFeedback, params.Username)
-	return err
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		version, err := entityVersion(ctx, tx, model.AuditEntityBid, bidId)
+		if err != nil {
+			return err
+		}
+		if version == nil {
+			return sql.ErrNoRows
+		}
+		_, err = tx.ExecContext(ctx, `
+        INSERT INTO bid_reviews (bid_id, username, description)
+        VALUES ($1, $2, $3)
+    `, bidId, params.Username, params.BidFeedback)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidFeedback, model.AuditEntityBid, bidId, params.Username, version); err != nil {
+			return err
+		}
+
+		event := model.BidFeedbackEvent{BidId: bidId, BidFeedback: params.BidFeedback, Username: params.Username}
+		if err := tx.QueryRowContext(ctx, `SELECT tender_id FROM bids WHERE id = $1`, bidId).Scan(&event.TenderId); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventBidFeedbackSubmitted, bidId, event)
+	})
 }
 
+// RollbackBid восстанавливает параметры предложения из версии version.
+// Откат считается новой правкой: текущая версия сохраняется в историю,
+// номер версии увеличивается.
 func (s *PostgresStorage) RollbackBid(ctx context.Context, bidId string, version int32, params model.RollbackBidParams) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-
-	// Копируем текущую версию предложения в историю
-	_, err = tx.ExecContext(ctx, `
-        INSERT INTO bid_history (bid_id, data, version)
-        SELECT id, data, version FROM bids WHERE id = $1
-    `, bidId)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		before, err := entityVersion(ctx, tx, model.AuditEntityBid, bidId)
+		if err != nil {
+			return err
+		}
+		if err := archiveBid(ctx, tx, bidId); err != nil {
+			return err
+		}
 
-	// Восстанавливаем выбранную версию из истории
-	_, err = tx.ExecContext(ctx, `
-        UPDATE bids
-        SET data = (SELECT data FROM bid_history WHERE bid_id = $1 AND version = $2),
-            version = $2
-        WHERE id = $1
-    `, bidId, version)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+		query := `
+        UPDATE bids b
+        SET name = h.name,
+            description = h.description,
+            version = b.version + 1,
+            updated_at = NOW()
+        FROM bid_history h
+        WHERE b.id = $1 AND h.bid_id = b.id AND h.version = $2
+        RETURNING ` + bidColumns
+		bid, err := updateBidReturning(ctx, tx, query, bidId, version)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidRollback, model.AuditEntityBid, bidId, params.Username, before); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventBidEdited, bid.Id, bid)
+	})
+}
 
-	return tx.Commit()
+// archiveBid сохраняет текущую версию параметров предложения в историю перед изменением
+func archiveBid(ctx context.Context, tx *sql.Tx, bidId string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO bid_history (bid_id, version, name, description)
+        SELECT id, version, name, description FROM bids WHERE id = $1
+    `, bidId)
+	return err
 }
 
 func (s *PostgresStorage) GetBidStatus(ctx context.Context, bidId string) (*model.BidStatus, error) {
-	query := `SELECT status, updated_at FROM bids WHERE id = $1`
+	query := `SELECT status FROM bids WHERE id = $1`
 	var status model.BidStatus
 	err := s.db.QueryRowContext(ctx, query, bidId).Scan(&status)
 	if err != nil {
@@ -169,95 +295,208 @@ func (s *PostgresStorage) GetBidStatus(ctx context.Context, bidId string) (*mode
 }
 
 func (s *PostgresStorage) UpdateBidStatus(ctx context.Context, bidId string, params model.UpdateBidStatusParams) error {
-	query := `
-        UPDATE bids
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		query := `
+        UPDATE bids b
         SET status = $1,
             updated_at = NOW()
         WHERE id = $2
-    `
-	_, err := s.db.ExecContext(ctx, query, params.Status, bidId)
-	return err
+        RETURNING ` + bidColumns
+		before, err := entityVersion(ctx, tx, model.AuditEntityBid, bidId)
+		if err != nil {
+			return err
+		}
+		bid, err := updateBidReturning(ctx, tx, query, params.Status, bidId)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidStatus, model.AuditEntityBid, bid.Id, params.Username, before); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventBidStatusChanged, bid.Id, bid)
+	})
+}
+
+// updateBidReturning выполняет UPDATE ... RETURNING bidColumns и возвращает измененное предложение
+func updateBidReturning(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (model.Bid, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return model.Bid{}, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return model.Bid{}, err
+		}
+		return model.Bid{}, sql.ErrNoRows
+	}
+	bid, _, err := scanBid(rows)
+	return bid, err
+}
+
+// ErrBidNotPublished решение принимается только по опубликованному предложению
+var ErrBidNotPublished = errors.New("bid is not published")
+
+// decisionQuorum наибольшее число согласований, нужное для принятия предложения
+const decisionQuorum = 3
+
+// decisionOutcome итоговый статус предложения по голосам ответственных: любое
+// отклонение отклоняет, согласование требует min(3, число ответственных) голосов.
+// Пока кворума нет, предложение остается опубликованным.
+func decisionOutcome(approvals, rejections, responsibles int) model.BidStatus {
+	if rejections > 0 {
+		return model.BidStatusRejected
+	}
+	quorum := decisionQuorum
+	if responsibles < quorum {
+		quorum = responsibles
+	}
+	if quorum > 0 && approvals >= quorum {
+		return model.BidStatusApproved
+	}
+	return model.BidStatusPublished
 }
 
+// SubmitBidDecision записывает голос ответственного и, если набран кворум, меняет
+// статус предложения; согласование закрывает тендер
 func (s *PostgresStorage) SubmitBidDecision(ctx context.Context, bidId string, params model.SubmitBidDecisionParams) error {
-	query := `
-        INSERT INTO bid_decisions (bid_id, decision, decided_at)
-        VALUES ($1, $2, NOW())
-        ON CONFLICT (bid_id) DO UPDATE SET
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		var status model.BidStatus
+		event := model.BidDecisionEvent{BidId: bidId, Decision: params.Decision, Username: params.Username}
+		if err := tx.QueryRowContext(ctx, `SELECT status, tender_id FROM bids WHERE id = $1 FOR UPDATE`, bidId).
+			Scan(&status, &event.TenderId); err != nil {
+			return err
+		}
+		if status != model.BidStatusPublished {
+			return ErrBidNotPublished
+		}
+
+		query := `
+        INSERT INTO bid_decisions (bid_id, username, decision, decided_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (bid_id, username) DO UPDATE SET
             decision = EXCLUDED.decision,
             decided_at = NOW()
     `
-	_, err := s.db.ExecContext(ctx, query, bidId, params.Decision)
-	return err
+		if _, err := tx.ExecContext(ctx, query, bidId, params.Username, params.Decision); err != nil {
+			return err
+		}
+		version, err := entityVersion(ctx, tx, model.AuditEntityBid, bidId)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidDecision, model.AuditEntityBid, bidId, params.Username, version); err != nil {
+			return err
+		}
+
+		var approvals, rejections, responsibles int
+		if err := tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FILTER (WHERE d.decision = 'Approved'),
+               COUNT(*) FILTER (WHERE d.decision = 'Rejected'),
+               (SELECT COUNT(*) FROM organization_responsible r
+                JOIN tenders t ON r.organization_id::text = t.organization_id::text
+                WHERE t.id = $2)
+        FROM bid_decisions d
+        WHERE d.bid_id = $1
+    `, bidId, event.TenderId).Scan(&approvals, &rejections, &responsibles); err != nil {
+			return err
+		}
+		event.BidStatus = decisionOutcome(approvals, rejections, responsibles)
+		if err := insertEvent(ctx, tx, model.EventBidDecisionSubmitted, bidId, event); err != nil {
+			return err
+		}
+		if event.BidStatus == model.BidStatusPublished {
+			return nil
+		}
+
+		bid, err := updateBidReturning(ctx, tx, `
+        UPDATE bids b
+        SET status = $1,
+            updated_at = NOW()
+        WHERE id = $2
+        RETURNING `+bidColumns, event.BidStatus, bidId)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditBidStatus, model.AuditEntityBid, bid.Id, params.Username, version); err != nil {
+			return err
+		}
+		if err := insertEvent(ctx, tx, model.EventBidStatusChanged, bid.Id, bid); err != nil {
+			return err
+		}
+		if bid.Status != model.BidStatusApproved {
+			return nil
+		}
+		return closeTender(ctx, tx, bid.TenderId, params.Username)
+	})
 }
 
-func (s *PostgresStorage) GetBidsForTender(ctx context.Context, tenderId string, limit, offset int) ([]model.Bid, error) {
-	query := `
-        SELECT id, title, description, amount, user_id, created_at, updated_at
-        FROM bids
-        WHERE tender_id = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3
-    `
-	rows, err := s.db.QueryContext(ctx, query, tenderId, limit, offset)
+// closeTender закрывает тендер после согласования предложения
+func closeTender(ctx context.Context, tx *sql.Tx, tenderId, username string) error {
+	before, err := entityVersion(ctx, tx, model.AuditEntityTender, tenderId)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var bids []model.Bid
-	for rows.Next() {
-		var b model.Bid
-		if err := rows.Scan(&b.Id, &b.Name, &b.Description, &b.Description, &b.AuthorId, &b.CreatedAt); err != nil {
-			return nil, err
-		}
-		bids = append(bids, b)
+	tender, err := scanTenderRow(tx.QueryRowContext(ctx, `
+        UPDATE tenders t
+        SET status = $1,
+            updated_at = NOW()
+        WHERE id = $2
+        RETURNING `+tenderColumns, model.Closed, tenderId))
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, model.AuditTenderStatus, model.AuditEntityTender, tender.Id, username, before); err != nil {
+		return err
 	}
-	return bids, rows.Err()
+	return insertEvent(ctx, tx, model.EventTenderClosed, tender.Id, tender)
 }
 
-func (s *PostgresStorage) GetBidReviews(ctx context.Context, tenderId string, limit, offset int) ([]model.BidReview, error) {
+// GetBidReviews возвращает отзывы на предложения автора, который подавал
+// предложение на указанный тендер
+func (s *PostgresStorage) GetBidReviews(ctx context.Context, tenderId, authorUsername string, limit, offset int) ([]model.BidReview, error) {
 	query := `
-        SELECT id, bid_id, review_text, rating, created_at, updated_at
-        FROM bid_reviews
-        WHERE tender_id = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3
+        SELECT r.id, r.description, r.created_at
+        FROM bid_reviews r
+        JOIN bids b ON b.id = r.bid_id
+        WHERE b.creator_username = $2
+          AND EXISTS (SELECT 1 FROM bids tb WHERE tb.tender_id = $1 AND tb.creator_username = $2)
+        ORDER BY r.created_at DESC, r.id DESC
+        LIMIT $3 OFFSET $4
     `
-	rows, err := s.db.QueryContext(ctx, query, tenderId, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, tenderId, authorUsername, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reviews []model.BidReview
+	reviews := []model.BidReview{}
 	for rows.Next() {
 		var r model.BidReview
-		if err := rows.Scan(&r.Id, &r.Id, &r.Description, &r.CreatedAt); err != nil {
+		var createdAt time.Time
+		if err := rows.Scan(&r.Id, &r.Description, &createdAt); err != nil {
 			return nil, err
 		}
+		r.CreatedAt = createdAt.Format(time.RFC3339)
 		reviews = append(reviews, r)
 	}
 	return reviews, rows.Err()
 }
 
-func (s *PostgresStorage) GetTenders(ctx context.Context, limit, offset int) ([]model.Tender, error) {
-	query := `
-        SELECT id, title, description, created_at, updated_at
-        FROM tenders
-        ORDER BY created_at DESC
-        LIMIT $1 OFFSET $2
-    `
-	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+// GetUserTenders возвращает тендеры, созданные пользователем, по алфавиту
+func (s *PostgresStorage) GetUserTenders(ctx context.Context, username string, limit, offset int) ([]model.Tender, error) {
+	query, args := userTendersQuery(username)
+	query, args = paged(query, args, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tenders []model.Tender
+	tenders := []model.Tender{}
 	for rows.Next() {
-		var t model.Tender
-		if err := rows.Scan(&t.Id, &t.Name, &t.Description, &t.CreatedAt); err != nil {
+		t, err := scanTenderRow(rows)
+		if err != nil {
 			return nil, err
 		}
 		tenders = append(tenders, t)
@@ -265,88 +504,103 @@ func (s *PostgresStorage) GetTenders(ctx context.Context, limit, offset int) ([]
 	return tenders, rows.Err()
 }
 
-func (s *PostgresStorage) GetUserTenders(ctx context.Context, userId string, limit, offset int) ([]model.Tender, error) {
-	query := `
-        SELECT id, title, description, created_at, updated_at
-        FROM tenders
-        WHERE user_id = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3
-    `
-	rows, err := s.db.QueryContext(ctx, query, userId, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var tenders []model.Tender
-	for rows.Next() {
-		var t model.Tender
-		if err := rows.Scan(&t.Id, &t.Name, &t.Description, &t.CreatedAt); err != nil {
-			return nil, err
-		}
-		tenders = append(tenders, t)
-	}
-	return tenders, rows.Err()
+// CreateTender сохраняет новый тендер. Идентификатор, дата создания, версия 1
+// и статус Created назначаются сервером; возвращается сохраненная запись.
+func (s *PostgresStorage) CreateTender(ctx context.Context, body model.TendersNewBody) (model.Tender, error) {
+	var tender model.Tender
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		tender, err = createTender(ctx, tx, body)
+		return err
+	})
+	return tender, err
 }
 
-func (s *PostgresStorage) CreateTender(ctx context.Context, tender model.Tender) error {
-	query := `
-        INSERT INTO tenders (id, title, description, created_at, updated_at)
-        VALUES ($1, $2, $3, NOW(), NOW())
-    `
-	_, err := s.db.ExecContext(ctx, query, tender.Id, tender.Name, tender.Description)
-	return err
+// createTender вставляет тендер в открытой транзакции
+func createTender(ctx context.Context, tx *sql.Tx, body model.TendersNewBody) (model.Tender, error) {
+	tender, err := scanTenderRow(tx.QueryRowContext(ctx, `
+        INSERT INTO tenders AS t (id, name, description, service_type, status, organization_id,
+                                  creator_username, version, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, 1, NOW())
+        RETURNING `+tenderColumns,
+		uuid.NewString(), body.Name, body.Description, body.ServiceType, model.Created,
+		body.OrganizationId, body.CreatorUsername))
+	if err != nil {
+		return tender, err
+	}
+	return tender, recordAudit(ctx, tx, model.AuditTenderCreate, model.AuditEntityTender, tender.Id, body.CreatorUsername, nil)
 }
 
-func (s *PostgresStorage) EditTender(ctx context.Context, tenderId string, params model.EditTenderParams) error {
-	query := `
-        UPDATE tenders
-        SET Username = $1,
-            description = $2,
+// EditTender меняет переданные поля тендера и увеличивает его версию
+func (s *PostgresStorage) EditTender(ctx context.Context, tenderId string, params model.EditTenderParams, body model.TenderIdEditBody) (model.Tender, error) {
+	var tender model.Tender
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		before, err := entityVersion(ctx, tx, model.AuditEntityTender, tenderId)
+		if err != nil {
+			return err
+		}
+		if err := archiveTender(ctx, tx, tenderId); err != nil {
+			return err
+		}
+		tender, err = scanTenderRow(tx.QueryRowContext(ctx, `
+        UPDATE tenders t
+        SET name = COALESCE($1, name),
+            description = COALESCE($2, description),
+            service_type = COALESCE($3, service_type),
+            version = version + 1,
             updated_at = NOW()
-        WHERE id = $3
-    `
-	_, err := s.db.ExecContext(ctx, query, params.Username, params.Description, tenderId)
-	return err
+        WHERE id = $4
+        RETURNING `+tenderColumns, body.Name, body.Description, body.ServiceType, tenderId))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditTenderEdit, model.AuditEntityTender, tenderId, params.Username, before); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventTenderEdited, tender.Id, tender)
+	})
+	return tender, err
 }
 
+// RollbackTender восстанавливает параметры тендера из версии version.
+// Как и для предложений, откат — новая правка с увеличением версии.
 func (s *PostgresStorage) RollbackTender(ctx context.Context, tenderId string, version int32, params model.RollbackTenderParams) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		before, err := entityVersion(ctx, tx, model.AuditEntityTender, tenderId)
+		if err != nil {
+			return err
+		}
+		if err := archiveTender(ctx, tx, tenderId); err != nil {
+			return err
 		}
-	}()
-
-	_, err = tx.ExecContext(ctx, `
-        INSERT INTO tenders_history (tender_id, version, data, archived_at)
-        SELECT id, version, row_to_json(tenders.*), NOW()
-        FROM tenders WHERE id = $1`, tenderId)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
 
-	_, err = tx.ExecContext(ctx, `
+		tender, err := scanTenderRow(tx.QueryRowContext(ctx, `
         UPDATE tenders t
-        SET data = h.data,
-            version = h.version,
+        SET name = h.name,
+            description = h.description,
+            service_type = h.service_type,
+            version = t.version + 1,
             updated_at = NOW()
         FROM tenders_history h
-        WHERE t.id = $1 AND h.tender_id = $1 AND h.version = $2
-    `, tenderId, version)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+        WHERE t.id = $1 AND h.tender_id = t.id AND h.version = $2
+        RETURNING `+tenderColumns, tenderId, version))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, model.AuditTenderRollback, model.AuditEntityTender, tenderId, params.Username, before); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, model.EventTenderEdited, tender.Id, tender)
+	})
+}
 
-	return tx.Commit()
+// archiveTender сохраняет текущую версию параметров тендера в историю перед изменением
+func archiveTender(ctx context.Context, tx *sql.Tx, tenderId string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO tenders_history (tender_id, version, name, description, service_type)
+        SELECT id, version, name, description, service_type FROM tenders WHERE id = $1
+    `, tenderId)
+	return err
 }
 
 func (s *PostgresStorage) GetTenderStatus(ctx context.Context, tenderId string, params model.GetTenderStatusParams) (*model.TenderStatus, error) {
@@ -359,13 +613,35 @@ func (s *PostgresStorage) GetTenderStatus(ctx context.Context, tenderId string,
 	return &status, nil
 }
 
-func (s *PostgresStorage) UpdateTenderStatus(ctx context.Context, tenderId string, params model.UpdateBidStatusParams) error {
-	query := `
-        UPDATE tenders
+// UpdateTenderStatus меняет статус тендера; публикация и закрытие записываются в outbox
+func (s *PostgresStorage) UpdateTenderStatus(ctx context.Context, tenderId string, params model.UpdateTenderStatusParams) (model.Tender, error) {
+	var tender model.Tender
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		before, err := entityVersion(ctx, tx, model.AuditEntityTender, tenderId)
+		if err != nil {
+			return err
+		}
+		tender, err = scanTenderRow(tx.QueryRowContext(ctx, `
+        UPDATE tenders t
         SET status = $1,
             updated_at = NOW()
         WHERE id = $2
-    `
-	_, err := s.db.ExecContext(ctx, query, params.Status, tenderId)
-	return err
+        RETURNING `+tenderColumns, params.Status, tenderId))
+		if err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, tx, model.AuditTenderStatus, model.AuditEntityTender, tender.Id, params.Username, before); err != nil {
+			return err
+		}
+
+		switch tender.Status {
+		case model.Published:
+			return insertEvent(ctx, tx, model.EventTenderPublished, tender.Id, tender)
+		case model.Closed:
+			return insertEvent(ctx, tx, model.EventTenderClosed, tender.Id, tender)
+		}
+		return nil
+	})
+	return tender, err
 }
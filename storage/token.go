@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"go-tenders/model"
+)
+
+// apiTokenPrefix помогает узнать токен сервиса в логах и сканерах секретов
+const apiTokenPrefix = "tnd_"
+
+// hashAPIToken SHA-256 токена в hex — то, что хранится в api_tokens
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIToken выпускает токен сотруднику username. ttl 0 — бессрочный токен.
+// Если сотрудника нет, возвращается sql.ErrNoRows.
+func (s *PostgresStorage) CreateAPIToken(ctx context.Context, username, name string, ttl time.Duration) (model.APIToken, error) {
+	token := model.APIToken{Username: username, Name: name}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM employee WHERE username = $1)`, username).Scan(&exists); err != nil {
+		return token, err
+	}
+	if !exists {
+		return token, sql.ErrNoRows
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return token, err
+	}
+	token.Token = apiTokenPrefix + hex.EncodeToString(secret)
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+        INSERT INTO api_tokens (username, name, token_hash, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `, username, name, hashAPIToken(token.Token), token.ExpiresAt).Scan(&token.Id, &token.CreatedAt)
+	return token, err
+}
+
+// APITokenUsername возвращает сотрудника, которому выпущен токен. Для
+// неизвестного, просроченного или отозванного токена возвращается sql.ErrNoRows.
+func (s *PostgresStorage) APITokenUsername(ctx context.Context, token string) (string, error) {
+	var username string
+	err := s.db.QueryRowContext(ctx, `
+        SELECT username
+        FROM api_tokens
+        WHERE token_hash = $1
+          AND revoked_at IS NULL
+          AND (expires_at IS NULL OR expires_at > NOW())
+    `, hashAPIToken(token)).Scan(&username)
+	return username, err
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTokenStorage fakeDB с сотрудником alice и таблицей токенов hash → сотрудник
+func newTokenStorage(t *testing.T) (*PostgresStorage, *fakeDB, map[string]string) {
+	s, db := newFakeStorage(t)
+	hashes := make(map[string]string)
+	db.on("FROM employee", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		return [][]driver.Value{{args[0] == "alice"}}, nil
+	}})
+	db.on("INSERT INTO api_tokens", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		hashes[args[2].(string)] = args[0].(string)
+		return [][]driver.Value{{"token-1", time.Now()}}, nil
+	}})
+	db.on("FROM api_tokens", fakeRule{fn: func(args []driver.Value) ([][]driver.Value, error) {
+		username, ok := hashes[args[0].(string)]
+		if !ok {
+			return nil, nil
+		}
+		return [][]driver.Value{{username}}, nil
+	}})
+	return s, db, hashes
+}
+
+// В базе хранится только SHA-256 токена; по токену находится его владелец
+func TestAPITokenHashLookup(t *testing.T) {
+	s, db, hashes := newTokenStorage(t)
+	ctx := context.Background()
+
+	token, err := s.CreateAPIToken(ctx, "alice", "ci", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token.Token, apiTokenPrefix) || len(token.Token) != len(apiTokenPrefix)+64 {
+		t.Fatalf("token = %q, want %s and 32 random bytes in hex", token.Token, apiTokenPrefix)
+	}
+	if token.Id != "token-1" || token.Username != "alice" || token.ExpiresAt != nil {
+		t.Fatalf("token = %+v", token)
+	}
+	sum := sha256.Sum256([]byte(token.Token))
+	if hashes[hex.EncodeToString(sum[:])] != "alice" || len(hashes) != 1 {
+		t.Fatalf("stored hashes = %v, want SHA-256 of the token", hashes)
+	}
+	for _, q := range db.executed("") {
+		for _, arg := range q.Args {
+			if arg == token.Token {
+				t.Fatalf("plain token sent to the database in %q", q.SQL)
+			}
+		}
+	}
+
+	if username, err := s.APITokenUsername(ctx, token.Token); err != nil || username != "alice" {
+		t.Fatalf("APITokenUsername = %q, %v; want alice", username, err)
+	}
+	if _, err := s.APITokenUsername(ctx, token.Token+"0"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("APITokenUsername of unknown token = %v, want sql.ErrNoRows", err)
+	}
+	lookup := db.executed("FROM api_tokens")[0].SQL
+	for _, part := range []string{"revoked_at IS NULL", "expires_at IS NULL OR expires_at > NOW()"} {
+		if !strings.Contains(lookup, part) {
+			t.Errorf("lookup does not check %q", part)
+		}
+	}
+
+	// Токены разные, даже выпущенные одному сотруднику
+	other, err := s.CreateAPIToken(ctx, "alice", "ci", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Token == token.Token || len(hashes) != 2 {
+		t.Fatal("second token repeats the first")
+	}
+}
+
+// ttl задает срок действия; токен неизвестному сотруднику не выпускается
+func TestCreateAPITokenExpiryAndUnknownEmployee(t *testing.T) {
+	s, db, hashes := newTokenStorage(t)
+	ctx := context.Background()
+
+	before := time.Now()
+	token, err := s.CreateAPIToken(ctx, "alice", "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ExpiresAt == nil || token.ExpiresAt.Before(before.Add(time.Hour)) || token.ExpiresAt.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("ExpiresAt = %v, want in an hour", token.ExpiresAt)
+	}
+	if arg := db.executed("INSERT INTO api_tokens")[0].Args[3]; arg != token.ExpiresAt {
+		t.Fatalf("expires_at = %v, want %v", arg, token.ExpiresAt)
+	}
+
+	if _, err := s.CreateAPIToken(ctx, "mallory", "", 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("CreateAPIToken for unknown employee = %v, want sql.ErrNoRows", err)
+	}
+	if len(hashes) != 1 {
+		t.Fatalf("token issued to unknown employee: %v", hashes)
+	}
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"go-tenders/model"
+)
+
+// IsOrganizationResponsible проверяет, что пользователь ответственный за организацию
+func (s *PostgresStorage) IsOrganizationResponsible(ctx context.Context, username string, organizationId string) (bool, error) {
+	var ok bool
+	err := s.db.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1
+            FROM organization_responsible r
+            JOIN employee e ON e.id = r.user_id
+            WHERE e.username = $1 AND r.organization_id::text = $2
+        )
+    `, username, organizationId).Scan(&ok)
+	return ok, err
+}
+
+func scanWebhook(row interface{ Scan(...interface{}) error }) (model.Webhook, error) {
+	var w model.Webhook
+	var events []byte
+	var createdAt time.Time
+	if err := row.Scan(&w.Id, &w.OrganizationId, &w.Url, &events, &createdAt); err != nil {
+		return w, err
+	}
+	w.CreatedAt = createdAt.Format(time.RFC3339)
+	return w, json.Unmarshal(events, &w.Events)
+}
+
+// CreateWebhook сохраняет подписку; секрет генерирует вызывающая сторона
+func (s *PostgresStorage) CreateWebhook(ctx context.Context, webhook model.Webhook, createdBy string) (model.Webhook, error) {
+	if webhook.Events == nil {
+		webhook.Events = []model.EventType{}
+	}
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return webhook, err
+	}
+	created, err := scanWebhook(s.db.QueryRowContext(ctx, `
+        INSERT INTO webhooks (organization_id, url, events, secret, created_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, organization_id, url, events, created_at
+    `, webhook.OrganizationId, webhook.Url, events, webhook.Secret, createdBy))
+	created.Secret = webhook.Secret
+	return created, err
+}
+
+// GetWebhook возвращает подписку без секрета
+func (s *PostgresStorage) GetWebhook(ctx context.Context, webhookId string) (model.Webhook, error) {
+	return scanWebhook(s.db.QueryRowContext(ctx, `
+        SELECT id, organization_id, url, events, created_at
+        FROM webhooks
+        WHERE id::text = $1 AND deleted_at IS NULL
+    `, webhookId))
+}
+
+// ListWebhooks возвращает подписки организации без секретов
+func (s *PostgresStorage) ListWebhooks(ctx context.Context, organizationId string) ([]model.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT id, organization_id, url, events, created_at
+        FROM webhooks
+        WHERE organization_id = $1 AND deleted_at IS NULL
+        ORDER BY created_at, id
+    `, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook отключает подписку; журнал доставок сохраняется
+func (s *PostgresStorage) DeleteWebhook(ctx context.Context, webhookId string) error {
+	res, err := s.db.ExecContext(ctx, `
+        UPDATE webhooks SET deleted_at = NOW()
+        WHERE id::text = $1 AND deleted_at IS NULL
+    `, webhookId)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries ставит событие в очередь доставки всем подходящим подпискам:
+// организации тендера и организации-автору предложения. События еще не опубликованного
+// (Created) предложения организация тендера не получает, как и в списках предложений.
+// Повторная постановка того же события игнорируется.
+func (s *PostgresStorage) EnqueueWebhookDeliveries(ctx context.Context, event model.Event) error {
+	_, err := s.db.ExecContext(ctx, `
+        INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+        SELECT w.id, $1, $2, $3
+        FROM webhooks w
+        WHERE w.deleted_at IS NULL
+          AND (w.events = '[]'::jsonb OR w.events ? $2)
+          AND w.organization_id IN (
+              SELECT t.organization_id::text FROM tenders t
+              WHERE t.id::text = $4
+                AND ($2 NOT LIKE 'bid.%' OR NOT EXISTS (
+                    SELECT 1 FROM bids b WHERE b.id::text = $5 AND b.status = 'Created'
+                ))
+              UNION
+              SELECT b.author_id::text FROM bids b
+              WHERE b.id::text = $5 AND b.author_type = 'Organization'
+          )
+        ON CONFLICT (webhook_id, event_id) DO NOTHING
+    `, event.Id, string(event.Type), []byte(event.Payload), event.TenderId(), event.AggregateId)
+	return err
+}
+
+// ClaimWebhookDeliveries забирает доставки активных подписок, готовые к отправке,
+// и резервирует их на lease. Доставки удаленных подписок остаются в журнале и не отправляются.
+func (s *PostgresStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = NOW() + make_interval(secs => $2)
+        FROM webhooks w
+        WHERE w.id = d.webhook_id AND d.id IN (
+            SELECT dd.id FROM webhook_deliveries dd
+            JOIN webhooks ww ON ww.id = dd.webhook_id
+            WHERE dd.status = 'Pending' AND dd.next_attempt_at <= NOW()
+              AND ww.deleted_at IS NULL
+            ORDER BY dd.id
+            LIMIT $1
+            FOR UPDATE OF dd SKIP LOCKED
+        )
+        RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.status, d.attempts,
+                  d.response_code, COALESCE(d.last_error, ''), d.created_at, d.payload, w.url, w.secret
+    `, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		if err := rows.Scan(&d.Id, &d.WebhookId, &d.EventId, &d.EventType, &d.Status, &d.Attempts,
+			&d.ResponseCode, &d.LastError, &d.CreatedAt, &d.Payload, &d.Url, &d.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt сохраняет результат попытки доставки. Успешная попытка
+// завершает доставку; для неуспешной retryAt задает следующую попытку,
+// nil означает, что попытки исчерпаны.
+func (s *PostgresStorage) RecordWebhookAttempt(ctx context.Context, id int64, responseCode int, deliverErr error, retryAt *time.Time) error {
+	if deliverErr == nil {
+		_, err := s.db.ExecContext(ctx, `
+            UPDATE webhook_deliveries
+            SET status = 'Delivered', attempts = attempts + 1, response_code = $2,
+                last_error = NULL, delivered_at = NOW()
+            WHERE id = $1
+        `, id, responseCode)
+		return err
+	}
+
+	status, next := model.WebhookDeliveryFailed, time.Now()
+	if retryAt != nil {
+		status, next = model.WebhookDeliveryPending, *retryAt
+	}
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+        SET status = $2, attempts = attempts + 1, response_code = $3,
+            last_error = $4, next_attempt_at = $5
+        WHERE id = $1
+    `, id, status, responseCode, deliverErr.Error(), next)
+	return err
+}
+
+// ListWebhookDeliveries возвращает журнал доставок подписки, новые сначала
+func (s *PostgresStorage) ListWebhookDeliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT id, webhook_id, event_id, event_type, status, attempts,
+               response_code, COALESCE(last_error, ''), created_at, delivered_at
+        FROM webhook_deliveries
+        WHERE webhook_id::text = $1
+        ORDER BY id DESC
+        LIMIT $2 OFFSET $3
+    `, webhookId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		if err := rows.Scan(&d.Id, &d.WebhookId, &d.EventId, &d.EventType, &d.Status, &d.Attempts,
+			&d.ResponseCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// ResponsibleOrganizations возвращает организации, за которые отвечает пользователь
+func (s *PostgresStorage) ResponsibleOrganizations(ctx context.Context, username string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT r.organization_id::text
+        FROM organization_responsible r
+        JOIN employee e ON e.id = r.user_id
+        WHERE e.username = $1
+    `, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, id)
+	}
+	return orgs, rows.Err()
+}
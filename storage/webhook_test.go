@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-tenders/model"
+)
+
+// Организация тендера не получает события черновика предложения;
+// организация-автор получает их всегда
+func TestEnqueueWebhookDeliveriesSkipsDraftBidsForTenderOrganization(t *testing.T) {
+	s, db := newFakeStorage(t)
+	event := model.Event{Id: 9, Type: model.EventBidCreated, AggregateId: "bid-1", Payload: []byte(`{"tenderId":"tender-1"}`)}
+	if err := s.EnqueueWebhookDeliveries(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	q := db.executed("INSERT INTO webhook_deliveries")[0]
+	if q.Args[1] != "bid.created" || q.Args[3] != "tender-1" || q.Args[4] != "bid-1" {
+		t.Fatalf("args = %v", q.Args)
+	}
+	tenderBranch, authorBranch, ok := strings.Cut(q.SQL, "UNION")
+	if !ok {
+		t.Fatalf("query = %s", q.SQL)
+	}
+	if !strings.Contains(tenderBranch, "$2 NOT LIKE 'bid.%' OR NOT EXISTS") ||
+		!strings.Contains(tenderBranch, "b.id::text = $5 AND b.status = '"+string(model.BidStatusCreated)+"'") {
+		t.Fatalf("tender organization branch does not skip draft bids: %s", tenderBranch)
+	}
+	if strings.Contains(authorBranch, "status") {
+		t.Fatalf("author organization branch is filtered by status: %s", authorBranch)
+	}
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware создает серверный спан на каждый запрос. Родитель берется из
+// заголовков traceparent/tracestate; имя спана — операция API из operation.
+// Контекст запроса заменяется контекстом со спаном, поэтому запросы к базе
+// становятся его дочерними спанами.
+func Middleware(operation func(ctx echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			parent := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			spanCtx, span := tracer().Start(parent, operation(ctx),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", ctx.Path()),
+					attribute.String("url.path", req.URL.Path),
+				),
+			)
+			defer span.End()
+			ctx.SetRequest(req.WithContext(spanCtx))
+
+			err := next(ctx)
+			if err != nil {
+				// Передаем ошибку обработчику Echo, чтобы записать итоговый код ответа
+				ctx.Error(err)
+				span.RecordError(err)
+			}
+
+			status := ctx.Response().Status
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-tenders/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryHook хук storage, создающий спан на каждый запрос и на начало и
+// завершение транзакции. Имя спана — метод PostgresStorage и вид обращения,
+// например "CreateBid exec".
+type QueryHook struct{}
+
+var _ storage.QueryHook = QueryHook{}
+
+func (QueryHook) BeforeQuery(ctx context.Context, q storage.QueryInfo) (context.Context, func(err error)) {
+	ctx, span := tracer().Start(ctx, q.Operation+" "+string(q.Kind),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", string(q.Kind)),
+			attribute.String("db.query.text", q.SQL),
+			attribute.String("code.function", q.Operation),
+		),
+	)
+	return ctx, func(err error) {
+		// Отсутствие строк — ожидаемый результат, а не сбой запроса
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName имя, под которым сервис создает спаны
+const instrumentationName = "go-tenders"
+
+// Defines values for Exporter.
+const (
+	ExporterNone   Exporter = "none"
+	ExporterStdout Exporter = "stdout"
+	ExporterOTLP   Exporter = "otlp"
+)
+
+// Exporter куда отправляются спаны
+type Exporter string
+
+// Config параметры трассировки
+type Config struct {
+	ServiceName string
+	Exporter    Exporter
+	// OTLPEndpoint адрес коллектора host:port для OTLP/HTTP; пусто — из OTEL_EXPORTER_OTLP_ENDPOINT
+	OTLPEndpoint string
+	// OTLPInsecure отправлять в коллектор без TLS
+	OTLPInsecure bool
+	// SampleRatio доля трассируемых корневых запросов, от 0 до 1
+	SampleRatio float64
+}
+
+// Setup настраивает глобальный провайдер трассировки и W3C-пропагацию
+// (traceparent, baggage). Возвращает функцию, выгружающую накопленные спаны
+// при остановке сервиса. С ExporterNone спаны не создаются, но контекст
+// трассы из входящих заголовков по-прежнему передается дальше.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// tracer возвращает трассировщик глобального провайдера; до Setup — no-op
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-tenders/storage"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans подменяет глобальный провайдер записывающим на время теста
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddlewareContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	e := echo.New()
+	var handlerSpan trace.SpanContext
+	handler := Middleware(func(echo.Context) string { return "GetTenders" })(func(ctx echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(ctx.Request().Context())
+		return echo.NewHTTPError(http.StatusInternalServerError, "boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err := handler(e.NewContext(req, httptest.NewRecorder())); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GetTenders" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span %q kind %v", span.Name(), span.SpanKind())
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span = %s, want the incoming traceparent", got)
+	}
+	if span.SpanContext().TraceID() != handlerSpan.TraceID() || span.SpanContext().SpanID() != handlerSpan.SpanID() {
+		t.Error("handler context does not carry the request span")
+	}
+	if got := spanAttr(span, "http.response.status_code").AsInt64(); got != http.StatusInternalServerError {
+		t.Errorf("status code attribute = %d", got)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error for 5xx", span.Status())
+	}
+}
+
+func TestQueryHookSpanStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"success", nil, codes.Unset},
+		{"no rows is not a failure", sql.ErrNoRows, codes.Unset},
+		{"query error", errors.New("connection reset"), codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			parentCtx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+			_, done := QueryHook{}.BeforeQuery(parentCtx, storage.QueryInfo{
+				Operation: "CreateBid", Kind: storage.QueryKindExec, SQL: "INSERT INTO bids",
+			})
+			done(tt.err)
+			parent.End()
+
+			span := recorder.Ended()[0]
+			if span.Name() != "CreateBid exec" {
+				t.Errorf("span name = %q", span.Name())
+			}
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Error("query span is not a child of the request span")
+			}
+			if got := spanAttr(span, "db.query.text").AsString(); got != "INSERT INTO bids" {
+				t.Errorf("db.query.text = %q", got)
+			}
+			if span.Status().Code != tt.want {
+				t.Errorf("status = %v, want %v", span.Status().Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-tenders/model"
+)
+
+// Sender отправляет подписанные запросы доставки
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender конструктор Sender; nil client заменяется NewClient с таймаутом 10 секунд
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = NewClient(10 * time.Second)
+	}
+	return &Sender{client: client, now: time.Now}
+}
+
+// Send отправляет доставку и возвращает HTTP-код ответа. Успехом считается любой 2xx.
+func (s *Sender) Send(ctx context.Context, d model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-tenders-webhook/1.0")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.Id, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, s.now(), d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Дочитываем тело, чтобы соединение вернулось в пул
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса доставки
+const (
+	HeaderSignature = "X-Tenders-Signature"
+	HeaderEvent     = "X-Tenders-Event"
+	HeaderDelivery  = "X-Tenders-Delivery"
+)
+
+// ErrInvalidSignature подпись отсутствует, не совпадает или устарела
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// NewSecret генерирует секрет подписки
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign возвращает значение заголовка подписи "t=<unix>,v1=<hex>", где v1 —
+// HMAC-SHA256 от "<unix>.<body>". Метка времени защищает от повтора запроса.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, computeMAC(secret, ts, body))
+}
+
+// Verify проверяет заголовок подписи; tolerance ограничивает возраст запроса.
+// Предназначена для получателей вебхуков.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, mac string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			mac = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || mac == "" {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(mac), []byte(computeMAC(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeMAC(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
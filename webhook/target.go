@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenTarget адрес получателя во внутренней сети или на самом сервере
+var ErrForbiddenTarget = errors.New("webhook target address is not allowed")
+
+// forbiddenPrefixes диапазоны, не покрытые методами netip.Addr: служебные,
+// CGNAT, тестовые и трансляция IPv6 в IPv4
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// AllowedAddr сообщает, можно ли отправлять запросы на адрес: запрещены loopback,
+// частные сети (RFC 1918, fc00::/7), link-local (включая 169.254.169.254 облачных
+// метаданных), multicast и служебные диапазоны
+func AllowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, p := range forbiddenPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateURL проверяет адрес получателя при регистрации: абсолютный http(s) URL,
+// все адреса хоста разрешены AllowedAddr. Отправка проверяет адрес еще раз
+// при соединении, поэтому смена DNS после регистрации не обходит проверку.
+func ValidateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenTarget
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !AllowedAddr(addr) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve url host %q", host)
+	}
+	for _, addr := range addrs {
+		if !AllowedAddr(addr) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
+
+// safeControl проверяет адрес функцией allow уже после разрешения имени, перед соединением
+func safeControl(allow func(netip.Addr) bool) func(network, address string, _ syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		ap, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if !allow(ap.Addr()) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+}
+
+// NewClient HTTP-клиент доставки, который не соединяется с запрещенными адресами,
+// в том числе после перенаправлений. Прокси из окружения не используется: иначе
+// проверялся бы адрес прокси, а не получателя.
+func NewClient(timeout time.Duration) *http.Client {
+	return newClient(timeout, AllowedAddr)
+}
+
+// newClient клиент доставки с проверкой адреса allow; тесты разрешают loopback
+func newClient(timeout time.Duration, allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: safeControl(allow)}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
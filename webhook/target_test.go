@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"go-tenders/model"
+)
+
+func TestAllowedAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := AllowedAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Fatalf("AllowedAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		forbidden bool
+		invalid   bool
+	}{
+		{url: "https://93.184.216.34/hook"},
+		{url: "http://127.0.0.1:8080/hook", forbidden: true},
+		{url: "http://[::1]/hook", forbidden: true},
+		{url: "http://169.254.169.254/latest/meta-data/", forbidden: true},
+		{url: "http://10.0.0.5/hook", forbidden: true},
+		{url: "http://localhost/hook", forbidden: true},
+		{url: "http://api.localhost./hook", forbidden: true},
+		{url: "ftp://93.184.216.34/hook", invalid: true},
+		{url: "/relative", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(context.Background(), tt.url)
+			switch {
+			case tt.forbidden:
+				if !errors.Is(err, ErrForbiddenTarget) {
+					t.Fatalf("ValidateURL = %v, want ErrForbiddenTarget", err)
+				}
+			case tt.invalid:
+				if err == nil || errors.Is(err, ErrForbiddenTarget) {
+					t.Fatalf("ValidateURL = %v, want invalid URL error", err)
+				}
+			case err != nil:
+				t.Fatalf("ValidateURL = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// Адрес проверяется при соединении: зарегистрированный хост мог сменить DNS
+func TestSenderRefusesForbiddenAddress(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	_, err := NewSender(nil).Send(context.Background(), model.WebhookDelivery{
+		Id: 1, Url: receiver.URL, Payload: []byte(`{}`), Secret: "s",
+	})
+	if !errors.Is(err, ErrForbiddenTarget) {
+		t.Fatalf("Send() = %v, want ErrForbiddenTarget", err)
+	}
+	if called {
+		t.Fatal("request reached a loopback receiver")
+	}
+}
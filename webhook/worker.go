@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/outbox"
+)
+
+// Store хранилище подписок и очереди доставок; реализуется storage.PostgresStorage
+type Store interface {
+	EnqueueWebhookDeliveries(ctx context.Context, event model.Event) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, id int64, responseCode int, deliverErr error, retryAt *time.Time) error
+}
+
+// Sink получатель outbox: ставит событие в очередь доставки подходящим подпискам.
+// Отправка выполняется Worker, поэтому медленный получатель не задерживает relay.
+type Sink struct {
+	store Store
+}
+
+// NewSink конструктор Sink
+func NewSink(store Store) *Sink {
+	return &Sink{store: store}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+func (s *Sink) Deliver(ctx context.Context, event model.Event) error {
+	return s.store.EnqueueWebhookDeliveries(ctx, event)
+}
+
+// Worker отправляет доставки из очереди с повторами и экспоненциальной задержкой
+type Worker struct {
+	store  Store
+	sender *Sender
+	logger outbox.Logger
+	config outbox.Config
+}
+
+// NewWorker конструктор Worker; параметры опроса и повторов те же, что у outbox.Relay
+func NewWorker(store Store, sender *Sender, logger outbox.Logger, cfg outbox.Config) *Worker {
+	return &Worker{
+		store:  store,
+		sender: sender,
+		logger: logger,
+		config: cfg,
+	}
+}
+
+// Run обрабатывает очередь до отмены ctx
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		n, err := w.ProcessBatch(ctx)
+		if err != nil {
+			w.logger.Error("webhook worker error: ", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// ProcessBatch отправляет одну пачку доставок, возвращает их число. Доставки
+// отправляются параллельно и не дольше Lease: иначе резерв истек бы раньше,
+// чем дошла очередь до последних, и их забрал бы другой экземпляр.
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	deliveries, err := w.store.ClaimWebhookDeliveries(ctx, w.config.BatchSize, w.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	type result struct {
+		code int
+		err  error
+	}
+	results := make([]result, len(deliveries))
+	sendCtx, cancel := context.WithTimeout(ctx, w.config.Lease)
+	var wg sync.WaitGroup
+	for i, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, err := w.sender.Send(sendCtx, d)
+			results[i] = result{code: code, err: err}
+		}()
+	}
+	wg.Wait()
+	cancel()
+
+	for i, d := range deliveries {
+		code, sendErr := results[i].code, results[i].err
+
+		var retryAt *time.Time
+		attempt := d.Attempts + 1
+		if sendErr != nil {
+			w.logger.Error("webhook delivery ", d.Id, " to ", d.Url, " failed: ", sendErr)
+			if attempt < w.config.MaxAttempts {
+				t := time.Now().Add(outbox.Backoff(w.config.BaseBackoff, w.config.MaxBackoff, attempt))
+				retryAt = &t
+			}
+		}
+		if err := w.store.RecordWebhookAttempt(ctx, d.Id, code, sendErr, retryAt); err != nil {
+			w.logger.Error("webhook record attempt error: ", err)
+		}
+	}
+	return len(deliveries), nil
+}
@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"go-tenders/model"
+	"go-tenders/outbox"
+)
+
+type attempt struct {
+	id      int64
+	code    int
+	err     error
+	retryAt *time.Time
+}
+
+type fakeStore struct {
+	mu         sync.Mutex
+	deliveries []model.WebhookDelivery
+	attempts   []attempt
+}
+
+func (f *fakeStore) EnqueueWebhookDeliveries(ctx context.Context, event model.Event) error {
+	return nil
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	claimed := f.deliveries
+	f.deliveries = nil
+	return claimed, nil
+}
+
+func (f *fakeStore) RecordWebhookAttempt(ctx context.Context, id int64, responseCode int, deliverErr error, retryAt *time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts = append(f.attempts, attempt{id: id, code: responseCode, err: deliverErr, retryAt: retryAt})
+	return nil
+}
+
+// newTestSender отправляет через клиент NewClient, которому дополнительно
+// разрешен loopback-адрес тестового получателя
+func newTestSender() *Sender {
+	return NewSender(newClient(10*time.Second, func(addr netip.Addr) bool {
+		return addr.IsLoopback() || AllowedAddr(addr)
+	}))
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Error(args ...interface{}) {}
+
+func TestWorkerDeliversSignedPayload(t *testing.T) {
+	const secret = "test-secret"
+	payload := []byte(`{"id":"550e8400-e29b-41d4-a716-446655440000","status":"Closed"}`)
+
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify(secret, r.Header.Get(HeaderSignature), body, 5*time.Minute, time.Now()); err != nil {
+			t.Errorf("signature verification failed: %v", err)
+		}
+		if string(body) != string(payload) {
+			t.Errorf("unexpected body %s", body)
+		}
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := &fakeStore{deliveries: []model.WebhookDelivery{{
+		Id:        7,
+		EventType: model.EventTenderClosed,
+		Payload:   payload,
+		Url:       receiver.URL,
+		Secret:    secret,
+	}}}
+	worker := NewWorker(store, newTestSender(), nopLogger{}, outbox.DefaultConfig)
+
+	n, err := worker.ProcessBatch(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("ProcessBatch() = %d, %v; want 1, nil", n, err)
+	}
+
+	r := <-received
+	if got := r.Header.Get(HeaderEvent); got != string(model.EventTenderClosed) {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, model.EventTenderClosed)
+	}
+	if got := r.Header.Get(HeaderDelivery); got != "7" {
+		t.Errorf("%s = %q, want 7", HeaderDelivery, got)
+	}
+	if len(store.attempts) != 1 || store.attempts[0].err != nil || store.attempts[0].code != http.StatusNoContent {
+		t.Fatalf("unexpected attempts %+v", store.attempts)
+	}
+}
+
+func TestWorkerSchedulesRetryWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	cfg := outbox.DefaultConfig
+	cfg.MaxAttempts = 3
+
+	store := &fakeStore{deliveries: []model.WebhookDelivery{
+		{Id: 1, Attempts: 0, Payload: []byte(`{}`), Url: receiver.URL, Secret: "s"},
+		{Id: 2, Attempts: 2, Payload: []byte(`{}`), Url: receiver.URL, Secret: "s"},
+	}}
+	worker := NewWorker(store, newTestSender(), nopLogger{}, cfg)
+
+	before := time.Now()
+	if _, err := worker.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("ProcessBatch() error: %v", err)
+	}
+	if len(store.attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(store.attempts))
+	}
+
+	first := store.attempts[0]
+	if first.err == nil || first.code != http.StatusServiceUnavailable {
+		t.Errorf("first attempt = %+v, want 503 error", first)
+	}
+	if first.retryAt == nil || first.retryAt.Before(before.Add(cfg.BaseBackoff)) {
+		t.Errorf("first attempt retryAt = %v, want at least %v later", first.retryAt, cfg.BaseBackoff)
+	}
+
+	// Третья неудача при MaxAttempts = 3 исчерпывает попытки
+	if last := store.attempts[1]; last.retryAt != nil {
+		t.Errorf("last attempt retryAt = %v, want nil", last.retryAt)
+	}
+}
+
+// Пачка отправляется параллельно и укладывается в резерв, даже если каждый
+// получатель отвечает почти весь Lease
+func TestWorkerSendsBatchWithinLease(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	cfg := outbox.DefaultConfig
+	cfg.Lease = 2 * delay
+	store := &fakeStore{}
+	for i := range 5 {
+		store.deliveries = append(store.deliveries, model.WebhookDelivery{Id: int64(i + 1), Payload: []byte(`{}`), Url: receiver.URL, Secret: "s"})
+	}
+	worker := NewWorker(store, newTestSender(), nopLogger{}, cfg)
+
+	start := time.Now()
+	if _, err := worker.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("ProcessBatch() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= cfg.Lease {
+		t.Fatalf("batch took %v, longer than lease %v", elapsed, cfg.Lease)
+	}
+	for i, a := range store.attempts {
+		if a.id != int64(i+1) || a.err != nil {
+			t.Fatalf("attempt %d = %+v, want delivered id %d", i, a, i+1)
+		}
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	now := time.Now()
+	header := Sign("secret", now, []byte(`{"a":1}`))
+
+	if err := Verify("secret", header, []byte(`{"a":1}`), time.Minute, now); err != nil {
+		t.Fatalf("Verify() on original body: %v", err)
+	}
+	if err := Verify("secret", header, []byte(`{"a":2}`), time.Minute, now); err != ErrInvalidSignature {
+		t.Errorf("Verify() on tampered body = %v, want ErrInvalidSignature", err)
+	}
+	if err := Verify("secret", header, []byte(`{"a":1}`), time.Minute, now.Add(2*time.Minute)); err != ErrInvalidSignature {
+		t.Errorf("Verify() on stale signature = %v, want ErrInvalidSignature", err)
+	}
+}